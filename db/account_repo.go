@@ -0,0 +1,221 @@
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"spending-tracker/models"
+)
+
+func GetAccounts(ctx context.Context, householdID int64) ([]models.Account, error) {
+	rows, err := conn(ctx).Query(ctx, `
+		SELECT id, name, account_type, opening_balance, created_at
+		FROM accounts
+		WHERE household_id = $1
+		ORDER BY name
+	`, householdID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var accounts []models.Account
+	for rows.Next() {
+		var a models.Account
+		if err := rows.Scan(&a.ID, &a.Name, &a.Type, &a.OpeningBalance, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, a)
+	}
+	return accounts, rows.Err()
+}
+
+func GetAccount(ctx context.Context, householdID, id int64) (*models.Account, error) {
+	var a models.Account
+	err := conn(ctx).QueryRow(ctx, `
+		SELECT id, name, account_type, opening_balance, created_at
+		FROM accounts
+		WHERE household_id = $1 AND id = $2
+	`, householdID, id).Scan(&a.ID, &a.Name, &a.Type, &a.OpeningBalance, &a.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func CreateAccount(ctx context.Context, householdID int64, account models.Account) (*models.Account, error) {
+	var a models.Account
+	err := conn(ctx).QueryRow(ctx, `
+		INSERT INTO accounts (household_id, name, account_type, opening_balance)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, name, account_type, opening_balance, created_at
+	`, householdID, account.Name, account.Type, account.OpeningBalance,
+	).Scan(&a.ID, &a.Name, &a.Type, &a.OpeningBalance, &a.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// UpdateAccount overwrites an account's details, reporting whether it existed
+func UpdateAccount(ctx context.Context, householdID int64, account models.Account) (bool, error) {
+	tag, err := conn(ctx).Exec(ctx, `
+		UPDATE accounts SET name = $3, account_type = $4, opening_balance = $5
+		WHERE household_id = $1 AND id = $2
+	`, householdID, account.ID, account.Name, account.Type, account.OpeningBalance)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// DeleteAccount removes an account, reporting whether it existed; the
+// database rejects accounts still used by expenses, income or transfers
+func DeleteAccount(ctx context.Context, householdID, id int64) (bool, error) {
+	tag, err := conn(ctx).Exec(ctx, `DELETE FROM accounts WHERE household_id = $1 AND id = $2`, householdID, id)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// GetAccountBalances returns each account's balance going into a period,
+// what moved through it during the period, and its balance coming out.
+// Later periods are left out.
+func GetAccountBalances(ctx context.Context, householdID int64, year, month int) ([]models.AccountBalance, error) {
+	rows, err := conn(ctx).Query(ctx, `
+		WITH movements AS (
+			SELECT account_id, year, month, amount AS income, 0 AS spent, 0 AS moved_in, 0 AS moved_out
+			FROM income WHERE household_id = $1 AND account_id IS NOT NULL
+			UNION ALL
+			SELECT account_id, year, month, 0, amount, 0, 0
+			FROM expenses WHERE household_id = $1 AND account_id IS NOT NULL
+			UNION ALL
+			SELECT to_account_id, year, month, 0, 0, amount, 0
+			FROM transfers WHERE household_id = $1
+			UNION ALL
+			SELECT from_account_id, year, month, 0, 0, 0, amount
+			FROM transfers WHERE household_id = $1
+		)
+		SELECT a.id, a.name, a.account_type, a.opening_balance, a.created_at,
+		       a.opening_balance + COALESCE(SUM(m.income - m.spent + m.moved_in - m.moved_out)
+		           FILTER (WHERE (m.year, m.month) < ($2, $3)), 0),
+		       COALESCE(SUM(m.income) FILTER (WHERE m.year = $2 AND m.month = $3), 0),
+		       COALESCE(SUM(m.spent) FILTER (WHERE m.year = $2 AND m.month = $3), 0),
+		       COALESCE(SUM(m.moved_in) FILTER (WHERE m.year = $2 AND m.month = $3), 0),
+		       COALESCE(SUM(m.moved_out) FILTER (WHERE m.year = $2 AND m.month = $3), 0)
+		FROM accounts a
+		LEFT JOIN movements m ON m.account_id = a.id
+		WHERE a.household_id = $1
+		GROUP BY a.id
+		ORDER BY a.name
+	`, householdID, year, month)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var balances []models.AccountBalance
+	for rows.Next() {
+		var a models.Account
+		var opening, income, spent, in, out float64
+		if err := rows.Scan(&a.ID, &a.Name, &a.Type, &a.OpeningBalance, &a.CreatedAt,
+			&opening, &income, &spent, &in, &out); err != nil {
+			return nil, err
+		}
+		balances = append(balances, models.NewAccountBalance(a, opening, income, spent, in, out))
+	}
+	return balances, rows.Err()
+}
+
+// GetIncomeAccountID returns the account a period's income was paid into, if any
+func GetIncomeAccountID(ctx context.Context, householdID int64, year, month int) (*int64, error) {
+	var accountID *int64
+	err := conn(ctx).QueryRow(ctx, `
+		SELECT account_id FROM income
+		WHERE household_id = $1 AND year = $2 AND month = $3
+	`, householdID, year, month).Scan(&accountID)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	return accountID, err
+}
+
+// SetIncomeAccount records which account a period's income was paid into
+func SetIncomeAccount(ctx context.Context, householdID int64, year, month int, accountID *int64) error {
+	_, err := conn(ctx).Exec(ctx, `
+		INSERT INTO income (household_id, year, month, amount, account_id)
+		VALUES ($1, $2, $3, 0, $4)
+		ON CONFLICT (household_id, year, month)
+		DO UPDATE SET account_id = $4, updated_at = NOW()
+	`, householdID, year, month, accountID)
+	return err
+}
+
+// transferSelect selects a transfer with both accounts; pair with scanTransfer
+const transferSelect = `
+		SELECT t.id, t.amount, t.year, t.month, t.note, t.created_at,
+		       f.id, f.name, f.account_type, f.opening_balance, f.created_at,
+		       tt.id, tt.name, tt.account_type, tt.opening_balance, tt.created_at
+		FROM transfers t
+		JOIN accounts f ON t.from_account_id = f.id
+		JOIN accounts tt ON t.to_account_id = tt.id`
+
+func scanTransfer(row pgx.Row) (models.Transfer, error) {
+	var t models.Transfer
+	err := row.Scan(&t.ID, &t.Amount, &t.Year, &t.Month, &t.Note, &t.CreatedAt,
+		&t.FromAccount.ID, &t.FromAccount.Name, &t.FromAccount.Type, &t.FromAccount.OpeningBalance, &t.FromAccount.CreatedAt,
+		&t.ToAccount.ID, &t.ToAccount.Name, &t.ToAccount.Type, &t.ToAccount.OpeningBalance, &t.ToAccount.CreatedAt,
+	)
+	return t, err
+}
+
+func GetTransfers(ctx context.Context, householdID int64) ([]models.Transfer, error) {
+	rows, err := conn(ctx).Query(ctx, transferSelect+`
+		WHERE t.household_id = $1
+		ORDER BY t.year DESC, t.month DESC, t.created_at DESC
+	`, householdID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var transfers []models.Transfer
+	for rows.Next() {
+		t, err := scanTransfer(rows)
+		if err != nil {
+			return nil, err
+		}
+		transfers = append(transfers, t)
+	}
+	return transfers, rows.Err()
+}
+
+func GetTransfer(ctx context.Context, householdID, id int64) (*models.Transfer, error) {
+	t, err := scanTransfer(conn(ctx).QueryRow(ctx, transferSelect+`
+		WHERE t.household_id = $1 AND t.id = $2
+	`, householdID, id))
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func CreateTransfer(ctx context.Context, householdID int64, fromID, toID int64, amount float64, year, month int, note string) (int64, error) {
+	var id int64
+	err := conn(ctx).QueryRow(ctx, `
+		INSERT INTO transfers (household_id, from_account_id, to_account_id, amount, year, month, note)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`, householdID, fromID, toID, amount, year, month, note).Scan(&id)
+	return id, err
+}
+
+// DeleteTransfer removes a transfer, reporting whether it existed
+func DeleteTransfer(ctx context.Context, householdID, id int64) (bool, error) {
+	tag, err := conn(ctx).Exec(ctx, `DELETE FROM transfers WHERE household_id = $1 AND id = $2`, householdID, id)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
@@ -0,0 +1,72 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"spending-tracker/models"
+)
+
+func GetAPITokens(ctx context.Context, userID int64) ([]models.APIToken, error) {
+	rows, err := conn(ctx).Query(ctx, `
+		SELECT id, user_id, name, scope, expires_at, last_used_at, created_at
+		FROM api_tokens
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tokens []models.APIToken
+	for rows.Next() {
+		var t models.APIToken
+		if err := rows.Scan(&t.ID, &t.UserID, &t.Name, &t.Scope, &t.ExpiresAt, &t.LastUsedAt, &t.CreatedAt); err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, t)
+	}
+	return tokens, rows.Err()
+}
+
+func CreateAPIToken(ctx context.Context, userID int64, name, tokenHash string, scope models.TokenScope, expiresAt *time.Time) (*models.APIToken, error) {
+	var t models.APIToken
+	err := conn(ctx).QueryRow(ctx, `
+		INSERT INTO api_tokens (user_id, name, token_hash, scope, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, user_id, name, scope, expires_at, last_used_at, created_at
+	`, userID, name, tokenHash, scope, expiresAt).Scan(&t.ID, &t.UserID, &t.Name, &t.Scope, &t.ExpiresAt, &t.LastUsedAt, &t.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// GetAPITokenUser returns the owner and scope of an unexpired token and records the use
+func GetAPITokenUser(ctx context.Context, tokenHash string) (*models.User, models.TokenScope, error) {
+	var u models.User
+	var scope models.TokenScope
+	err := conn(ctx).QueryRow(ctx, `
+		UPDATE api_tokens t
+		SET last_used_at = NOW()
+		FROM users u
+		WHERE t.token_hash = $1
+		  AND (t.expires_at IS NULL OR t.expires_at > NOW())
+		  AND u.id = t.user_id
+		RETURNING u.id, u.username, u.password_hash, u.is_admin, u.created_at, t.scope
+	`, tokenHash).Scan(&u.ID, &u.Username, &u.PasswordHash, &u.IsAdmin, &u.CreatedAt, &scope)
+	if err != nil {
+		return nil, "", err
+	}
+	return &u, scope, nil
+}
+
+// DeleteAPIToken revokes one of a user's tokens, reporting whether it existed
+func DeleteAPIToken(ctx context.Context, userID, id int64) (bool, error) {
+	tag, err := conn(ctx).Exec(ctx, `DELETE FROM api_tokens WHERE user_id = $1 AND id = $2`, userID, id)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
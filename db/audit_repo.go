@@ -0,0 +1,82 @@
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"spending-tracker/models"
+)
+
+// auditSelect selects an audit entry with its actor's username; pair with scanAuditEntry
+const auditSelect = `
+		SELECT a.id, a.user_id, u.username, a.entity_type, a.entity_id, a.action,
+		       a.before, a.after, a.undone_at, a.created_at
+		FROM audit_log a
+		LEFT JOIN users u ON u.id = a.user_id`
+
+func scanAuditEntry(row pgx.Row) (models.AuditEntry, error) {
+	var e models.AuditEntry
+	err := row.Scan(&e.ID, &e.UserID, &e.Username, &e.EntityType, &e.EntityID, &e.Action,
+		&e.Before, &e.After, &e.UndoneAt, &e.CreatedAt)
+	return e, err
+}
+
+// CreateAuditEntry records a change; before and after are JSON or nil
+func CreateAuditEntry(ctx context.Context, householdID int64, userID *int64, entity models.AuditEntity, entityID *int64, action models.AuditAction, before, after []byte) (*models.AuditEntry, error) {
+	var id int64
+	err := conn(ctx).QueryRow(ctx, `
+		INSERT INTO audit_log (household_id, user_id, entity_type, entity_id, action, before, after)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`, householdID, userID, entity, entityID, action, before, after).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+	return GetAuditEntry(ctx, householdID, id)
+}
+
+// GetAuditEntries returns a household's most recent changes first
+func GetAuditEntries(ctx context.Context, householdID int64, limit, offset int) ([]models.AuditEntry, error) {
+	rows, err := conn(ctx).Query(ctx, auditSelect+`
+		WHERE a.household_id = $1
+		ORDER BY a.created_at DESC, a.id DESC
+		LIMIT $2 OFFSET $3
+	`, householdID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []models.AuditEntry
+	for rows.Next() {
+		e, err := scanAuditEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+func GetAuditEntry(ctx context.Context, householdID, id int64) (*models.AuditEntry, error) {
+	e, err := scanAuditEntry(conn(ctx).QueryRow(ctx, auditSelect+`
+		WHERE a.household_id = $1 AND a.id = $2
+	`, householdID, id))
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// MarkAuditEntryUndone flags an entry as undone, reporting false if it already was
+func MarkAuditEntryUndone(ctx context.Context, householdID, id int64) (bool, error) {
+	tag, err := conn(ctx).Exec(ctx, `
+		UPDATE audit_log
+		SET undone_at = NOW()
+		WHERE household_id = $1 AND id = $2 AND undone_at IS NULL
+	`, householdID, id)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
@@ -5,12 +5,13 @@ import (
 	"spending-tracker/models"
 )
 
-func GetAllCategories(ctx context.Context) ([]models.Category, error) {
-	rows, err := Pool.Query(ctx, `
-		SELECT id, name, color, created_at
+func GetAllCategories(ctx context.Context, householdID int64) ([]models.Category, error) {
+	rows, err := conn(ctx).Query(ctx, `
+		SELECT id, name, color, parent_id, budget, archived_at, created_at
 		FROM categories
+		WHERE household_id = $1
 		ORDER BY name
-	`)
+	`, householdID)
 	if err != nil {
 		return nil, err
 	}
@@ -19,7 +20,7 @@ func GetAllCategories(ctx context.Context) ([]models.Category, error) {
 	var categories []models.Category
 	for rows.Next() {
 		var c models.Category
-		if err := rows.Scan(&c.ID, &c.Name, &c.Color, &c.CreatedAt); err != nil {
+		if err := rows.Scan(&c.ID, &c.Name, &c.Color, &c.ParentID, &c.Budget, &c.ArchivedAt, &c.CreatedAt); err != nil {
 			return nil, err
 		}
 		categories = append(categories, c)
@@ -27,42 +28,201 @@ func GetAllCategories(ctx context.Context) ([]models.Category, error) {
 	return categories, rows.Err()
 }
 
-func GetCategoryByID(ctx context.Context, id int64) (*models.Category, error) {
+func GetCategoryByID(ctx context.Context, householdID, id int64) (*models.Category, error) {
 	var c models.Category
-	err := Pool.QueryRow(ctx, `
-		SELECT id, name, color, created_at
+	err := conn(ctx).QueryRow(ctx, `
+		SELECT id, name, color, parent_id, budget, archived_at, created_at
 		FROM categories
-		WHERE id = $1
-	`, id).Scan(&c.ID, &c.Name, &c.Color, &c.CreatedAt)
+		WHERE household_id = $1 AND id = $2
+	`, householdID, id).Scan(&c.ID, &c.Name, &c.Color, &c.ParentID, &c.Budget, &c.ArchivedAt, &c.CreatedAt)
 	if err != nil {
 		return nil, err
 	}
 	return &c, nil
 }
 
-func CreateCategory(ctx context.Context, name, color string) (*models.Category, error) {
+func CreateCategory(ctx context.Context, householdID int64, name, color string, parentID *int64, budget *float64) (*models.Category, error) {
 	var c models.Category
-	err := Pool.QueryRow(ctx, `
-		INSERT INTO categories (name, color)
-		VALUES ($1, $2)
-		RETURNING id, name, color, created_at
-	`, name, color).Scan(&c.ID, &c.Name, &c.Color, &c.CreatedAt)
+	err := conn(ctx).QueryRow(ctx, `
+		INSERT INTO categories (household_id, name, color, parent_id, budget)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, name, color, parent_id, budget, archived_at, created_at
+	`, householdID, name, color, parentID, budget).Scan(&c.ID, &c.Name, &c.Color, &c.ParentID, &c.Budget, &c.ArchivedAt, &c.CreatedAt)
 	if err != nil {
 		return nil, err
 	}
 	return &c, nil
 }
 
-func UpdateCategory(ctx context.Context, id int64, name, color string) error {
-	_, err := Pool.Exec(ctx, `
+func UpdateCategory(ctx context.Context, householdID, id int64, name, color string) error {
+	_, err := conn(ctx).Exec(ctx, `
 		UPDATE categories
-		SET name = $2, color = $3
-		WHERE id = $1
-	`, id, name, color)
+		SET name = $3, color = $4
+		WHERE household_id = $1 AND id = $2
+	`, householdID, id, name, color)
 	return err
 }
 
-func DeleteCategory(ctx context.Context, id int64) error {
-	_, err := Pool.Exec(ctx, `DELETE FROM categories WHERE id = $1`, id)
+func UpdateCategoryParent(ctx context.Context, householdID, id int64, parentID *int64) error {
+	_, err := conn(ctx).Exec(ctx, `
+		UPDATE categories
+		SET parent_id = $3
+		WHERE household_id = $1 AND id = $2
+	`, householdID, id, parentID)
 	return err
 }
+
+func UpdateCategoryBudget(ctx context.Context, householdID, id int64, budget *float64) error {
+	_, err := conn(ctx).Exec(ctx, `
+		UPDATE categories
+		SET budget = $3
+		WHERE household_id = $1 AND id = $2
+	`, householdID, id, budget)
+	return err
+}
+
+func HasSubcategories(ctx context.Context, householdID, id int64) (bool, error) {
+	var exists bool
+	err := conn(ctx).QueryRow(ctx, `
+		SELECT EXISTS(SELECT 1 FROM categories WHERE household_id = $1 AND parent_id = $2)
+	`, householdID, id).Scan(&exists)
+	return exists, err
+}
+
+// ArchiveCategory hides a category and its subcategories from pickers
+func ArchiveCategory(ctx context.Context, householdID, id int64) error {
+	_, err := conn(ctx).Exec(ctx, `
+		UPDATE categories
+		SET archived_at = NOW()
+		WHERE household_id = $1 AND (id = $2 OR parent_id = $2) AND archived_at IS NULL
+	`, householdID, id)
+	return err
+}
+
+// UnarchiveCategory restores a category, along with its parent if that is archived too
+func UnarchiveCategory(ctx context.Context, householdID, id int64) error {
+	_, err := conn(ctx).Exec(ctx, `
+		UPDATE categories
+		SET archived_at = NULL
+		WHERE household_id = $1
+		  AND (id = $2 OR id = (SELECT parent_id FROM categories WHERE id = $2))
+	`, householdID, id)
+	return err
+}
+
+func GetCategoryUsage(ctx context.Context, householdID, id int64) (models.CategoryUsage, error) {
+	var usage models.CategoryUsage
+	err := conn(ctx).QueryRow(ctx, `
+		SELECT
+			(SELECT COUNT(*) FROM expenses e
+			 WHERE e.household_id = $1
+			   AND (e.category_id = $2 OR EXISTS (
+			       SELECT 1 FROM expense_splits s WHERE s.expense_id = e.id AND s.category_id = $2
+			   ))),
+			(SELECT COUNT(*) FROM recurring_expenses WHERE household_id = $1 AND category_id = $2)
+	`, householdID, id).Scan(&usage.Expenses, &usage.RecurringTemplates)
+	return usage, err
+}
+
+// DeleteCategory removes an unused category. Subcategories move to the top level;
+// categories still referenced by expenses or templates are rejected by the database.
+func DeleteCategory(ctx context.Context, householdID, id int64) error {
+	_, err := conn(ctx).Exec(ctx, `DELETE FROM categories WHERE household_id = $1 AND id = $2`, householdID, id)
+	return err
+}
+
+// MergeCategories moves every expense, split line, recurring template and subcategory from
+// sourceID to targetID, then deletes the source, all in one transaction
+func MergeCategories(ctx context.Context, householdID, sourceID, targetID int64) error {
+	tx, err := conn(ctx).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE expenses SET category_id = $3, updated_at = NOW()
+		WHERE household_id = $1 AND category_id = $2
+	`, householdID, sourceID, targetID); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE expense_splits SET category_id = $3
+		WHERE category_id = $2
+		  AND expense_id IN (SELECT id FROM expenses WHERE household_id = $1)
+	`, householdID, sourceID, targetID); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE recurring_expenses SET category_id = $3, updated_at = NOW()
+		WHERE household_id = $1 AND category_id = $2
+	`, householdID, sourceID, targetID); err != nil {
+		return err
+	}
+
+	// A subcategory absorbing its own parent takes the parent's place
+	if _, err := tx.Exec(ctx, `
+		UPDATE categories
+		SET parent_id = (SELECT parent_id FROM categories WHERE id = $2)
+		WHERE household_id = $1 AND id = $3 AND parent_id = $2
+	`, householdID, sourceID, targetID); err != nil {
+		return err
+	}
+
+	// Remaining subcategories follow the target if it can be a parent
+	if _, err := tx.Exec(ctx, `
+		UPDATE categories
+		SET parent_id = CASE
+			WHEN (SELECT parent_id FROM categories WHERE id = $3) IS NULL THEN $3
+			ELSE NULL
+		END
+		WHERE household_id = $1 AND parent_id = $2
+	`, householdID, sourceID, targetID); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM categories WHERE household_id = $1 AND id = $2`, householdID, sourceID); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// RestoreCategory re-inserts a deleted category with its original ID, under
+// its parent if that still exists, and moves its former subcategories back
+// beneath it if they are still at the top level
+func RestoreCategory(ctx context.Context, householdID int64, c models.Category) error {
+	tx, err := conn(ctx).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO categories (id, household_id, name, color, parent_id, budget, archived_at, created_at)
+		VALUES ($1, $2, $3, $4,
+		        (SELECT id FROM categories WHERE household_id = $2 AND id = $5 AND parent_id IS NULL),
+		        $6, $7, $8)
+	`, c.ID, householdID, c.Name, c.Color, c.ParentID, c.Budget, c.ArchivedAt, c.CreatedAt); err != nil {
+		return err
+	}
+
+	childIDs := make([]int64, len(c.Children))
+	for i, child := range c.Children {
+		childIDs[i] = child.ID
+	}
+	if len(childIDs) > 0 {
+		if _, err := tx.Exec(ctx, `
+			UPDATE categories
+			SET parent_id = $2
+			WHERE household_id = $1 AND id = ANY($3) AND parent_id IS NULL
+			  AND NOT EXISTS (SELECT 1 FROM categories sub WHERE sub.parent_id = categories.id)
+		`, householdID, c.ID, childIDs); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
@@ -4,8 +4,11 @@ import (
 	"context"
 	"embed"
 	"fmt"
+	"io/fs"
 	"os"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -14,6 +17,41 @@ var migrationsFS embed.FS
 
 var Pool *pgxpool.Pool
 
+// querier is what the repositories run statements on: the pool, or the
+// transaction InTx is running
+type querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+type txKey struct{}
+
+// conn returns the transaction ctx carries, or the pool outside one
+func conn(ctx context.Context) querier {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx
+	}
+	return Pool
+}
+
+// InTx runs fn in one transaction, committing only if it returns nil. Every
+// repository call fn makes with the context it is given takes part, and
+// their own transactions become savepoints within it.
+func InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	tx, err := conn(ctx).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
 func Connect(ctx context.Context) error {
 	connStr := os.Getenv("DATABASE_URL")
 	if connStr == "" {
@@ -40,14 +78,22 @@ func Close() {
 }
 
 func RunMigrations(ctx context.Context) error {
-	migration, err := migrationsFS.ReadFile("migrations/001_initial_schema.sql")
+	// Migrations are idempotent and applied in filename order on every start
+	files, err := fs.Glob(migrationsFS, "migrations/*.sql")
 	if err != nil {
-		return fmt.Errorf("failed to read migration file: %w", err)
+		return fmt.Errorf("failed to list migration files: %w", err)
 	}
 
-	_, err = Pool.Exec(ctx, string(migration))
-	if err != nil {
-		return fmt.Errorf("failed to run migration: %w", err)
+	for _, file := range files {
+		migration, err := migrationsFS.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to read migration file %s: %w", file, err)
+		}
+
+		_, err = Pool.Exec(ctx, string(migration))
+		if err != nil {
+			return fmt.Errorf("failed to run migration %s: %w", file, err)
+		}
 	}
 
 	return nil
@@ -4,61 +4,59 @@ import (
 	"context"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"spending-tracker/models"
 )
 
-func GetExpensesByPeriod(ctx context.Context, year, month int) ([]models.Expense, error) {
-	rows, err := Pool.Query(ctx, `
+// expenseSelect selects an expense joined with its category; pair with scanExpense
+const expenseSelect = `
 		SELECT e.id, e.description, e.amount, e.category_id, e.expense_type,
-		       e.year, e.month, e.recurring_expense_id, e.created_at, e.updated_at,
+		       e.year, e.month, e.recurring_expense_id, e.version, e.created_at, e.updated_at,
+		       e.paid_by, COALESCE(pb.name, ''), COALESCE(e.share_method, ''),
+		       e.refund_of, COALESCE(ro.description, ''), COALESCE(e.reimbursable_from, ''),
+		       COALESCE((SELECT -SUM(r.amount) FROM expenses r WHERE r.refund_of = e.id), 0),
+		       e.account_id, COALESCE(ac.name, ''), e.cleared, e.reconciled_at,
+		       e.payee_id, COALESCE(py.name, ''), e.loan_id, COALESCE(ln.name, ''),
 		       c.id, c.name, c.color, c.created_at
 		FROM expenses e
 		LEFT JOIN categories c ON e.category_id = c.id
-		WHERE e.year = $1 AND e.month = $2
-		ORDER BY e.expense_type DESC, e.created_at
-	`, year, month)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
+		LEFT JOIN people pb ON e.paid_by = pb.id
+		LEFT JOIN expenses ro ON e.refund_of = ro.id
+		LEFT JOIN accounts ac ON e.account_id = ac.id
+		LEFT JOIN payees py ON e.payee_id = py.id
+		LEFT JOIN loans ln ON e.loan_id = ln.id`
 
-	var expenses []models.Expense
-	for rows.Next() {
-		var e models.Expense
-		var catName, catColor *string
-		var catCreatedAt *time.Time
-		var cID *int64
-
-		if err := rows.Scan(
-			&e.ID, &e.Description, &e.Amount, &e.CategoryID, &e.Type,
-			&e.Year, &e.Month, &e.RecurringExpenseID, &e.CreatedAt, &e.UpdatedAt,
-			&cID, &catName, &catColor, &catCreatedAt,
-		); err != nil {
-			return nil, err
-		}
+func scanExpense(row pgx.Row) (models.Expense, error) {
+	var e models.Expense
+	var cID *int64
+	var catName, catColor *string
+	var catCreatedAt *time.Time
 
-		if cID != nil && catName != nil && catColor != nil {
-			e.Category = &models.Category{
-				ID:    *cID,
-				Name:  *catName,
-				Color: *catColor,
-			}
+	if err := row.Scan(
+		&e.ID, &e.Description, &e.Amount, &e.CategoryID, &e.Type,
+		&e.Year, &e.Month, &e.RecurringExpenseID, &e.Version, &e.CreatedAt, &e.UpdatedAt,
+		&e.PaidBy, &e.PaidByName, &e.ShareMethod,
+		&e.RefundOf, &e.RefundOfDescription, &e.ReimbursableFrom, &e.Refunded,
+		&e.AccountID, &e.AccountName, &e.Cleared, &e.ReconciledAt,
+		&e.PayeeID, &e.PayeeName, &e.LoanID, &e.LoanName,
+		&cID, &catName, &catColor, &catCreatedAt,
+	); err != nil {
+		return e, err
+	}
+
+	if cID != nil && catName != nil && catColor != nil {
+		e.Category = &models.Category{
+			ID:    *cID,
+			Name:  *catName,
+			Color: *catColor,
 		}
-		expenses = append(expenses, e)
 	}
-	return expenses, rows.Err()
+	return e, nil
 }
 
-func GetExpensesByPeriodAndType(ctx context.Context, year, month int, expenseType models.ExpenseType) ([]models.Expense, error) {
-	rows, err := Pool.Query(ctx, `
-		SELECT e.id, e.description, e.amount, e.category_id, e.expense_type,
-		       e.year, e.month, e.recurring_expense_id, e.created_at, e.updated_at,
-		       c.id, c.name, c.color, c.created_at
-		FROM expenses e
-		LEFT JOIN categories c ON e.category_id = c.id
-		WHERE e.year = $1 AND e.month = $2 AND e.expense_type = $3
-		ORDER BY e.created_at
-	`, year, month, expenseType)
+// queryExpenses runs an expenseSelect query and loads each row's tags
+func queryExpenses(ctx context.Context, query string, args ...any) ([]models.Expense, error) {
+	rows, err := conn(ctx).Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -66,79 +64,87 @@ func GetExpensesByPeriodAndType(ctx context.Context, year, month int, expenseTyp
 
 	var expenses []models.Expense
 	for rows.Next() {
-		var e models.Expense
-		var cID *int64
-		var catName, catColor *string
-		var catCreatedAt *time.Time
-
-		if err := rows.Scan(
-			&e.ID, &e.Description, &e.Amount, &e.CategoryID, &e.Type,
-			&e.Year, &e.Month, &e.RecurringExpenseID, &e.CreatedAt, &e.UpdatedAt,
-			&cID, &catName, &catColor, &catCreatedAt,
-		); err != nil {
+		e, err := scanExpense(rows)
+		if err != nil {
 			return nil, err
 		}
-
-		if cID != nil && catName != nil && catColor != nil {
-			e.Category = &models.Category{
-				ID:    *cID,
-				Name:  *catName,
-				Color: *catColor,
-			}
-		}
 		expenses = append(expenses, e)
 	}
-	return expenses, rows.Err()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := attachTags(ctx, expenses); err != nil {
+		return nil, err
+	}
+	if err := attachSplits(ctx, expenses); err != nil {
+		return nil, err
+	}
+	if err := attachShares(ctx, expenses); err != nil {
+		return nil, err
+	}
+	return expenses, nil
 }
 
-func GetExpenseByID(ctx context.Context, id int64) (*models.Expense, error) {
-	var e models.Expense
-	var cID *int64
-	var catName, catColor *string
-	var catCreatedAt *time.Time
+func GetExpensesByPeriod(ctx context.Context, householdID int64, year, month int) ([]models.Expense, error) {
+	return queryExpenses(ctx, expenseSelect+`
+		WHERE e.household_id = $1 AND e.year = $2 AND e.month = $3
+		ORDER BY e.expense_type DESC, e.created_at
+	`, householdID, year, month)
+}
 
-	err := Pool.QueryRow(ctx, `
-		SELECT e.id, e.description, e.amount, e.category_id, e.expense_type,
-		       e.year, e.month, e.recurring_expense_id, e.created_at, e.updated_at,
-		       c.id, c.name, c.color, c.created_at
-		FROM expenses e
-		LEFT JOIN categories c ON e.category_id = c.id
-		WHERE e.id = $1
-	`, id).Scan(
-		&e.ID, &e.Description, &e.Amount, &e.CategoryID, &e.Type,
-		&e.Year, &e.Month, &e.RecurringExpenseID, &e.CreatedAt, &e.UpdatedAt,
-		&cID, &catName, &catColor, &catCreatedAt,
-	)
+func GetExpensesByPeriodAndType(ctx context.Context, householdID int64, year, month int, expenseType models.ExpenseType) ([]models.Expense, error) {
+	return queryExpenses(ctx, expenseSelect+`
+		WHERE e.household_id = $1 AND e.year = $2 AND e.month = $3 AND e.expense_type = $4
+		ORDER BY e.created_at
+	`, householdID, year, month, expenseType)
+}
+
+func GetExpensesByPeriodAndTag(ctx context.Context, householdID int64, year, month int, tag string) ([]models.Expense, error) {
+	return queryExpenses(ctx, expenseSelect+`
+		WHERE e.household_id = $1 AND e.year = $2 AND e.month = $3
+		  AND EXISTS (
+		      SELECT 1 FROM expense_tags et
+		      JOIN tags t ON t.id = et.tag_id
+		      WHERE et.expense_id = e.id AND t.name = $4
+		  )
+		ORDER BY e.expense_type DESC, e.created_at
+	`, householdID, year, month, tag)
+}
+
+func GetExpenseByID(ctx context.Context, householdID, id int64) (*models.Expense, error) {
+	expenses, err := queryExpenses(ctx, expenseSelect+`
+		WHERE e.household_id = $1 AND e.id = $2
+	`, householdID, id)
 	if err != nil {
 		return nil, err
 	}
-
-	if cID != nil && catName != nil && catColor != nil {
-		e.Category = &models.Category{
-			ID:    *cID,
-			Name:  *catName,
-			Color: *catColor,
-		}
+	if len(expenses) == 0 {
+		return nil, pgx.ErrNoRows
 	}
-	return &e, nil
+	return &expenses[0], nil
 }
 
-func CreateExpense(ctx context.Context, expense models.Expense) (*models.Expense, error) {
+func CreateExpense(ctx context.Context, householdID int64, expense models.Expense) (*models.Expense, error) {
 	var e models.Expense
-	err := Pool.QueryRow(ctx, `
-		INSERT INTO expenses (description, amount, category_id, expense_type, year, month, recurring_expense_id)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
-		RETURNING id, description, amount, category_id, expense_type, year, month, recurring_expense_id, created_at, updated_at
-	`, expense.Description, expense.Amount, expense.CategoryID, expense.Type,
-		expense.Year, expense.Month, expense.RecurringExpenseID,
+	err := conn(ctx).QueryRow(ctx, `
+		INSERT INTO expenses (household_id, description, amount, category_id, expense_type, year, month,
+		                      recurring_expense_id, refund_of, reimbursable_from, account_id, payee_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NULLIF($10, ''), $11, $12)
+		RETURNING id, description, amount, category_id, expense_type, year, month, recurring_expense_id,
+		          refund_of, COALESCE(reimbursable_from, ''), account_id, payee_id, version, created_at, updated_at
+	`, householdID, expense.Description, expense.Amount, expense.CategoryID, expense.Type,
+		expense.Year, expense.Month, expense.RecurringExpenseID, expense.RefundOf, expense.ReimbursableFrom,
+		expense.AccountID, expense.PayeeID,
 	).Scan(&e.ID, &e.Description, &e.Amount, &e.CategoryID, &e.Type,
-		&e.Year, &e.Month, &e.RecurringExpenseID, &e.CreatedAt, &e.UpdatedAt)
+		&e.Year, &e.Month, &e.RecurringExpenseID, &e.RefundOf, &e.ReimbursableFrom,
+		&e.AccountID, &e.PayeeID, &e.Version, &e.CreatedAt, &e.UpdatedAt)
 	if err != nil {
 		return nil, err
 	}
 
 	if e.CategoryID != nil {
-		cat, err := GetCategoryByID(ctx, *e.CategoryID)
+		cat, err := GetCategoryByID(ctx, householdID, *e.CategoryID)
 		if err == nil {
 			e.Category = cat
 		}
@@ -146,16 +152,161 @@ func CreateExpense(ctx context.Context, expense models.Expense) (*models.Expense
 	return &e, nil
 }
 
-func UpdateExpense(ctx context.Context, id int64, description string, amount float64, categoryID *int64, expenseType models.ExpenseType) error {
-	_, err := Pool.Exec(ctx, `
+// UpdateExpense overwrites an expense if it is still at the given version,
+// reporting false when it was changed in the meantime. A version of zero
+// updates unconditionally. Moving it to another account clears its tick.
+func UpdateExpense(ctx context.Context, householdID, id int64, version int, description string, amount float64, categoryID, accountID, payeeID *int64, expenseType models.ExpenseType) (bool, error) {
+	tag, err := conn(ctx).Exec(ctx, `
+		UPDATE expenses
+		SET description = $4, amount = $5, category_id = $6, account_id = $7, payee_id = $8, expense_type = $9,
+		    cleared = cleared AND account_id IS NOT DISTINCT FROM $7,
+		    version = version + 1, updated_at = NOW()
+		WHERE household_id = $1 AND id = $2 AND ($3 = 0 OR version = $3)
+	`, householdID, id, version, description, amount, categoryID, accountID, payeeID, expenseType)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// SetExpenseReimbursable records who owes an expense back; empty clears it
+func SetExpenseReimbursable(ctx context.Context, householdID, id int64, from string) (bool, error) {
+	tag, err := conn(ctx).Exec(ctx, `
+		UPDATE expenses
+		SET reimbursable_from = NULLIF($3, ''), version = version + 1, updated_at = NOW()
+		WHERE household_id = $1 AND id = $2
+	`, householdID, id, from)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// SetExpenseLoan records the loan an expense was an extra payment toward;
+// nil clears it
+func SetExpenseLoan(ctx context.Context, householdID, id int64, loanID *int64) (bool, error) {
+	tag, err := conn(ctx).Exec(ctx, `
 		UPDATE expenses
-		SET description = $2, amount = $3, category_id = $4, expense_type = $5, updated_at = NOW()
-		WHERE id = $1
-	`, id, description, amount, categoryID, expenseType)
+		SET loan_id = $3, version = version + 1, updated_at = NOW()
+		WHERE household_id = $1 AND id = $2
+	`, householdID, id, loanID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// GetDuplicateCandidates returns expenses with the given amount in a period
+// or added since a time, oldest first, to be checked for duplicates
+func GetDuplicateCandidates(ctx context.Context, householdID int64, year, month int, amount float64, since time.Time) ([]models.Expense, error) {
+	return queryExpenses(ctx, expenseSelect+`
+		WHERE e.household_id = $1 AND e.amount = $4
+		  AND ((e.year = $2 AND e.month = $3) OR e.created_at >= $5)
+		ORDER BY e.created_at
+	`, householdID, year, month, amount, since)
+}
+
+// GetChargesBetween returns the expenses charged from one period to another,
+// inclusive, oldest period first, leaving out refunds
+func GetChargesBetween(ctx context.Context, householdID int64, from, to models.Period) ([]models.Expense, error) {
+	return queryExpenses(ctx, expenseSelect+`
+		WHERE e.household_id = $1 AND e.amount > 0 AND e.refund_of IS NULL
+		  AND e.year * 12 + e.month - 1 BETWEEN $2 AND $3
+		ORDER BY e.year, e.month, e.created_at
+	`, householdID, from.Index(), to.Index())
+}
+
+// GetExpensesBetween returns the expenses from one period to another,
+// inclusive, refunds included, oldest period first
+func GetExpensesBetween(ctx context.Context, householdID int64, from, to models.Period) ([]models.Expense, error) {
+	return queryExpenses(ctx, expenseSelect+`
+		WHERE e.household_id = $1
+		  AND e.year * 12 + e.month - 1 BETWEEN $2 AND $3
+		ORDER BY e.year, e.month, e.created_at
+	`, householdID, from.Index(), to.Index())
+}
+
+// GetExpenseHistory returns the expenses between two periods with only what
+// spotting unusual spending needs: amounts, types, periods, categories and
+// split lines, payees, recurring templates and refund links. It skips the
+// joins, tags and shares of a full load since it runs on every period load.
+func GetExpenseHistory(ctx context.Context, householdID int64, from, to models.Period) ([]models.Expense, error) {
+	rows, err := conn(ctx).Query(ctx, `
+		SELECT e.id, e.amount, e.category_id, e.expense_type, e.year, e.month,
+		       e.recurring_expense_id, e.refund_of, e.payee_id, c.name
+		FROM expenses e
+		LEFT JOIN categories c ON e.category_id = c.id
+		WHERE e.household_id = $1
+		  AND e.year * 12 + e.month - 1 BETWEEN $2 AND $3
+		ORDER BY e.year, e.month, e.created_at
+	`, householdID, from.Index(), to.Index())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var expenses []models.Expense
+	for rows.Next() {
+		var e models.Expense
+		var catName *string
+		if err := rows.Scan(&e.ID, &e.Amount, &e.CategoryID, &e.Type, &e.Year, &e.Month,
+			&e.RecurringExpenseID, &e.RefundOf, &e.PayeeID, &catName); err != nil {
+			return nil, err
+		}
+		if e.CategoryID != nil && catName != nil {
+			e.Category = &models.Category{ID: *e.CategoryID, Name: *catName}
+		}
+		expenses = append(expenses, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if err := attachSplits(ctx, expenses); err != nil {
+		return nil, err
+	}
+	return expenses, nil
+}
+
+// GetPendingReimbursements returns reimbursable expenses not yet paid back in full
+func GetPendingReimbursements(ctx context.Context, householdID int64) ([]models.Expense, error) {
+	return queryExpenses(ctx, expenseSelect+`
+		WHERE e.household_id = $1 AND e.reimbursable_from IS NOT NULL
+		  AND e.amount > COALESCE((SELECT -SUM(r.amount) FROM expenses r WHERE r.refund_of = e.id), 0)
+		ORDER BY e.year, e.month, e.created_at
+	`, householdID)
+}
+
+func DeleteExpense(ctx context.Context, householdID, id int64) error {
+	_, err := conn(ctx).Exec(ctx, `DELETE FROM expenses WHERE household_id = $1 AND id = $2`, householdID, id)
 	return err
 }
 
-func DeleteExpense(ctx context.Context, id int64) error {
-	_, err := Pool.Exec(ctx, `DELETE FROM expenses WHERE id = $1`, id)
+// RestoreExpense re-inserts a deleted expense with its original ID and
+// timestamps, and a new version so edits made before the delete conflict.
+// Its category, recurring template, refunded expense, account, payee and
+// loan are only relinked if they still exist in the household, and it is
+// only cleared or reconciled again if its account does.
+func RestoreExpense(ctx context.Context, householdID int64, e models.Expense) error {
+	_, err := conn(ctx).Exec(ctx, `
+		INSERT INTO expenses (id, household_id, description, amount, category_id, expense_type,
+		                      year, month, recurring_expense_id, refund_of, reimbursable_from,
+		                      account_id, payee_id, loan_id, version, created_at, updated_at,
+		                      cleared, reconciled_at)
+		VALUES ($1, $2, $3, $4,
+		        (SELECT id FROM categories WHERE household_id = $2 AND id = $5),
+		        $6, $7, $8,
+		        (SELECT id FROM recurring_expenses WHERE household_id = $2 AND id = $9),
+		        (SELECT id FROM expenses WHERE household_id = $2 AND id = $10),
+		        NULLIF($11, ''),
+		        (SELECT id FROM accounts WHERE household_id = $2 AND id = $12),
+		        (SELECT id FROM payees WHERE household_id = $2 AND id = $13),
+		        (SELECT id FROM loans WHERE household_id = $2 AND id = $14),
+		        $15, $16, $17,
+		        COALESCE((SELECT $18::boolean FROM accounts WHERE household_id = $2 AND id = $12), FALSE),
+		        (SELECT $19::timestamptz FROM accounts WHERE household_id = $2 AND id = $12))
+	`, e.ID, householdID, e.Description, e.Amount, e.CategoryID, e.Type,
+		e.Year, e.Month, e.RecurringExpenseID, e.RefundOf, e.ReimbursableFrom,
+		e.AccountID, e.PayeeID, e.LoanID, e.Version+1, e.CreatedAt, e.UpdatedAt,
+		e.Cleared, e.ReconciledAt)
 	return err
 }
@@ -0,0 +1,161 @@
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"spending-tracker/models"
+)
+
+// goalSelect selects a savings goal with its account and what has been put
+// toward it; pair with scanGoal
+const goalSelect = `
+		SELECT g.id, g.name, g.target_amount, g.target_date, g.account_id, COALESCE(a.name, ''),
+		       COALESCE((SELECT SUM(c.amount) FROM goal_contributions c WHERE c.goal_id = g.id), 0),
+		       g.created_at
+		FROM savings_goals g
+		LEFT JOIN accounts a ON g.account_id = a.id`
+
+func scanGoal(row pgx.Row) (models.SavingsGoal, error) {
+	var g models.SavingsGoal
+	err := row.Scan(&g.ID, &g.Name, &g.TargetAmount, &g.TargetDate, &g.AccountID, &g.AccountName,
+		&g.Saved, &g.CreatedAt)
+	return g, err
+}
+
+// GetGoals returns the household's savings goals, soonest target first
+func GetGoals(ctx context.Context, householdID int64) ([]models.SavingsGoal, error) {
+	rows, err := conn(ctx).Query(ctx, goalSelect+`
+		WHERE g.household_id = $1
+		ORDER BY g.target_date, g.name
+	`, householdID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var goals []models.SavingsGoal
+	for rows.Next() {
+		g, err := scanGoal(rows)
+		if err != nil {
+			return nil, err
+		}
+		goals = append(goals, g)
+	}
+	return goals, rows.Err()
+}
+
+func GetGoal(ctx context.Context, householdID, id int64) (*models.SavingsGoal, error) {
+	g, err := scanGoal(conn(ctx).QueryRow(ctx, goalSelect+`
+		WHERE g.household_id = $1 AND g.id = $2
+	`, householdID, id))
+	if err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+func CreateGoal(ctx context.Context, householdID int64, goal models.SavingsGoal) (int64, error) {
+	var id int64
+	err := conn(ctx).QueryRow(ctx, `
+		INSERT INTO savings_goals (household_id, name, target_amount, target_date, account_id)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`, householdID, goal.Name, goal.TargetAmount, goal.TargetDate, goal.AccountID).Scan(&id)
+	return id, err
+}
+
+// UpdateGoal overwrites a goal's details, reporting whether it existed
+func UpdateGoal(ctx context.Context, householdID int64, goal models.SavingsGoal) (bool, error) {
+	tag, err := conn(ctx).Exec(ctx, `
+		UPDATE savings_goals SET name = $3, target_amount = $4, target_date = $5, account_id = $6
+		WHERE household_id = $1 AND id = $2
+	`, householdID, goal.ID, goal.Name, goal.TargetAmount, goal.TargetDate, goal.AccountID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// DeleteGoal removes a goal and its contributions, reporting whether it existed
+func DeleteGoal(ctx context.Context, householdID, id int64) (bool, error) {
+	tag, err := conn(ctx).Exec(ctx, `DELETE FROM savings_goals WHERE household_id = $1 AND id = $2`, householdID, id)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// contributionSelect selects a contribution with its goal's name; pair with scanContribution
+const contributionSelect = `
+		SELECT c.id, c.goal_id, g.name, c.amount, c.year, c.month, c.note, c.created_at
+		FROM goal_contributions c
+		JOIN savings_goals g ON c.goal_id = g.id`
+
+func scanContribution(row pgx.Row) (models.GoalContribution, error) {
+	var c models.GoalContribution
+	err := row.Scan(&c.ID, &c.GoalID, &c.GoalName, &c.Amount, &c.Year, &c.Month, &c.Note, &c.CreatedAt)
+	return c, err
+}
+
+// GetContributions returns what was put toward goals in a period
+func GetContributions(ctx context.Context, householdID int64, year, month int) ([]models.GoalContribution, error) {
+	rows, err := conn(ctx).Query(ctx, contributionSelect+`
+		WHERE c.household_id = $1 AND c.year = $2 AND c.month = $3
+		ORDER BY c.created_at
+	`, householdID, year, month)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var contributions []models.GoalContribution
+	for rows.Next() {
+		c, err := scanContribution(rows)
+		if err != nil {
+			return nil, err
+		}
+		contributions = append(contributions, c)
+	}
+	return contributions, rows.Err()
+}
+
+func GetContribution(ctx context.Context, householdID, id int64) (*models.GoalContribution, error) {
+	c, err := scanContribution(conn(ctx).QueryRow(ctx, contributionSelect+`
+		WHERE c.household_id = $1 AND c.id = $2
+	`, householdID, id))
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// GetContributionTotal returns what was put toward goals in a period, net
+// of anything taken back out
+func GetContributionTotal(ctx context.Context, householdID int64, year, month int) (float64, error) {
+	var total float64
+	err := conn(ctx).QueryRow(ctx, `
+		SELECT COALESCE(SUM(amount), 0) FROM goal_contributions
+		WHERE household_id = $1 AND year = $2 AND month = $3
+	`, householdID, year, month).Scan(&total)
+	return total, err
+}
+
+func CreateContribution(ctx context.Context, householdID int64, c models.GoalContribution) (int64, error) {
+	var id int64
+	err := conn(ctx).QueryRow(ctx, `
+		INSERT INTO goal_contributions (household_id, goal_id, amount, year, month, note)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`, householdID, c.GoalID, c.Amount, c.Year, c.Month, c.Note).Scan(&id)
+	return id, err
+}
+
+// DeleteContribution removes a contribution, reporting whether it existed
+func DeleteContribution(ctx context.Context, householdID, id int64) (bool, error) {
+	tag, err := conn(ctx).Exec(ctx, `DELETE FROM goal_contributions WHERE household_id = $1 AND id = $2`, householdID, id)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
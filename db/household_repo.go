@@ -0,0 +1,136 @@
+package db
+
+import (
+	"context"
+
+	"spending-tracker/models"
+)
+
+// GetMemberships returns the households a user belongs to, by name
+func GetMemberships(ctx context.Context, userID int64) ([]models.Membership, error) {
+	rows, err := conn(ctx).Query(ctx, `
+		SELECT h.id, h.name, h.created_at, m.role
+		FROM household_members m
+		JOIN households h ON h.id = m.household_id
+		WHERE m.user_id = $1
+		ORDER BY h.name, h.id
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var memberships []models.Membership
+	for rows.Next() {
+		var m models.Membership
+		if err := rows.Scan(&m.Household.ID, &m.Household.Name, &m.Household.CreatedAt, &m.Role); err != nil {
+			return nil, err
+		}
+		memberships = append(memberships, m)
+	}
+	return memberships, rows.Err()
+}
+
+func GetMembership(ctx context.Context, userID, householdID int64) (*models.Membership, error) {
+	var m models.Membership
+	err := conn(ctx).QueryRow(ctx, `
+		SELECT h.id, h.name, h.created_at, m.role
+		FROM household_members m
+		JOIN households h ON h.id = m.household_id
+		WHERE m.user_id = $1 AND m.household_id = $2
+	`, userID, householdID).Scan(&m.Household.ID, &m.Household.Name, &m.Household.CreatedAt, &m.Role)
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// CreateHousehold creates a household with ownerID as its first owner
+func CreateHousehold(ctx context.Context, name string, ownerID int64) (*models.Household, error) {
+	tx, err := conn(ctx).Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var h models.Household
+	err = tx.QueryRow(ctx, `
+		INSERT INTO households (name)
+		VALUES ($1)
+		RETURNING id, name, created_at
+	`, name).Scan(&h.ID, &h.Name, &h.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO household_members (household_id, user_id, role)
+		VALUES ($1, $2, $3)
+	`, h.ID, ownerID, models.RoleOwner); err != nil {
+		return nil, err
+	}
+
+	return &h, tx.Commit(ctx)
+}
+
+func RenameHousehold(ctx context.Context, id int64, name string) error {
+	_, err := conn(ctx).Exec(ctx, `UPDATE households SET name = $2 WHERE id = $1`, id, name)
+	return err
+}
+
+func GetHouseholdMembers(ctx context.Context, householdID int64) ([]models.Member, error) {
+	rows, err := conn(ctx).Query(ctx, `
+		SELECT u.id, u.username, m.role, m.created_at
+		FROM household_members m
+		JOIN users u ON u.id = m.user_id
+		WHERE m.household_id = $1
+		ORDER BY u.username
+	`, householdID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var members []models.Member
+	for rows.Next() {
+		var m models.Member
+		if err := rows.Scan(&m.UserID, &m.Username, &m.Role, &m.JoinedAt); err != nil {
+			return nil, err
+		}
+		members = append(members, m)
+	}
+	return members, rows.Err()
+}
+
+func AddHouseholdMember(ctx context.Context, householdID, userID int64, role models.Role) error {
+	_, err := conn(ctx).Exec(ctx, `
+		INSERT INTO household_members (household_id, user_id, role)
+		VALUES ($1, $2, $3)
+	`, householdID, userID, role)
+	return err
+}
+
+func UpdateHouseholdMemberRole(ctx context.Context, householdID, userID int64, role models.Role) error {
+	_, err := conn(ctx).Exec(ctx, `
+		UPDATE household_members
+		SET role = $3
+		WHERE household_id = $1 AND user_id = $2
+	`, householdID, userID, role)
+	return err
+}
+
+func RemoveHouseholdMember(ctx context.Context, householdID, userID int64) error {
+	_, err := conn(ctx).Exec(ctx, `
+		DELETE FROM household_members
+		WHERE household_id = $1 AND user_id = $2
+	`, householdID, userID)
+	return err
+}
+
+func CountHouseholdOwners(ctx context.Context, householdID int64) (int, error) {
+	var count int
+	err := conn(ctx).QueryRow(ctx, `
+		SELECT COUNT(*) FROM household_members WHERE household_id = $1 AND role = $2
+	`, householdID, models.RoleOwner).Scan(&count)
+	return count, err
+}
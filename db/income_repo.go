@@ -6,13 +6,13 @@ import (
 	"github.com/jackc/pgx/v5"
 )
 
-func GetIncomeByPeriod(ctx context.Context, year, month int) (float64, error) {
+func GetIncomeByPeriod(ctx context.Context, householdID int64, year, month int) (float64, error) {
 	var amount float64
-	err := Pool.QueryRow(ctx, `
+	err := conn(ctx).QueryRow(ctx, `
 		SELECT amount
 		FROM income
-		WHERE year = $1 AND month = $2
-	`, year, month).Scan(&amount)
+		WHERE household_id = $1 AND year = $2 AND month = $3
+	`, householdID, year, month).Scan(&amount)
 	if err == pgx.ErrNoRows {
 		return 0, nil
 	}
@@ -22,12 +22,12 @@ func GetIncomeByPeriod(ctx context.Context, year, month int) (float64, error) {
 	return amount, nil
 }
 
-func UpsertIncome(ctx context.Context, year, month int, amount float64) error {
-	_, err := Pool.Exec(ctx, `
-		INSERT INTO income (year, month, amount)
-		VALUES ($1, $2, $3)
-		ON CONFLICT (year, month)
-		DO UPDATE SET amount = $3, updated_at = NOW()
-	`, year, month, amount)
+func UpsertIncome(ctx context.Context, householdID int64, year, month int, amount float64) error {
+	_, err := conn(ctx).Exec(ctx, `
+		INSERT INTO income (household_id, year, month, amount)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (household_id, year, month)
+		DO UPDATE SET amount = $4, updated_at = NOW()
+	`, householdID, year, month, amount)
 	return err
 }
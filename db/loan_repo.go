@@ -0,0 +1,121 @@
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"spending-tracker/models"
+)
+
+// loanSelect selects a loan with its recurring repayment; pair with scanLoan
+const loanSelect = `
+		SELECT l.id, l.name, l.principal, l.apr, l.minimum_payment, l.start_year, l.start_month,
+		       l.recurring_expense_id, COALESCE(r.description, ''), COALESCE(r.amount, 0), l.created_at
+		FROM loans l
+		LEFT JOIN recurring_expenses r ON l.recurring_expense_id = r.id`
+
+func scanLoan(row pgx.Row) (models.Loan, error) {
+	var l models.Loan
+	err := row.Scan(&l.ID, &l.Name, &l.Principal, &l.APR, &l.MinimumPayment, &l.StartYear, &l.StartMonth,
+		&l.RecurringExpenseID, &l.RecurringDescription, &l.RecurringAmount, &l.CreatedAt)
+	return l, err
+}
+
+func GetLoans(ctx context.Context, householdID int64) ([]models.Loan, error) {
+	rows, err := conn(ctx).Query(ctx, loanSelect+`
+		WHERE l.household_id = $1
+		ORDER BY l.name
+	`, householdID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var loans []models.Loan
+	for rows.Next() {
+		l, err := scanLoan(rows)
+		if err != nil {
+			return nil, err
+		}
+		loans = append(loans, l)
+	}
+	return loans, rows.Err()
+}
+
+func GetLoan(ctx context.Context, householdID, id int64) (*models.Loan, error) {
+	l, err := scanLoan(conn(ctx).QueryRow(ctx, loanSelect+`
+		WHERE l.household_id = $1 AND l.id = $2
+	`, householdID, id))
+	if err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+func CreateLoan(ctx context.Context, householdID int64, loan models.Loan) (int64, error) {
+	var id int64
+	err := conn(ctx).QueryRow(ctx, `
+		INSERT INTO loans (household_id, name, principal, apr, minimum_payment, start_year, start_month, recurring_expense_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id
+	`, householdID, loan.Name, loan.Principal, loan.APR, loan.MinimumPayment,
+		loan.StartYear, loan.StartMonth, loan.RecurringExpenseID).Scan(&id)
+	return id, err
+}
+
+// UpdateLoan overwrites a loan's details, reporting whether it existed
+func UpdateLoan(ctx context.Context, householdID int64, loan models.Loan) (bool, error) {
+	tag, err := conn(ctx).Exec(ctx, `
+		UPDATE loans
+		SET name = $3, principal = $4, apr = $5, minimum_payment = $6,
+		    start_year = $7, start_month = $8, recurring_expense_id = $9
+		WHERE household_id = $1 AND id = $2
+	`, householdID, loan.ID, loan.Name, loan.Principal, loan.APR, loan.MinimumPayment,
+		loan.StartYear, loan.StartMonth, loan.RecurringExpenseID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// DeleteLoan removes a loan, reporting whether it existed. Its extra
+// payments are kept as ordinary expenses.
+func DeleteLoan(ctx context.Context, householdID, id int64) (bool, error) {
+	tag, err := conn(ctx).Exec(ctx, `DELETE FROM loans WHERE household_id = $1 AND id = $2`, householdID, id)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// GetLoanPayments returns what was paid toward each loan in each period
+// from its start: by its recurring expense and in extra payments
+func GetLoanPayments(ctx context.Context, householdID int64) (map[int64][]models.LoanPayment, error) {
+	rows, err := conn(ctx).Query(ctx, `
+		SELECT l.id, e.year, e.month,
+		       COALESCE(SUM(e.amount) FILTER (WHERE e.recurring_expense_id = l.recurring_expense_id), 0),
+		       COALESCE(SUM(e.amount) FILTER (WHERE e.loan_id = l.id), 0)
+		FROM loans l
+		JOIN expenses e ON e.household_id = l.household_id
+		     AND (e.loan_id = l.id OR e.recurring_expense_id = l.recurring_expense_id)
+		     AND (e.year, e.month) >= (l.start_year, l.start_month)
+		WHERE l.household_id = $1
+		GROUP BY l.id, e.year, e.month
+		ORDER BY l.id, e.year, e.month
+	`, householdID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	payments := make(map[int64][]models.LoanPayment)
+	for rows.Next() {
+		var loanID int64
+		var p models.LoanPayment
+		if err := rows.Scan(&loanID, &p.Year, &p.Month, &p.Regular, &p.Extra); err != nil {
+			return nil, err
+		}
+		payments[loanID] = append(payments[loanID], p)
+	}
+	return payments, rows.Err()
+}
@@ -0,0 +1,12 @@
+-- Subcategories: a category may have a single top-level parent
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS parent_id INTEGER REFERENCES categories(id) ON DELETE SET NULL;
+
+-- Optional monthly budget, assignable to parents or children
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS budget DECIMAL(12, 2);
+
+-- Names only need to be unique among siblings, which 007_households enforces
+-- per household. Building a global index here would fail on every start once
+-- two households share a category name.
+ALTER TABLE categories DROP CONSTRAINT IF EXISTS categories_name_key;
+
+CREATE INDEX IF NOT EXISTS idx_categories_parent ON categories(parent_id);
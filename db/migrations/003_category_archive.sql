@@ -0,0 +1,25 @@
+-- Archived categories are hidden from pickers but kept for history
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS archived_at TIMESTAMPTZ;
+
+-- Deleting a category in use must go through reassignment instead of
+-- silently nulling historical expenses and recurring templates
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM pg_constraint
+        WHERE conname = 'expenses_category_id_fkey' AND confdeltype <> 'r'
+    ) THEN
+        ALTER TABLE expenses DROP CONSTRAINT expenses_category_id_fkey;
+        ALTER TABLE expenses ADD CONSTRAINT expenses_category_id_fkey
+            FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE RESTRICT;
+    END IF;
+
+    IF EXISTS (
+        SELECT 1 FROM pg_constraint
+        WHERE conname = 'recurring_expenses_category_id_fkey' AND confdeltype <> 'r'
+    ) THEN
+        ALTER TABLE recurring_expenses DROP CONSTRAINT recurring_expenses_category_id_fkey;
+        ALTER TABLE recurring_expenses ADD CONSTRAINT recurring_expenses_category_id_fkey
+            FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE RESTRICT;
+    END IF;
+END $$;
@@ -0,0 +1,5 @@
+-- Trigram index for substring search over expense descriptions
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS idx_expenses_description_trgm ON expenses USING GIN (description gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_expenses_amount ON expenses(amount);
@@ -0,0 +1,84 @@
+-- A household owns a budget; users join households as members with a role
+CREATE TABLE IF NOT EXISTS households (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS household_members (
+    household_id INTEGER NOT NULL REFERENCES households(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL CHECK (role IN ('owner', 'editor', 'viewer')),
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    PRIMARY KEY(household_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_household_members_user ON household_members(user_id);
+
+-- Every budget table is scoped to a household
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS household_id INTEGER REFERENCES households(id) ON DELETE CASCADE;
+ALTER TABLE income ADD COLUMN IF NOT EXISTS household_id INTEGER REFERENCES households(id) ON DELETE CASCADE;
+ALTER TABLE recurring_expenses ADD COLUMN IF NOT EXISTS household_id INTEGER REFERENCES households(id) ON DELETE CASCADE;
+ALTER TABLE expenses ADD COLUMN IF NOT EXISTS household_id INTEGER REFERENCES households(id) ON DELETE CASCADE;
+ALTER TABLE initialized_months ADD COLUMN IF NOT EXISTS household_id INTEGER REFERENCES households(id) ON DELETE CASCADE;
+ALTER TABLE tags ADD COLUMN IF NOT EXISTS household_id INTEGER REFERENCES households(id) ON DELETE CASCADE;
+
+-- Data from before households existed moves into a shared household owned
+-- by every existing user. With no users yet, the first admin adopts it at setup.
+DO $$
+DECLARE
+    legacy_id INTEGER;
+BEGIN
+    IF EXISTS (SELECT 1 FROM categories WHERE household_id IS NULL)
+        OR EXISTS (SELECT 1 FROM income WHERE household_id IS NULL)
+        OR EXISTS (SELECT 1 FROM recurring_expenses WHERE household_id IS NULL)
+        OR EXISTS (SELECT 1 FROM expenses WHERE household_id IS NULL)
+        OR EXISTS (SELECT 1 FROM initialized_months WHERE household_id IS NULL)
+        OR EXISTS (SELECT 1 FROM tags WHERE household_id IS NULL)
+    THEN
+        INSERT INTO households (name) VALUES ('Household') RETURNING id INTO legacy_id;
+
+        INSERT INTO household_members (household_id, user_id, role)
+        SELECT legacy_id, id, 'owner' FROM users;
+
+        UPDATE categories SET household_id = legacy_id WHERE household_id IS NULL;
+        UPDATE income SET household_id = legacy_id WHERE household_id IS NULL;
+        UPDATE recurring_expenses SET household_id = legacy_id WHERE household_id IS NULL;
+        UPDATE expenses SET household_id = legacy_id WHERE household_id IS NULL;
+        UPDATE initialized_months SET household_id = legacy_id WHERE household_id IS NULL;
+        UPDATE tags SET household_id = legacy_id WHERE household_id IS NULL;
+    END IF;
+END $$;
+
+ALTER TABLE categories ALTER COLUMN household_id SET NOT NULL;
+ALTER TABLE income ALTER COLUMN household_id SET NOT NULL;
+ALTER TABLE recurring_expenses ALTER COLUMN household_id SET NOT NULL;
+ALTER TABLE expenses ALTER COLUMN household_id SET NOT NULL;
+ALTER TABLE initialized_months ALTER COLUMN household_id SET NOT NULL;
+ALTER TABLE tags ALTER COLUMN household_id SET NOT NULL;
+
+-- Uniqueness now only applies within a household
+DROP INDEX IF EXISTS idx_categories_parent_name;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_household_parent_name ON categories(household_id, COALESCE(parent_id, 0), name);
+
+ALTER TABLE income DROP CONSTRAINT IF EXISTS income_year_month_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_income_household_period ON income(household_id, year, month);
+
+ALTER TABLE tags DROP CONSTRAINT IF EXISTS tags_name_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_household_name ON tags(household_id, name);
+
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM pg_index i
+        JOIN pg_class c ON c.oid = i.indrelid
+        WHERE c.relname = 'initialized_months' AND i.indisprimary AND i.indnatts = 2
+    ) THEN
+        ALTER TABLE initialized_months DROP CONSTRAINT initialized_months_pkey;
+        ALTER TABLE initialized_months ADD PRIMARY KEY (household_id, year, month);
+    END IF;
+END $$;
+
+CREATE INDEX IF NOT EXISTS idx_categories_household ON categories(household_id);
+CREATE INDEX IF NOT EXISTS idx_recurring_expenses_household ON recurring_expenses(household_id);
+CREATE INDEX IF NOT EXISTS idx_expenses_household_period ON expenses(household_id, year, month);
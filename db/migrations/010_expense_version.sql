@@ -0,0 +1,2 @@
+-- Incremented on every update so concurrent edits can detect each other
+ALTER TABLE expenses ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,14 @@
+-- An expense can be split into lines, each attributed to its own category.
+-- Lines must sum to the expense amount; an expense without lines counts
+-- wholly towards its own category.
+CREATE TABLE IF NOT EXISTS expense_splits (
+    id SERIAL PRIMARY KEY,
+    expense_id INTEGER NOT NULL REFERENCES expenses(id) ON DELETE CASCADE,
+    category_id INTEGER REFERENCES categories(id) ON DELETE RESTRICT,
+    amount DECIMAL(12, 2) NOT NULL,
+    note VARCHAR(255) NOT NULL DEFAULT '',
+    position INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_expense_splits_expense ON expense_splits(expense_id);
+CREATE INDEX IF NOT EXISTS idx_expense_splits_category ON expense_splits(category_id);
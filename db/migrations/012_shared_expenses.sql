@@ -0,0 +1,44 @@
+-- People who share expenses within a household. A person may be linked to
+-- a user so their summary counts only their own share.
+CREATE TABLE IF NOT EXISTS people (
+    id SERIAL PRIMARY KEY,
+    household_id INTEGER NOT NULL REFERENCES households(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    user_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_people_household_name ON people(household_id, name);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_people_household_user ON people(household_id, user_id);
+
+-- Who paid a shared expense and how it is divided. Expenses without a
+-- share method are not shared.
+ALTER TABLE expenses ADD COLUMN IF NOT EXISTS paid_by INTEGER REFERENCES people(id) ON DELETE RESTRICT;
+ALTER TABLE expenses ADD COLUMN IF NOT EXISTS share_method VARCHAR(10)
+    CHECK (share_method IN ('equal', 'percent', 'exact'));
+
+-- value holds the percentage or exact amount entered; amount is the
+-- resulting share in pounds, which always sums to the expense amount
+CREATE TABLE IF NOT EXISTS expense_shares (
+    expense_id INTEGER NOT NULL REFERENCES expenses(id) ON DELETE CASCADE,
+    person_id INTEGER NOT NULL REFERENCES people(id) ON DELETE RESTRICT,
+    value DECIMAL(12, 4) NOT NULL DEFAULT 0,
+    amount DECIMAL(12, 2) NOT NULL,
+    PRIMARY KEY (expense_id, person_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_expense_shares_person ON expense_shares(person_id);
+
+-- Payments between people that settle their balances
+CREATE TABLE IF NOT EXISTS settlements (
+    id SERIAL PRIMARY KEY,
+    household_id INTEGER NOT NULL REFERENCES households(id) ON DELETE CASCADE,
+    from_person_id INTEGER NOT NULL REFERENCES people(id) ON DELETE RESTRICT,
+    to_person_id INTEGER NOT NULL REFERENCES people(id) ON DELETE RESTRICT,
+    amount DECIMAL(12, 2) NOT NULL CHECK (amount > 0),
+    note VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    CHECK (from_person_id <> to_person_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_settlements_household ON settlements(household_id, created_at DESC);
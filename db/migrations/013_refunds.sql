@@ -0,0 +1,11 @@
+-- A refund is a negative expense linked to the expense it refunds. It lands
+-- in the period the money came back, reducing that period's totals.
+ALTER TABLE expenses ADD COLUMN IF NOT EXISTS refund_of INTEGER REFERENCES expenses(id) ON DELETE SET NULL;
+
+-- Who owes the money back for a reimbursable expense, e.g. an employer.
+-- Reimbursements are recorded as refunds of the expense.
+ALTER TABLE expenses ADD COLUMN IF NOT EXISTS reimbursable_from VARCHAR(255);
+
+CREATE INDEX IF NOT EXISTS idx_expenses_refund_of ON expenses(refund_of);
+CREATE INDEX IF NOT EXISTS idx_expenses_reimbursable ON expenses(household_id)
+    WHERE reimbursable_from IS NOT NULL;
@@ -0,0 +1,36 @@
+-- Where money is held. Balances start from the opening balance; a credit
+-- card's balance is negative while money is owed on it.
+CREATE TABLE IF NOT EXISTS accounts (
+    id SERIAL PRIMARY KEY,
+    household_id INTEGER NOT NULL REFERENCES households(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    account_type VARCHAR(20) NOT NULL
+        CHECK (account_type IN ('current', 'credit_card', 'cash', 'savings')),
+    opening_balance DECIMAL(12, 2) NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_accounts_household_name ON accounts(household_id, name);
+
+-- Which account an expense was paid from or a period's income paid into
+ALTER TABLE expenses ADD COLUMN IF NOT EXISTS account_id INTEGER REFERENCES accounts(id) ON DELETE RESTRICT;
+ALTER TABLE income ADD COLUMN IF NOT EXISTS account_id INTEGER REFERENCES accounts(id) ON DELETE RESTRICT;
+
+CREATE INDEX IF NOT EXISTS idx_expenses_account ON expenses(account_id);
+
+-- Money moved between accounts. Transfers are not spending, so they are
+-- kept apart from expenses and never count towards the summary.
+CREATE TABLE IF NOT EXISTS transfers (
+    id SERIAL PRIMARY KEY,
+    household_id INTEGER NOT NULL REFERENCES households(id) ON DELETE CASCADE,
+    from_account_id INTEGER NOT NULL REFERENCES accounts(id) ON DELETE RESTRICT,
+    to_account_id INTEGER NOT NULL REFERENCES accounts(id) ON DELETE RESTRICT,
+    amount DECIMAL(12, 2) NOT NULL CHECK (amount > 0),
+    year INTEGER NOT NULL,
+    month INTEGER NOT NULL,
+    note VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    CHECK (from_account_id <> to_account_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_transfers_household ON transfers(household_id, year, month);
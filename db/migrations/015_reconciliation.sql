@@ -0,0 +1,19 @@
+-- Checking an account against a bank statement. At most one reconciliation
+-- per account is in progress; completing it locks the expenses ticked off.
+CREATE TABLE IF NOT EXISTS reconciliations (
+    id SERIAL PRIMARY KEY,
+    household_id INTEGER NOT NULL REFERENCES households(id) ON DELETE CASCADE,
+    account_id INTEGER NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    statement_date DATE NOT NULL,
+    statement_balance DECIMAL(12, 2) NOT NULL,
+    completed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reconciliations_open ON reconciliations(account_id)
+    WHERE completed_at IS NULL;
+
+-- Cleared expenses have been ticked off against a statement; reconciled ones
+-- are locked against edits until explicitly unlocked
+ALTER TABLE expenses ADD COLUMN IF NOT EXISTS cleared BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE expenses ADD COLUMN IF NOT EXISTS reconciled_at TIMESTAMPTZ;
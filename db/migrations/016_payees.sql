@@ -0,0 +1,22 @@
+-- Who an expense was paid to. Descriptions are matched to a payee by its
+-- name and alias patterns, so "TESCO STORES 2231" and "Tesco Express" can
+-- both count as Tesco.
+CREATE TABLE IF NOT EXISTS payees (
+    id SERIAL PRIMARY KEY,
+    household_id INTEGER NOT NULL REFERENCES households(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    default_category_id INTEGER REFERENCES categories(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_payees_household_name ON payees(household_id, name);
+
+CREATE TABLE IF NOT EXISTS payee_aliases (
+    payee_id INTEGER NOT NULL REFERENCES payees(id) ON DELETE CASCADE,
+    pattern VARCHAR(100) NOT NULL,
+    PRIMARY KEY (payee_id, pattern)
+);
+
+ALTER TABLE expenses ADD COLUMN IF NOT EXISTS payee_id INTEGER REFERENCES payees(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_expenses_payee ON expenses(payee_id);
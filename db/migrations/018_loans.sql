@@ -0,0 +1,22 @@
+-- Loans and credit cards being paid off. The principal is what was owed at
+-- the start of the start period; interest accrues monthly at apr / 12.
+CREATE TABLE IF NOT EXISTS loans (
+    id SERIAL PRIMARY KEY,
+    household_id INTEGER NOT NULL REFERENCES households(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    principal DECIMAL(12, 2) NOT NULL CHECK (principal >= 0),
+    apr DECIMAL(6, 3) NOT NULL DEFAULT 0 CHECK (apr >= 0),
+    minimum_payment DECIMAL(12, 2) NOT NULL DEFAULT 0 CHECK (minimum_payment >= 0),
+    start_year INTEGER NOT NULL,
+    start_month INTEGER NOT NULL,
+    -- The recurring expense the regular repayments are made by, if any
+    recurring_expense_id INTEGER REFERENCES recurring_expenses(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_loans_household_name ON loans(household_id, name);
+
+-- Expenses that were extra payments toward a loan, on top of its regular ones
+ALTER TABLE expenses ADD COLUMN IF NOT EXISTS loan_id INTEGER REFERENCES loans(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_expenses_loan ON expenses(loan_id);
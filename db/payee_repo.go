@@ -0,0 +1,216 @@
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"spending-tracker/models"
+)
+
+// payeeSelect selects a payee with its alias patterns; pair with scanPayee
+const payeeSelect = `
+		SELECT p.id, p.name, p.default_category_id, p.created_at,
+		       COALESCE(array_agg(a.pattern ORDER BY a.pattern) FILTER (WHERE a.pattern IS NOT NULL), '{}')
+		FROM payees p
+		LEFT JOIN payee_aliases a ON a.payee_id = p.id`
+
+func scanPayee(row pgx.Row) (models.Payee, error) {
+	var p models.Payee
+	err := row.Scan(&p.ID, &p.Name, &p.DefaultCategoryID, &p.CreatedAt, &p.Aliases)
+	return p, err
+}
+
+func GetPayees(ctx context.Context, householdID int64) ([]models.Payee, error) {
+	rows, err := conn(ctx).Query(ctx, payeeSelect+`
+		WHERE p.household_id = $1
+		GROUP BY p.id
+		ORDER BY p.name
+	`, householdID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var payees []models.Payee
+	for rows.Next() {
+		p, err := scanPayee(rows)
+		if err != nil {
+			return nil, err
+		}
+		payees = append(payees, p)
+	}
+	return payees, rows.Err()
+}
+
+func GetPayee(ctx context.Context, householdID, id int64) (*models.Payee, error) {
+	p, err := scanPayee(conn(ctx).QueryRow(ctx, payeeSelect+`
+		WHERE p.household_id = $1 AND p.id = $2
+		GROUP BY p.id
+	`, householdID, id))
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// CreatePayee adds a payee with its aliases in one transaction
+func CreatePayee(ctx context.Context, householdID int64, payee models.Payee) (int64, error) {
+	tx, err := conn(ctx).Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	var id int64
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO payees (household_id, name, default_category_id)
+		VALUES ($1, $2, $3)
+		RETURNING id
+	`, householdID, payee.Name, payee.DefaultCategoryID).Scan(&id); err != nil {
+		return 0, err
+	}
+	if err := insertPayeeAliases(ctx, tx, id, payee.Aliases); err != nil {
+		return 0, err
+	}
+	return id, tx.Commit(ctx)
+}
+
+// UpdatePayee overwrites a payee's details and replaces its aliases,
+// reporting whether it existed
+func UpdatePayee(ctx context.Context, householdID int64, payee models.Payee) (bool, error) {
+	tx, err := conn(ctx).Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE payees SET name = $3, default_category_id = $4
+		WHERE household_id = $1 AND id = $2
+	`, householdID, payee.ID, payee.Name, payee.DefaultCategoryID)
+	if err != nil {
+		return false, err
+	}
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM payee_aliases WHERE payee_id = $1`, payee.ID); err != nil {
+		return false, err
+	}
+	if err := insertPayeeAliases(ctx, tx, payee.ID, payee.Aliases); err != nil {
+		return false, err
+	}
+	return true, tx.Commit(ctx)
+}
+
+func insertPayeeAliases(ctx context.Context, tx pgx.Tx, payeeID int64, aliases []string) error {
+	for _, alias := range aliases {
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO payee_aliases (payee_id, pattern) VALUES ($1, $2)
+			ON CONFLICT DO NOTHING
+		`, payeeID, alias); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeletePayee removes a payee, reporting whether it existed. Its expenses
+// are kept without a payee.
+func DeletePayee(ctx context.Context, householdID, id int64) (bool, error) {
+	tag, err := conn(ctx).Exec(ctx, `DELETE FROM payees WHERE household_id = $1 AND id = $2`, householdID, id)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// GetPayeeTotals returns what has been spent with each payee across all
+// periods, biggest first
+func GetPayeeTotals(ctx context.Context, householdID int64) ([]models.PayeeTotal, error) {
+	rows, err := conn(ctx).Query(ctx, `
+		SELECT p.id, p.name, p.default_category_id, p.created_at,
+		       COALESCE((SELECT array_agg(a.pattern ORDER BY a.pattern) FROM payee_aliases a WHERE a.payee_id = p.id), '{}'),
+		       COALESCE(SUM(e.amount), 0), COUNT(e.id)
+		FROM payees p
+		LEFT JOIN expenses e ON e.payee_id = p.id
+		WHERE p.household_id = $1
+		GROUP BY p.id
+		ORDER BY COALESCE(SUM(e.amount), 0) DESC, p.name
+	`, householdID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var totals []models.PayeeTotal
+	for rows.Next() {
+		var t models.PayeeTotal
+		if err := rows.Scan(&t.Payee.ID, &t.Payee.Name, &t.Payee.DefaultCategoryID, &t.Payee.CreatedAt,
+			&t.Payee.Aliases, &t.Total, &t.Count); err != nil {
+			return nil, err
+		}
+		totals = append(totals, t)
+	}
+	return totals, rows.Err()
+}
+
+// GetPayeePeriodTotals returns what was spent with a payee in each period,
+// latest first
+func GetPayeePeriodTotals(ctx context.Context, householdID, payeeID int64) ([]models.PayeePeriodTotal, error) {
+	rows, err := conn(ctx).Query(ctx, `
+		SELECT year, month, SUM(amount), COUNT(*)
+		FROM expenses
+		WHERE household_id = $1 AND payee_id = $2
+		GROUP BY year, month
+		ORDER BY year DESC, month DESC
+	`, householdID, payeeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var totals []models.PayeePeriodTotal
+	for rows.Next() {
+		var t models.PayeePeriodTotal
+		if err := rows.Scan(&t.Year, &t.Month, &t.Total, &t.Count); err != nil {
+			return nil, err
+		}
+		totals = append(totals, t)
+	}
+	return totals, rows.Err()
+}
+
+// GetExpensesToMatch returns the ID, description and payee of expenses
+// with no payee or with the given one, to be matched to payees again
+func GetExpensesToMatch(ctx context.Context, householdID, payeeID int64) ([]models.Expense, error) {
+	rows, err := conn(ctx).Query(ctx, `
+		SELECT id, description, payee_id
+		FROM expenses
+		WHERE household_id = $1 AND (payee_id IS NULL OR payee_id = $2)
+	`, householdID, payeeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var expenses []models.Expense
+	for rows.Next() {
+		var e models.Expense
+		if err := rows.Scan(&e.ID, &e.Description, &e.PayeeID); err != nil {
+			return nil, err
+		}
+		expenses = append(expenses, e)
+	}
+	return expenses, rows.Err()
+}
+
+// AssignPayee sets the payee of the given expenses; nil clears it
+func AssignPayee(ctx context.Context, householdID int64, payeeID *int64, expenseIDs []int64) error {
+	_, err := conn(ctx).Exec(ctx, `
+		UPDATE expenses SET payee_id = $2
+		WHERE household_id = $1 AND id = ANY($3)
+	`, householdID, payeeID, expenseIDs)
+	return err
+}
@@ -0,0 +1,153 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"spending-tracker/models"
+)
+
+// reconciliationSelect selects a reconciliation with its cleared balance;
+// pair with scanReconciliation. Income, transfers and ticked expenses up to
+// the statement's period count as cleared, as do reconciled expenses. A
+// completed reconciliation matched its statement, so its cleared balance is
+// the statement balance.
+const reconciliationSelect = `
+		SELECT r.id, r.account_id, r.statement_date, r.statement_balance, r.completed_at, r.created_at,
+		       CASE WHEN r.completed_at IS NOT NULL THEN r.statement_balance ELSE
+		           a.opening_balance
+		           + COALESCE((SELECT SUM(i.amount) FROM income i
+		                       WHERE i.account_id = r.account_id
+		                         AND (i.year, i.month) <= (EXTRACT(YEAR FROM r.statement_date)::int,
+		                                                   EXTRACT(MONTH FROM r.statement_date)::int)), 0)
+		           + COALESCE((SELECT SUM(CASE WHEN t.to_account_id = r.account_id THEN t.amount ELSE -t.amount END)
+		                       FROM transfers t
+		                       WHERE r.account_id IN (t.from_account_id, t.to_account_id)
+		                         AND (t.year, t.month) <= (EXTRACT(YEAR FROM r.statement_date)::int,
+		                                                   EXTRACT(MONTH FROM r.statement_date)::int)), 0)
+		           - COALESCE((SELECT SUM(e.amount) FROM expenses e
+		                       WHERE e.account_id = r.account_id AND e.cleared
+		                         AND (e.reconciled_at IS NOT NULL
+		                              OR (e.year, e.month) <= (EXTRACT(YEAR FROM r.statement_date)::int,
+		                                                       EXTRACT(MONTH FROM r.statement_date)::int))), 0)
+		       END
+		FROM reconciliations r
+		JOIN accounts a ON r.account_id = a.id`
+
+func scanReconciliation(row pgx.Row) (models.Reconciliation, error) {
+	var r models.Reconciliation
+	err := row.Scan(&r.ID, &r.AccountID, &r.StatementDate, &r.StatementBalance, &r.CompletedAt, &r.CreatedAt,
+		&r.ClearedBalance)
+	r.Difference = r.StatementBalance - r.ClearedBalance
+	return r, err
+}
+
+// GetOpenReconciliation returns the reconciliation in progress for an account
+func GetOpenReconciliation(ctx context.Context, householdID, accountID int64) (*models.Reconciliation, error) {
+	r, err := scanReconciliation(conn(ctx).QueryRow(ctx, reconciliationSelect+`
+		WHERE r.household_id = $1 AND r.account_id = $2 AND r.completed_at IS NULL
+	`, householdID, accountID))
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// GetCompletedReconciliations returns an account's completed reconciliations, latest first
+func GetCompletedReconciliations(ctx context.Context, householdID, accountID int64) ([]models.Reconciliation, error) {
+	rows, err := conn(ctx).Query(ctx, reconciliationSelect+`
+		WHERE r.household_id = $1 AND r.account_id = $2 AND r.completed_at IS NOT NULL
+		ORDER BY r.statement_date DESC, r.completed_at DESC
+	`, householdID, accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reconciliations []models.Reconciliation
+	for rows.Next() {
+		r, err := scanReconciliation(rows)
+		if err != nil {
+			return nil, err
+		}
+		reconciliations = append(reconciliations, r)
+	}
+	return reconciliations, rows.Err()
+}
+
+// SaveReconciliation starts reconciling an account against a statement, or
+// replaces the statement of the reconciliation already in progress
+func SaveReconciliation(ctx context.Context, householdID, accountID int64, statementDate time.Time, statementBalance float64) error {
+	_, err := conn(ctx).Exec(ctx, `
+		INSERT INTO reconciliations (household_id, account_id, statement_date, statement_balance)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (account_id) WHERE completed_at IS NULL
+		DO UPDATE SET statement_date = $3, statement_balance = $4
+	`, householdID, accountID, statementDate, statementBalance)
+	return err
+}
+
+// GetUnreconciledExpenses returns the expenses paid from an account up to a
+// period that have not been reconciled yet
+func GetUnreconciledExpenses(ctx context.Context, householdID, accountID int64, year, month int) ([]models.Expense, error) {
+	return queryExpenses(ctx, expenseSelect+`
+		WHERE e.household_id = $1 AND e.account_id = $2 AND e.reconciled_at IS NULL
+		  AND (e.year, e.month) <= ($3, $4)
+		ORDER BY e.year, e.month, e.created_at
+	`, householdID, accountID, year, month)
+}
+
+// SetExpenseCleared ticks an unreconciled expense off against a statement,
+// or unticks it, reporting whether the expense was found on the account
+func SetExpenseCleared(ctx context.Context, householdID, accountID, expenseID int64, cleared bool) (bool, error) {
+	tag, err := conn(ctx).Exec(ctx, `
+		UPDATE expenses SET cleared = $4
+		WHERE household_id = $1 AND account_id = $2 AND id = $3 AND reconciled_at IS NULL
+	`, householdID, accountID, expenseID, cleared)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// CompleteReconciliation locks the cleared expenses on the account up to the
+// statement's period and closes the reconciliation in progress, all in one
+// transaction
+func CompleteReconciliation(ctx context.Context, householdID, accountID int64, year, month int) error {
+	tx, err := conn(ctx).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE expenses SET reconciled_at = NOW()
+		WHERE household_id = $1 AND account_id = $2 AND cleared AND reconciled_at IS NULL
+		  AND (year, month) <= ($3, $4)
+	`, householdID, accountID, year, month); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE reconciliations SET completed_at = NOW()
+		WHERE household_id = $1 AND account_id = $2 AND completed_at IS NULL
+	`, householdID, accountID); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// UnlockExpense releases a reconciled expense for editing, reporting whether
+// it was locked. It is no longer cleared, so it must be ticked off again.
+func UnlockExpense(ctx context.Context, householdID, id int64) (bool, error) {
+	tag, err := conn(ctx).Exec(ctx, `
+		UPDATE expenses SET reconciled_at = NULL, cleared = FALSE
+		WHERE household_id = $1 AND id = $2 AND reconciled_at IS NOT NULL
+	`, householdID, id)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
@@ -3,17 +3,21 @@ package db
 import (
 	"context"
 	"spending-tracker/models"
+
+	"github.com/jackc/pgx/v5"
 )
 
-func GetActiveRecurringExpenses(ctx context.Context) ([]models.Expense, error) {
-	rows, err := Pool.Query(ctx, `
+func GetActiveRecurringExpenses(ctx context.Context, householdID int64) ([]models.Expense, error) {
+	rows, err := conn(ctx).Query(ctx, `
 		SELECT r.id, r.description, r.amount, r.category_id,
+		       (SELECT e.payee_id FROM expenses e WHERE e.recurring_expense_id = r.id
+		        ORDER BY e.year DESC, e.month DESC LIMIT 1),
 		       c.id, c.name, c.color
 		FROM recurring_expenses r
 		LEFT JOIN categories c ON r.category_id = c.id
-		WHERE r.is_active = true
+		WHERE r.household_id = $1 AND r.is_active = true
 		ORDER BY r.created_at
-	`)
+	`, householdID)
 	if err != nil {
 		return nil, err
 	}
@@ -27,7 +31,7 @@ func GetActiveRecurringExpenses(ctx context.Context) ([]models.Expense, error) {
 
 		if err := rows.Scan(
 			&e.RecurringExpenseID, &e.Description, &e.Amount, &e.CategoryID,
-			&cID, &catName, &catColor,
+			&e.PayeeID, &cID, &catName, &catColor,
 		); err != nil {
 			return nil, err
 		}
@@ -45,25 +49,28 @@ func GetActiveRecurringExpenses(ctx context.Context) ([]models.Expense, error) {
 	return expenses, rows.Err()
 }
 
-func IsMonthInitialized(ctx context.Context, year, month int) (bool, error) {
+func IsMonthInitialized(ctx context.Context, householdID int64, year, month int) (bool, error) {
 	var exists bool
-	err := Pool.QueryRow(ctx, `
-		SELECT EXISTS(SELECT 1 FROM initialized_months WHERE year = $1 AND month = $2)
-	`, year, month).Scan(&exists)
+	err := conn(ctx).QueryRow(ctx, `
+		SELECT EXISTS(
+			SELECT 1 FROM initialized_months
+			WHERE household_id = $1 AND year = $2 AND month = $3
+		)
+	`, householdID, year, month).Scan(&exists)
 	return exists, err
 }
 
-func MarkMonthInitialized(ctx context.Context, year, month int) error {
-	_, err := Pool.Exec(ctx, `
-		INSERT INTO initialized_months (year, month)
-		VALUES ($1, $2)
+func MarkMonthInitialized(ctx context.Context, householdID int64, year, month int) error {
+	_, err := conn(ctx).Exec(ctx, `
+		INSERT INTO initialized_months (household_id, year, month)
+		VALUES ($1, $2, $3)
 		ON CONFLICT DO NOTHING
-	`, year, month)
+	`, householdID, year, month)
 	return err
 }
 
-func InitializeMonth(ctx context.Context, year, month int) error {
-	initialized, err := IsMonthInitialized(ctx, year, month)
+func InitializeMonth(ctx context.Context, householdID int64, year, month int) error {
+	initialized, err := IsMonthInitialized(ctx, householdID, year, month)
 	if err != nil {
 		return err
 	}
@@ -71,7 +78,7 @@ func InitializeMonth(ctx context.Context, year, month int) error {
 		return nil
 	}
 
-	recurring, err := GetActiveRecurringExpenses(ctx)
+	recurring, err := GetActiveRecurringExpenses(ctx, householdID)
 	if err != nil {
 		return err
 	}
@@ -85,35 +92,109 @@ func InitializeMonth(ctx context.Context, year, month int) error {
 			Year:               year,
 			Month:              month,
 			RecurringExpenseID: r.RecurringExpenseID,
+			PayeeID:            r.PayeeID,
 		}
-		_, err := CreateExpense(ctx, expense)
+		_, err := CreateExpense(ctx, householdID, expense)
 		if err != nil {
 			return err
 		}
 	}
 
 	prevPeriod := models.Period{Year: year, Month: month}.Prev()
-	prevIncome, _ := GetIncomeByPeriod(ctx, prevPeriod.Year, prevPeriod.Month)
+	prevIncome, _ := GetIncomeByPeriod(ctx, householdID, prevPeriod.Year, prevPeriod.Month)
 	if prevIncome > 0 {
-		UpsertIncome(ctx, year, month, prevIncome)
+		UpsertIncome(ctx, householdID, year, month, prevIncome)
+		if accountID, _ := GetIncomeAccountID(ctx, householdID, prevPeriod.Year, prevPeriod.Month); accountID != nil {
+			SetIncomeAccount(ctx, householdID, year, month, accountID)
+		}
 	}
 
-	return MarkMonthInitialized(ctx, year, month)
+	return MarkMonthInitialized(ctx, householdID, year, month)
 }
 
-func CreateRecurringExpense(ctx context.Context, description string, amount float64, categoryID *int64) (int64, error) {
+func CreateRecurringExpense(ctx context.Context, householdID int64, description string, amount float64, categoryID *int64) (int64, error) {
 	var id int64
-	err := Pool.QueryRow(ctx, `
-		INSERT INTO recurring_expenses (description, amount, category_id)
-		VALUES ($1, $2, $3)
+	err := conn(ctx).QueryRow(ctx, `
+		INSERT INTO recurring_expenses (household_id, description, amount, category_id)
+		VALUES ($1, $2, $3, $4)
 		RETURNING id
-	`, description, amount, categoryID).Scan(&id)
+	`, householdID, description, amount, categoryID).Scan(&id)
 	return id, err
 }
 
-func DeleteRecurringExpense(ctx context.Context, id int64) error {
-	_, err := Pool.Exec(ctx, `
-		UPDATE recurring_expenses SET is_active = false WHERE id = $1
-	`, id)
+func DeleteRecurringExpense(ctx context.Context, householdID, id int64) error {
+	_, err := conn(ctx).Exec(ctx, `
+		UPDATE recurring_expenses SET is_active = false WHERE household_id = $1 AND id = $2
+	`, householdID, id)
+	return err
+}
+
+// recurringSelect selects a recurring template joined with its category; pair with scanRecurringExpense
+const recurringSelect = `
+		SELECT r.id, r.description, r.amount, r.category_id, r.is_active, r.created_at, r.updated_at,
+		       c.id, c.name, c.color
+		FROM recurring_expenses r
+		LEFT JOIN categories c ON r.category_id = c.id`
+
+func scanRecurringExpense(row pgx.Row) (models.RecurringExpense, error) {
+	var r models.RecurringExpense
+	var cID *int64
+	var catName, catColor *string
+
+	if err := row.Scan(
+		&r.ID, &r.Description, &r.Amount, &r.CategoryID, &r.IsActive, &r.CreatedAt, &r.UpdatedAt,
+		&cID, &catName, &catColor,
+	); err != nil {
+		return r, err
+	}
+
+	if cID != nil && catName != nil && catColor != nil {
+		r.Category = &models.Category{
+			ID:    *cID,
+			Name:  *catName,
+			Color: *catColor,
+		}
+	}
+	return r, nil
+}
+
+// GetRecurringExpenses returns all recurring templates, active ones first
+func GetRecurringExpenses(ctx context.Context, householdID int64) ([]models.RecurringExpense, error) {
+	rows, err := conn(ctx).Query(ctx, recurringSelect+`
+		WHERE r.household_id = $1
+		ORDER BY r.is_active DESC, r.created_at
+	`, householdID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var templates []models.RecurringExpense
+	for rows.Next() {
+		r, err := scanRecurringExpense(rows)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, r)
+	}
+	return templates, rows.Err()
+}
+
+func GetRecurringExpenseByID(ctx context.Context, householdID, id int64) (*models.RecurringExpense, error) {
+	r, err := scanRecurringExpense(conn(ctx).QueryRow(ctx, recurringSelect+`
+		WHERE r.household_id = $1 AND r.id = $2
+	`, householdID, id))
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+func UpdateRecurringExpense(ctx context.Context, householdID, id int64, description string, amount float64, categoryID *int64, isActive bool) error {
+	_, err := conn(ctx).Exec(ctx, `
+		UPDATE recurring_expenses
+		SET description = $3, amount = $4, category_id = $5, is_active = $6, updated_at = NOW()
+		WHERE household_id = $1 AND id = $2
+	`, householdID, id, description, amount, categoryID, isActive)
 	return err
 }
@@ -0,0 +1,82 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"spending-tracker/models"
+)
+
+// likeEscaper escapes LIKE wildcards so user input matches literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// SearchExpenses finds expenses across all periods matching the search,
+// newest period first, returning one page plus the overall count and sum
+func SearchExpenses(ctx context.Context, householdID int64, search models.ExpenseSearch) (models.SearchResults, error) {
+	var conditions []string
+	var args []any
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	conditions = append(conditions, "e.household_id = "+arg(householdID))
+
+	if q := strings.TrimSpace(search.Query); q != "" {
+		conditions = append(conditions, "e.description ILIKE '%' || "+arg(likeEscaper.Replace(q))+" || '%'")
+	}
+	if search.MinAmount != nil {
+		conditions = append(conditions, "e.amount >= "+arg(*search.MinAmount))
+	}
+	if search.MaxAmount != nil {
+		conditions = append(conditions, "e.amount <= "+arg(*search.MaxAmount))
+	}
+	if search.CategoryID != nil {
+		// Searching a parent category includes its subcategories and split lines in either
+		p := arg(*search.CategoryID)
+		conditions = append(conditions, `(e.category_id = `+p+` OR c.parent_id = `+p+` OR EXISTS (
+			SELECT 1 FROM expense_splits s
+			JOIN categories sc ON sc.id = s.category_id
+			WHERE s.expense_id = e.id AND (sc.id = `+p+` OR sc.parent_id = `+p+`)))`)
+	}
+	if search.Type != "" {
+		conditions = append(conditions, "e.expense_type = "+arg(search.Type))
+	}
+	if search.Tag != "" {
+		conditions = append(conditions, `EXISTS (
+			SELECT 1 FROM expense_tags et
+			JOIN tags t ON t.id = et.tag_id
+			WHERE et.expense_id = e.id AND t.name = `+arg(search.Tag)+`)`)
+	}
+	if search.From != nil {
+		conditions = append(conditions, "e.year * 12 + (e.month - 1) >= "+arg(search.From.Index()))
+	}
+	if search.To != nil {
+		conditions = append(conditions, "e.year * 12 + (e.month - 1) <= "+arg(search.To.Index()))
+	}
+
+	where := "WHERE " + strings.Join(conditions, " AND ")
+
+	results := models.SearchResults{Search: search}
+	err := conn(ctx).QueryRow(ctx, `
+		SELECT COUNT(*), COALESCE(SUM(e.amount), 0)
+		FROM expenses e
+		LEFT JOIN categories c ON e.category_id = c.id
+		`+where, args...).Scan(&results.Total, &results.Sum)
+	if err != nil {
+		return results, err
+	}
+
+	offset := (search.Page - 1) * models.SearchPageSize
+	query := expenseSelect + `
+		` + where + `
+		ORDER BY e.year DESC, e.month DESC, e.created_at DESC
+		LIMIT ` + arg(models.SearchPageSize) + ` OFFSET ` + arg(offset)
+	expenses, err := queryExpenses(ctx, query, args...)
+	if err != nil {
+		return results, err
+	}
+	results.Expenses = expenses
+	return results, nil
+}
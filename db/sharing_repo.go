@@ -0,0 +1,254 @@
+package db
+
+import (
+	"context"
+
+	"spending-tracker/models"
+)
+
+func GetPeople(ctx context.Context, householdID int64) ([]models.Person, error) {
+	rows, err := conn(ctx).Query(ctx, `
+		SELECT id, name, user_id, created_at
+		FROM people
+		WHERE household_id = $1
+		ORDER BY name
+	`, householdID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var people []models.Person
+	for rows.Next() {
+		var p models.Person
+		if err := rows.Scan(&p.ID, &p.Name, &p.UserID, &p.CreatedAt); err != nil {
+			return nil, err
+		}
+		people = append(people, p)
+	}
+	return people, rows.Err()
+}
+
+func GetPerson(ctx context.Context, householdID, id int64) (*models.Person, error) {
+	var p models.Person
+	err := conn(ctx).QueryRow(ctx, `
+		SELECT id, name, user_id, created_at
+		FROM people
+		WHERE household_id = $1 AND id = $2
+	`, householdID, id).Scan(&p.ID, &p.Name, &p.UserID, &p.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// GetPersonForUser returns the person linked to a user in a household
+func GetPersonForUser(ctx context.Context, householdID, userID int64) (*models.Person, error) {
+	var p models.Person
+	err := conn(ctx).QueryRow(ctx, `
+		SELECT id, name, user_id, created_at
+		FROM people
+		WHERE household_id = $1 AND user_id = $2
+	`, householdID, userID).Scan(&p.ID, &p.Name, &p.UserID, &p.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func CreatePerson(ctx context.Context, householdID int64, name string, userID *int64) (*models.Person, error) {
+	var p models.Person
+	err := conn(ctx).QueryRow(ctx, `
+		INSERT INTO people (household_id, name, user_id)
+		VALUES ($1, $2, $3)
+		RETURNING id, name, user_id, created_at
+	`, householdID, name, userID).Scan(&p.ID, &p.Name, &p.UserID, &p.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// DeletePerson removes a person; the database rejects people still named
+// on shared expenses or settlements
+func DeletePerson(ctx context.Context, householdID, id int64) error {
+	_, err := conn(ctx).Exec(ctx, `DELETE FROM people WHERE household_id = $1 AND id = $2`, householdID, id)
+	return err
+}
+
+// SetExpenseSharing records who paid an expense and replaces its shares,
+// bumping the expense's version. An empty method stops sharing it.
+func SetExpenseSharing(ctx context.Context, householdID, expenseID int64, paidBy *int64, method models.ShareMethod, shares []models.ExpenseShare) error {
+	tx, err := conn(ctx).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var shareMethod *models.ShareMethod
+	if method != "" {
+		shareMethod = &method
+	}
+	tag, err := tx.Exec(ctx, `
+		UPDATE expenses
+		SET paid_by = $3, share_method = $4, version = version + 1, updated_at = NOW()
+		WHERE household_id = $1 AND id = $2
+	`, householdID, expenseID, paidBy, shareMethod)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return nil
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM expense_shares WHERE expense_id = $1`, expenseID); err != nil {
+		return err
+	}
+	for _, s := range shares {
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO expense_shares (expense_id, person_id, value, amount)
+			VALUES ($1, $2, $3, $4)
+		`, expenseID, s.PersonID, s.Value, s.Amount); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// attachShares loads the shares of each shared expense in place
+func attachShares(ctx context.Context, expenses []models.Expense) error {
+	index := make(map[int64]int, len(expenses))
+	var ids []int64
+	for i, e := range expenses {
+		if e.ShareMethod != "" {
+			ids = append(ids, e.ID)
+			index[e.ID] = i
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	rows, err := conn(ctx).Query(ctx, `
+		SELECT s.expense_id, s.person_id, p.name, s.value, s.amount
+		FROM expense_shares s
+		JOIN people p ON p.id = s.person_id
+		WHERE s.expense_id = ANY($1)
+		ORDER BY p.name
+	`, ids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var expenseID int64
+		var s models.ExpenseShare
+		if err := rows.Scan(&expenseID, &s.PersonID, &s.PersonName, &s.Value, &s.Amount); err != nil {
+			return err
+		}
+		i := index[expenseID]
+		expenses[i].Shares = append(expenses[i].Shares, s)
+	}
+	return rows.Err()
+}
+
+// GetBalances returns each person's net position across all shared expenses
+// and settlements: what they paid for others less what others paid for them
+func GetBalances(ctx context.Context, householdID int64) ([]models.Balance, error) {
+	rows, err := conn(ctx).Query(ctx, `
+		SELECT p.id, p.name, p.user_id, p.created_at,
+		       COALESCE((SELECT SUM(e.amount) FROM expenses e
+		                 WHERE e.household_id = $1 AND e.paid_by = p.id AND e.share_method IS NOT NULL), 0)
+		     - COALESCE((SELECT SUM(s.amount) FROM expense_shares s
+		                 JOIN expenses e ON e.id = s.expense_id
+		                 WHERE e.household_id = $1 AND s.person_id = p.id), 0)
+		     + COALESCE((SELECT SUM(amount) FROM settlements
+		                 WHERE household_id = $1 AND from_person_id = p.id), 0)
+		     - COALESCE((SELECT SUM(amount) FROM settlements
+		                 WHERE household_id = $1 AND to_person_id = p.id), 0)
+		FROM people p
+		WHERE p.household_id = $1
+		ORDER BY p.name
+	`, householdID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var balances []models.Balance
+	for rows.Next() {
+		var b models.Balance
+		if err := rows.Scan(&b.Person.ID, &b.Person.Name, &b.Person.UserID, &b.Person.CreatedAt, &b.Net); err != nil {
+			return nil, err
+		}
+		balances = append(balances, b)
+	}
+	return balances, rows.Err()
+}
+
+const settlementSelect = `
+		SELECT s.id, s.amount, s.note, s.created_at,
+		       f.id, f.name, f.user_id, f.created_at,
+		       t.id, t.name, t.user_id, t.created_at
+		FROM settlements s
+		JOIN people f ON f.id = s.from_person_id
+		JOIN people t ON t.id = s.to_person_id`
+
+func GetSettlements(ctx context.Context, householdID int64) ([]models.Settlement, error) {
+	rows, err := conn(ctx).Query(ctx, settlementSelect+`
+		WHERE s.household_id = $1
+		ORDER BY s.created_at DESC
+	`, householdID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var settlements []models.Settlement
+	for rows.Next() {
+		var s models.Settlement
+		if err := rows.Scan(&s.ID, &s.Amount, &s.Note, &s.CreatedAt,
+			&s.FromPerson.ID, &s.FromPerson.Name, &s.FromPerson.UserID, &s.FromPerson.CreatedAt,
+			&s.ToPerson.ID, &s.ToPerson.Name, &s.ToPerson.UserID, &s.ToPerson.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		settlements = append(settlements, s)
+	}
+	return settlements, rows.Err()
+}
+
+func CreateSettlement(ctx context.Context, householdID, fromID, toID int64, amount float64, note string) (int64, error) {
+	var id int64
+	err := conn(ctx).QueryRow(ctx, `
+		INSERT INTO settlements (household_id, from_person_id, to_person_id, amount, note)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`, householdID, fromID, toID, amount, note).Scan(&id)
+	return id, err
+}
+
+// DeleteSettlement removes a settlement, reporting whether it existed
+func DeleteSettlement(ctx context.Context, householdID, id int64) (bool, error) {
+	tag, err := conn(ctx).Exec(ctx, `DELETE FROM settlements WHERE household_id = $1 AND id = $2`, householdID, id)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func GetSettlement(ctx context.Context, householdID, id int64) (*models.Settlement, error) {
+	var s models.Settlement
+	err := conn(ctx).QueryRow(ctx, settlementSelect+`
+		WHERE s.household_id = $1 AND s.id = $2
+	`, householdID, id).Scan(&s.ID, &s.Amount, &s.Note, &s.CreatedAt,
+		&s.FromPerson.ID, &s.FromPerson.Name, &s.FromPerson.UserID, &s.FromPerson.CreatedAt,
+		&s.ToPerson.ID, &s.ToPerson.Name, &s.ToPerson.UserID, &s.ToPerson.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
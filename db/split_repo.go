@@ -0,0 +1,81 @@
+package db
+
+import (
+	"context"
+
+	"spending-tracker/models"
+)
+
+// SetExpenseSplits replaces an expense's split lines; no lines removes the split.
+// Lines naming a category that no longer exists in the household are kept uncategorised.
+func SetExpenseSplits(ctx context.Context, householdID, expenseID int64, splits []models.ExpenseSplit) error {
+	tx, err := conn(ctx).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `
+		DELETE FROM expense_splits
+		WHERE expense_id = (SELECT id FROM expenses WHERE household_id = $1 AND id = $2)
+	`, householdID, expenseID); err != nil {
+		return err
+	}
+
+	for i, s := range splits {
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO expense_splits (expense_id, category_id, amount, note, position)
+			SELECT e.id, (SELECT id FROM categories WHERE household_id = $1 AND id = $3), $4, $5, $6
+			FROM expenses e
+			WHERE e.household_id = $1 AND e.id = $2
+		`, householdID, expenseID, s.CategoryID, s.Amount, s.Note, i); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// attachSplits loads the split lines for each expense in place
+func attachSplits(ctx context.Context, expenses []models.Expense) error {
+	if len(expenses) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(expenses))
+	index := make(map[int64]int, len(expenses))
+	for i, e := range expenses {
+		ids[i] = e.ID
+		index[e.ID] = i
+	}
+
+	rows, err := conn(ctx).Query(ctx, `
+		SELECT s.expense_id, s.id, s.category_id, s.amount, s.note,
+		       c.id, c.name, c.color, c.parent_id
+		FROM expense_splits s
+		LEFT JOIN categories c ON s.category_id = c.id
+		WHERE s.expense_id = ANY($1)
+		ORDER BY s.position, s.id
+	`, ids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var expenseID int64
+		var s models.ExpenseSplit
+		var cID, cParentID *int64
+		var catName, catColor *string
+		if err := rows.Scan(&expenseID, &s.ID, &s.CategoryID, &s.Amount, &s.Note,
+			&cID, &catName, &catColor, &cParentID); err != nil {
+			return err
+		}
+		if cID != nil && catName != nil && catColor != nil {
+			s.Category = &models.Category{ID: *cID, Name: *catName, Color: *catColor, ParentID: cParentID}
+		}
+		i := index[expenseID]
+		expenses[i].Splits = append(expenses[i].Splits, s)
+	}
+	return rows.Err()
+}
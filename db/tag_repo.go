@@ -0,0 +1,157 @@
+package db
+
+import (
+	"context"
+
+	"spending-tracker/models"
+)
+
+func GetAllTags(ctx context.Context, householdID int64) ([]models.Tag, error) {
+	rows, err := conn(ctx).Query(ctx, `
+		SELECT id, name, created_at
+		FROM tags
+		WHERE household_id = $1
+		ORDER BY name
+	`, householdID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tags []models.Tag
+	for rows.Next() {
+		var t models.Tag
+		if err := rows.Scan(&t.ID, &t.Name, &t.CreatedAt); err != nil {
+			return nil, err
+		}
+		tags = append(tags, t)
+	}
+	return tags, rows.Err()
+}
+
+// SearchTags returns tags starting with prefix, most used first
+func SearchTags(ctx context.Context, householdID int64, prefix string, limit int) ([]models.Tag, error) {
+	rows, err := conn(ctx).Query(ctx, `
+		SELECT t.id, t.name, t.created_at
+		FROM tags t
+		LEFT JOIN expense_tags et ON et.tag_id = t.id
+		WHERE t.household_id = $1 AND t.name LIKE $2 || '%'
+		GROUP BY t.id
+		ORDER BY COUNT(et.expense_id) DESC, t.name
+		LIMIT $3
+	`, householdID, likeEscaper.Replace(prefix), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tags []models.Tag
+	for rows.Next() {
+		var t models.Tag
+		if err := rows.Scan(&t.ID, &t.Name, &t.CreatedAt); err != nil {
+			return nil, err
+		}
+		tags = append(tags, t)
+	}
+	return tags, rows.Err()
+}
+
+// SetExpenseTags replaces an expense's tags, creating any new tag names
+func SetExpenseTags(ctx context.Context, householdID, expenseID int64, names []string) error {
+	tx, err := conn(ctx).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM expense_tags WHERE expense_id = $1`, expenseID); err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		var tagID int64
+		err := tx.QueryRow(ctx, `
+			INSERT INTO tags (household_id, name)
+			VALUES ($1, $2)
+			ON CONFLICT (household_id, name) DO UPDATE SET name = EXCLUDED.name
+			RETURNING id
+		`, householdID, name).Scan(&tagID)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO expense_tags (expense_id, tag_id)
+			VALUES ($1, $2)
+			ON CONFLICT DO NOTHING
+		`, expenseID, tagID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetTagTotals sums tagged expenses between two periods, inclusive
+func GetTagTotals(ctx context.Context, householdID int64, from, to models.Period) ([]models.TagTotal, error) {
+	rows, err := conn(ctx).Query(ctx, `
+		SELECT t.id, t.name, t.created_at, SUM(e.amount), COUNT(e.id)
+		FROM tags t
+		JOIN expense_tags et ON et.tag_id = t.id
+		JOIN expenses e ON e.id = et.expense_id
+		WHERE t.household_id = $1 AND e.year * 12 + (e.month - 1) BETWEEN $2 AND $3
+		GROUP BY t.id
+		ORDER BY SUM(e.amount) DESC, t.name
+	`, householdID, from.Index(), to.Index())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var totals []models.TagTotal
+	for rows.Next() {
+		var tt models.TagTotal
+		if err := rows.Scan(&tt.Tag.ID, &tt.Tag.Name, &tt.Tag.CreatedAt, &tt.Total, &tt.Count); err != nil {
+			return nil, err
+		}
+		totals = append(totals, tt)
+	}
+	return totals, rows.Err()
+}
+
+// attachTags loads the tags for each expense in place
+func attachTags(ctx context.Context, expenses []models.Expense) error {
+	if len(expenses) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(expenses))
+	index := make(map[int64]int, len(expenses))
+	for i, e := range expenses {
+		ids[i] = e.ID
+		index[e.ID] = i
+	}
+
+	rows, err := conn(ctx).Query(ctx, `
+		SELECT et.expense_id, t.id, t.name, t.created_at
+		FROM expense_tags et
+		JOIN tags t ON t.id = et.tag_id
+		WHERE et.expense_id = ANY($1)
+		ORDER BY t.name
+	`, ids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var expenseID int64
+		var t models.Tag
+		if err := rows.Scan(&expenseID, &t.ID, &t.Name, &t.CreatedAt); err != nil {
+			return err
+		}
+		i := index[expenseID]
+		expenses[i].Tags = append(expenses[i].Tags, t)
+	}
+	return rows.Err()
+}
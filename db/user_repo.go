@@ -0,0 +1,142 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"spending-tracker/models"
+)
+
+func CountUsers(ctx context.Context) (int, error) {
+	var count int
+	err := conn(ctx).QueryRow(ctx, `SELECT COUNT(*) FROM users`).Scan(&count)
+	return count, err
+}
+
+func GetAllUsers(ctx context.Context) ([]models.User, error) {
+	rows, err := conn(ctx).Query(ctx, `
+		SELECT id, username, password_hash, is_admin, created_at
+		FROM users
+		ORDER BY username
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []models.User
+	for rows.Next() {
+		var u models.User
+		if err := rows.Scan(&u.ID, &u.Username, &u.PasswordHash, &u.IsAdmin, &u.CreatedAt); err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+	return users, rows.Err()
+}
+
+func GetUserByUsername(ctx context.Context, username string) (*models.User, error) {
+	var u models.User
+	err := conn(ctx).QueryRow(ctx, `
+		SELECT id, username, password_hash, is_admin, created_at
+		FROM users
+		WHERE username = $1
+	`, username).Scan(&u.ID, &u.Username, &u.PasswordHash, &u.IsAdmin, &u.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+func CreateUser(ctx context.Context, username, passwordHash string, isAdmin bool) (*models.User, error) {
+	var u models.User
+	err := conn(ctx).QueryRow(ctx, `
+		INSERT INTO users (username, password_hash, is_admin)
+		VALUES ($1, $2, $3)
+		RETURNING id, username, password_hash, is_admin, created_at
+	`, username, passwordHash, isAdmin).Scan(&u.ID, &u.Username, &u.PasswordHash, &u.IsAdmin, &u.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// CreateFirstUser creates the initial admin only if no users exist yet,
+// reporting false if another request got there first. The admin becomes
+// owner of any household left without members.
+func CreateFirstUser(ctx context.Context, username, passwordHash string) (*models.User, bool, error) {
+	tx, err := conn(ctx).Begin(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Serialize concurrent setup attempts
+	if _, err := tx.Exec(ctx, `LOCK TABLE users IN EXCLUSIVE MODE`); err != nil {
+		return nil, false, err
+	}
+
+	var count int
+	if err := tx.QueryRow(ctx, `SELECT COUNT(*) FROM users`).Scan(&count); err != nil {
+		return nil, false, err
+	}
+	if count > 0 {
+		return nil, false, nil
+	}
+
+	var u models.User
+	err = tx.QueryRow(ctx, `
+		INSERT INTO users (username, password_hash, is_admin)
+		VALUES ($1, $2, true)
+		RETURNING id, username, password_hash, is_admin, created_at
+	`, username, passwordHash).Scan(&u.ID, &u.Username, &u.PasswordHash, &u.IsAdmin, &u.CreatedAt)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Households migrated from before accounts existed have no members yet
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO household_members (household_id, user_id, role)
+		SELECT h.id, $1, 'owner'
+		FROM households h
+		WHERE NOT EXISTS (SELECT 1 FROM household_members m WHERE m.household_id = h.id)
+	`, u.ID); err != nil {
+		return nil, false, err
+	}
+
+	return &u, true, tx.Commit(ctx)
+}
+
+func CreateSession(ctx context.Context, tokenHash string, userID int64, expiresAt time.Time) error {
+	_, err := conn(ctx).Exec(ctx, `
+		INSERT INTO sessions (token_hash, user_id, expires_at)
+		VALUES ($1, $2, $3)
+	`, tokenHash, userID, expiresAt)
+	return err
+}
+
+// GetSessionUser returns the user for an unexpired session and records the access
+func GetSessionUser(ctx context.Context, tokenHash string) (*models.User, error) {
+	var u models.User
+	err := conn(ctx).QueryRow(ctx, `
+		UPDATE sessions s
+		SET last_seen_at = NOW()
+		FROM users u
+		WHERE s.token_hash = $1 AND s.expires_at > NOW() AND u.id = s.user_id
+		RETURNING u.id, u.username, u.password_hash, u.is_admin, u.created_at
+	`, tokenHash).Scan(&u.ID, &u.Username, &u.PasswordHash, &u.IsAdmin, &u.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+func DeleteSession(ctx context.Context, tokenHash string) error {
+	_, err := conn(ctx).Exec(ctx, `DELETE FROM sessions WHERE token_hash = $1`, tokenHash)
+	return err
+}
+
+func DeleteExpiredSessions(ctx context.Context) error {
+	_, err := conn(ctx).Exec(ctx, `DELETE FROM sessions WHERE expires_at <= NOW()`)
+	return err
+}
@@ -0,0 +1,215 @@
+// Package analytics finds unusual spending by comparing a period's expenses
+// with the household's history before it.
+package analytics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"spending-tracker/models"
+)
+
+const (
+	// TrailingPeriods is how many periods before the one checked make up
+	// the history it is compared with
+	TrailingPeriods = 6
+	// MinHistory is how many earlier periods, or earlier expenses for a
+	// payee or category, are needed before anything is called unusual
+	MinHistory = 3
+	// SpikeFactor is how many times its trailing average a category's
+	// spending must reach to be a spike
+	SpikeFactor = 1.5
+	// LargeFactor is how many times the typical amount an expense must reach
+	// to be unusually large
+	LargeFactor = 3.0
+	// MinDifference is the least amount above usual that is worth flagging,
+	// so small categories and payees do not trip the factors on pennies
+	MinDifference = 50.0
+)
+
+// Detect finds the anomalies in a period's expenses, given the expenses of
+// the TrailingPeriods before it. Category spikes come first, then anomalies
+// about single expenses in the order the expenses are given.
+func Detect(current, history []models.Expense) []models.Anomaly {
+	anomalies := CategorySpikes(current, history)
+	for _, e := range current {
+		if a, ok := largeExpense(e, history); ok {
+			anomalies = append(anomalies, a)
+		}
+		if a, ok := recurringChange(e, history); ok {
+			anomalies = append(anomalies, a)
+		}
+	}
+	return anomalies
+}
+
+// Annotate attaches each expense's anomalies to it
+func Annotate(expenses []models.Expense, anomalies []models.Anomaly) {
+	byExpense := make(map[int64][]models.Anomaly)
+	for _, a := range anomalies {
+		if a.ExpenseID != nil {
+			byExpense[*a.ExpenseID] = append(byExpense[*a.ExpenseID], a)
+		}
+	}
+	for i := range expenses {
+		expenses[i].Anomalies = byExpense[expenses[i].ID]
+	}
+}
+
+// CategorySpikes finds the categories whose spending in a period is far above
+// their average over the earlier periods with any spending, largest rise
+// first
+func CategorySpikes(current, history []models.Expense) []models.Anomaly {
+	periods := make(map[int]bool)
+	for _, e := range history {
+		periods[periodOf(e).Index()] = true
+	}
+	if len(periods) < MinHistory {
+		return nil
+	}
+
+	before, earlierNames := categoryTotals(history)
+	now, names := categoryTotals(current)
+
+	var spikes []models.Anomaly
+	for id, total := range now {
+		average := before[id] / float64(len(periods))
+		if total < average*SpikeFactor || total-average < MinDifference {
+			continue
+		}
+		name := names[id]
+		if name == "" {
+			name = earlierNames[id]
+		}
+		categoryID := id
+		spikes = append(spikes, models.Anomaly{
+			Kind:       models.AnomalyCategorySpike,
+			CategoryID: &categoryID,
+			Subject:    name,
+			Amount:     roundCents(total),
+			Usual:      roundCents(average),
+			Basis:      fmt.Sprintf("over the last %d months", len(periods)),
+		})
+	}
+	sort.Slice(spikes, func(i, j int) bool {
+		return spikes[i].Amount-spikes[i].Usual > spikes[j].Amount-spikes[j].Usual
+	})
+	return spikes
+}
+
+// categoryTotals sums spending by category, refunds included, with each
+// category's name
+func categoryTotals(expenses []models.Expense) (map[int64]float64, map[int64]string) {
+	totals := make(map[int64]float64)
+	names := make(map[int64]string)
+	for _, e := range expenses {
+		for _, a := range e.Allocations() {
+			if a.CategoryID == nil {
+				continue
+			}
+			totals[*a.CategoryID] += a.Amount
+			if a.Category != nil {
+				names[*a.CategoryID] = a.Category.Name
+			}
+		}
+	}
+	return totals, names
+}
+
+// largeExpense checks a one-time expense against the typical earlier
+// expense for its payee or, without enough of those, its category
+func largeExpense(e models.Expense, history []models.Expense) (models.Anomaly, bool) {
+	if e.Type != models.ExpenseTypeOneTime || e.Amount <= 0 || e.IsRefund() {
+		return models.Anomaly{}, false
+	}
+
+	var byPayee, byCategory []float64
+	for _, h := range history {
+		if h.Type != models.ExpenseTypeOneTime || h.Amount <= 0 || h.IsRefund() {
+			continue
+		}
+		if e.PayeeID != nil && h.PayeeID != nil && *e.PayeeID == *h.PayeeID {
+			byPayee = append(byPayee, h.Amount)
+		}
+		if e.CategoryID != nil && h.CategoryID != nil && *e.CategoryID == *h.CategoryID {
+			byCategory = append(byCategory, h.Amount)
+		}
+	}
+
+	amounts, basis := byPayee, "at "+e.PayeeName
+	if len(amounts) < MinHistory {
+		amounts, basis = byCategory, "in this category"
+		if e.Category != nil {
+			basis = "in " + e.Category.Name
+		}
+	}
+	if len(amounts) < MinHistory {
+		return models.Anomaly{}, false
+	}
+
+	usual := median(amounts)
+	if e.Amount < usual*LargeFactor || e.Amount-usual < MinDifference {
+		return models.Anomaly{}, false
+	}
+	id := e.ID
+	return models.Anomaly{
+		Kind:       models.AnomalyLargeExpense,
+		ExpenseID:  &id,
+		CategoryID: e.CategoryID,
+		Subject:    e.Description,
+		Amount:     e.Amount,
+		Usual:      roundCents(usual),
+		Basis:      basis,
+	}, true
+}
+
+// recurringChange checks a recurring charge against the latest earlier
+// charge of the same recurring expense
+func recurringChange(e models.Expense, history []models.Expense) (models.Anomaly, bool) {
+	if e.RecurringExpenseID == nil {
+		return models.Anomaly{}, false
+	}
+
+	var previous *models.Expense
+	for i, h := range history {
+		if h.RecurringExpenseID == nil || *h.RecurringExpenseID != *e.RecurringExpenseID {
+			continue
+		}
+		if previous == nil || periodOf(h).Index() > periodOf(*previous).Index() {
+			previous = &history[i]
+		}
+	}
+	if previous == nil || math.Abs(e.Amount-previous.Amount) < 0.005 {
+		return models.Anomaly{}, false
+	}
+
+	id := e.ID
+	return models.Anomaly{
+		Kind:       models.AnomalyRecurringChange,
+		ExpenseID:  &id,
+		CategoryID: e.CategoryID,
+		Subject:    e.Description,
+		Amount:     e.Amount,
+		Usual:      previous.Amount,
+		Basis:      fmt.Sprintf("in %s %d", periodOf(*previous).MonthName(), previous.Year),
+	}, true
+}
+
+func periodOf(e models.Expense) models.Period {
+	return models.Period{Year: e.Year, Month: e.Month}
+}
+
+func median(amounts []float64) float64 {
+	sorted := append([]float64(nil), amounts...)
+	sort.Float64s(sorted)
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
+
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
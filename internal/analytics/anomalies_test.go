@@ -0,0 +1,240 @@
+package analytics
+
+import (
+	"testing"
+
+	"spending-tracker/models"
+)
+
+var (
+	groceries = &models.Category{ID: 1, Name: "Groceries"}
+	eatingOut = &models.Category{ID: 2, Name: "Eating out"}
+)
+
+// spend builds a one-time expense in a month of 2026
+func spend(id int64, month int, category *models.Category, amount float64) models.Expense {
+	e := models.Expense{ID: id, Description: "Expense", Type: models.ExpenseTypeOneTime, Amount: amount, Year: 2026, Month: month}
+	if category != nil {
+		e.CategoryID, e.Category = &category.ID, category
+	}
+	return e
+}
+
+func atPayee(e models.Expense, payeeID int64, name string) models.Expense {
+	e.PayeeID, e.PayeeName = &payeeID, name
+	return e
+}
+
+func charge(id int64, month int, recurringID int64, amount float64) models.Expense {
+	return models.Expense{ID: id, Description: "Streaming", Type: models.ExpenseTypeRecurring, Amount: amount,
+		Year: 2026, Month: month, RecurringExpenseID: &recurringID}
+}
+
+func TestCategorySpikes(t *testing.T) {
+	tests := []struct {
+		name      string
+		history   []models.Expense
+		current   []models.Expense
+		wantUsual []float64
+	}{
+		{
+			name:      "spending past the factor and the minimum difference is a spike",
+			history:   []models.Expense{spend(1, 4, groceries, 100), spend(2, 5, groceries, 100), spend(3, 6, groceries, 100)},
+			current:   []models.Expense{spend(4, 7, groceries, 200)},
+			wantUsual: []float64{100},
+		},
+		{
+			name:    "fewer than MinHistory earlier periods is not enough to judge",
+			history: []models.Expense{spend(1, 5, groceries, 100), spend(2, 6, groceries, 100)},
+			current: []models.Expense{spend(3, 7, groceries, 400)},
+		},
+		{
+			name:    "spending below the factor is not a spike",
+			history: []models.Expense{spend(1, 4, groceries, 100), spend(2, 5, groceries, 100), spend(3, 6, groceries, 100)},
+			current: []models.Expense{spend(4, 7, groceries, 149)},
+		},
+		{
+			name:    "a rise under MinDifference is not a spike however many times the average",
+			history: []models.Expense{spend(1, 4, eatingOut, 20), spend(2, 5, eatingOut, 20), spend(3, 6, eatingOut, 20)},
+			current: []models.Expense{spend(4, 7, eatingOut, 60)},
+		},
+		{
+			name: "the average is over every earlier period with spending, largest rise first",
+			history: []models.Expense{
+				spend(1, 1, groceries, 300), spend(2, 2, groceries, 300), spend(3, 3, groceries, 300),
+				spend(4, 3, eatingOut, 90),
+			},
+			current:   []models.Expense{spend(5, 7, eatingOut, 100), spend(6, 7, groceries, 600)},
+			wantUsual: []float64{300, 30},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spikes := CategorySpikes(tt.current, tt.history)
+			if len(spikes) != len(tt.wantUsual) {
+				t.Fatalf("got %d spikes, want %d: %+v", len(spikes), len(tt.wantUsual), spikes)
+			}
+			for i, s := range spikes {
+				if s.Kind != models.AnomalyCategorySpike {
+					t.Errorf("spike %d kind = %s", i, s.Kind)
+				}
+				if s.Usual != tt.wantUsual[i] {
+					t.Errorf("spike %d usual = %.2f, want %.2f", i, s.Usual, tt.wantUsual[i])
+				}
+			}
+		})
+	}
+}
+
+func TestLargeExpense(t *testing.T) {
+	coffee := func(id int64, month int, amount float64) models.Expense {
+		return atPayee(spend(id, month, eatingOut, amount), 7, "Cafe")
+	}
+	refund := spend(9, 7, eatingOut, 200)
+	refundOf := int64(1)
+	refund.RefundOf = &refundOf
+
+	tests := []struct {
+		name      string
+		history   []models.Expense
+		expense   models.Expense
+		wantOK    bool
+		wantUsual float64
+		wantBasis string
+	}{
+		{
+			name:    "far above the payee's typical expense",
+			history: []models.Expense{coffee(1, 4, 20), coffee(2, 5, 25), coffee(3, 6, 30)},
+			expense: coffee(4, 7, 100),
+			wantOK:  true, wantUsual: 25, wantBasis: "at Cafe",
+		},
+		{
+			name: "too few at the payee falls back to the category",
+			history: []models.Expense{
+				coffee(1, 5, 20), coffee(2, 6, 20),
+				spend(3, 4, eatingOut, 30), spend(4, 5, eatingOut, 30),
+			},
+			expense: coffee(5, 7, 100),
+			wantOK:  true, wantUsual: 25, wantBasis: "in Eating out",
+		},
+		{
+			name:    "fewer than MinHistory anywhere is not enough to judge",
+			history: []models.Expense{coffee(1, 5, 20), coffee(2, 6, 20)},
+			expense: coffee(3, 7, 500),
+		},
+		{
+			name:    "past the factor but under MinDifference is not flagged",
+			history: []models.Expense{coffee(1, 4, 20), coffee(2, 5, 20), coffee(3, 6, 20)},
+			expense: coffee(4, 7, 65),
+		},
+		{
+			name:    "refunds are never flagged",
+			history: []models.Expense{spend(1, 4, eatingOut, 20), spend(2, 5, eatingOut, 20), spend(3, 6, eatingOut, 20)},
+			expense: refund,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, ok := largeExpense(tt.expense, tt.history)
+			if ok != tt.wantOK {
+				t.Fatalf("flagged = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if a.Usual != tt.wantUsual {
+				t.Errorf("usual = %.2f, want %.2f", a.Usual, tt.wantUsual)
+			}
+			if a.Basis != tt.wantBasis {
+				t.Errorf("basis = %q, want %q", a.Basis, tt.wantBasis)
+			}
+			if a.ExpenseID == nil || *a.ExpenseID != tt.expense.ID {
+				t.Errorf("expense id = %v, want %d", a.ExpenseID, tt.expense.ID)
+			}
+		})
+	}
+}
+
+func TestRecurringChange(t *testing.T) {
+	tests := []struct {
+		name      string
+		history   []models.Expense
+		expense   models.Expense
+		wantOK    bool
+		wantUsual float64
+		wantBasis string
+	}{
+		{
+			name:    "compared with the latest earlier charge",
+			history: []models.Expense{charge(1, 6, 3, 10.99), charge(2, 4, 3, 8.99)},
+			expense: charge(3, 7, 3, 12.99),
+			wantOK:  true, wantUsual: 10.99, wantBasis: "in June 2026",
+		},
+		{
+			name:    "the same amount is not flagged",
+			history: []models.Expense{charge(1, 6, 3, 10.99)},
+			expense: charge(2, 7, 3, 10.99),
+		},
+		{
+			name:    "other recurring expenses are not compared",
+			history: []models.Expense{charge(1, 6, 4, 5)},
+			expense: charge(2, 7, 3, 10.99),
+		},
+		{
+			name:    "one-time expenses are not recurring charges",
+			history: []models.Expense{charge(1, 6, 3, 10.99)},
+			expense: spend(2, 7, nil, 15),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, ok := recurringChange(tt.expense, tt.history)
+			if ok != tt.wantOK {
+				t.Fatalf("flagged = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if a.Usual != tt.wantUsual {
+				t.Errorf("usual = %.2f, want %.2f", a.Usual, tt.wantUsual)
+			}
+			if a.Basis != tt.wantBasis {
+				t.Errorf("basis = %q, want %q", a.Basis, tt.wantBasis)
+			}
+		})
+	}
+}
+
+func TestDetectAndAnnotate(t *testing.T) {
+	history := []models.Expense{
+		spend(1, 4, groceries, 100), spend(2, 5, groceries, 100), spend(3, 6, groceries, 100),
+		charge(4, 6, 3, 10),
+	}
+	current := []models.Expense{
+		spend(5, 7, groceries, 400),
+		charge(6, 7, 3, 12),
+		spend(7, 7, eatingOut, 5),
+	}
+
+	anomalies := Detect(current, history)
+	kinds := []models.AnomalyKind{models.AnomalyCategorySpike, models.AnomalyLargeExpense, models.AnomalyRecurringChange}
+	if len(anomalies) != len(kinds) {
+		t.Fatalf("got %d anomalies, want %d: %+v", len(anomalies), len(kinds), anomalies)
+	}
+	for i, kind := range kinds {
+		if anomalies[i].Kind != kind {
+			t.Errorf("anomaly %d kind = %s, want %s", i, anomalies[i].Kind, kind)
+		}
+	}
+
+	Annotate(current, anomalies)
+	wantCounts := []int{1, 1, 0}
+	for i, e := range current {
+		if len(e.Anomalies) != wantCounts[i] {
+			t.Errorf("expense %d has %d anomalies, want %d", e.ID, len(e.Anomalies), wantCounts[i])
+		}
+	}
+}
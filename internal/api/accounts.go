@@ -0,0 +1,122 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"spending-tracker/internal/service"
+)
+
+func (h *Handler) ListAccounts(c *gin.Context) {
+	accounts, err := h.svc.ListAccounts(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, emptyIfNil(accounts))
+}
+
+func (h *Handler) CreateAccount(c *gin.Context) {
+	var input service.AccountInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	account, err := h.svc.CreateAccount(c.Request.Context(), input)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, account)
+}
+
+func (h *Handler) UpdateAccount(c *gin.Context) {
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var input service.AccountInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	account, err := h.svc.UpdateAccount(c.Request.Context(), id, input)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, account)
+}
+
+func (h *Handler) DeleteAccount(c *gin.Context) {
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.svc.DeleteAccount(c.Request.Context(), id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetAccountBalances returns each account's running balance over a period
+func (h *Handler) GetAccountBalances(c *gin.Context) {
+	period, ok := periodParam(c)
+	if !ok {
+		return
+	}
+
+	balances, err := h.svc.AccountBalances(c.Request.Context(), period)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, emptyIfNil(balances))
+}
+
+func (h *Handler) ListTransfers(c *gin.Context) {
+	transfers, err := h.svc.ListTransfers(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, emptyIfNil(transfers))
+}
+
+func (h *Handler) CreateTransfer(c *gin.Context) {
+	var input service.TransferInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	transfer, err := h.svc.CreateTransfer(c.Request.Context(), input)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, transfer)
+}
+
+func (h *Handler) DeleteTransfer(c *gin.Context) {
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.svc.DeleteTransfer(c.Request.Context(), id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
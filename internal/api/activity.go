@@ -0,0 +1,37 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListActivity returns a page of the household's changes, newest first
+func (h *Handler) ListActivity(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+
+	entries, err := h.svc.Activity(c.Request.Context(), page)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, emptyIfNil(entries))
+}
+
+// UndoChange reverses a delete and returns the entry recording the restore
+func (h *Handler) UndoChange(c *gin.Context) {
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+
+	entry, err := h.svc.Undo(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
@@ -0,0 +1,23 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetAnomalies returns what is unusual about a period's spending
+func (h *Handler) GetAnomalies(c *gin.Context) {
+	period, ok := periodParam(c)
+	if !ok {
+		return
+	}
+
+	anomalies, err := h.svc.Anomalies(c.Request.Context(), period)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, emptyIfNil(anomalies))
+}
@@ -0,0 +1,168 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"spending-tracker/internal/service"
+)
+
+type mergeCategoryRequest struct {
+	IntoID int64 `json:"into_id"`
+}
+
+// ListCategories returns all categories, including archived ones
+func (h *Handler) ListCategories(c *gin.Context) {
+	categories, err := h.svc.ListCategories(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, emptyIfNil(categories))
+}
+
+// CreateCategory creates a category
+func (h *Handler) CreateCategory(c *gin.Context) {
+	var input service.CategoryInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	cat, err := h.svc.CreateCategory(c.Request.Context(), input)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, cat)
+}
+
+// GetCategory returns a single category
+func (h *Handler) GetCategory(c *gin.Context) {
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+
+	cat, err := h.svc.GetCategory(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, cat)
+}
+
+// GetCategoryUsage returns how many expenses and templates use a category
+func (h *Handler) GetCategoryUsage(c *gin.Context) {
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+
+	usage, err := h.svc.CategoryUsage(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, usage)
+}
+
+// UpdateCategory replaces a category's name, color, parent and budget
+func (h *Handler) UpdateCategory(c *gin.Context) {
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var input service.CategoryInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	cat, err := h.svc.UpdateCategory(c.Request.Context(), id, input)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, cat)
+}
+
+// DeleteCategory deletes a category; replacement_id is required while it is in use
+func (h *Handler) DeleteCategory(c *gin.Context) {
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var replacementID *int64
+	if raw := c.Query("replacement_id"); raw != "" {
+		parsed, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			writeError(c, http.StatusBadRequest, "invalid_id", "invalid replacement_id")
+			return
+		}
+		replacementID = &parsed
+	}
+
+	if _, err := h.svc.DeleteCategory(c.Request.Context(), id, replacementID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// MergeCategory moves everything in a category into another and deletes it
+func (h *Handler) MergeCategory(c *gin.Context) {
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req mergeCategoryRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	if err := h.svc.MergeCategories(c.Request.Context(), id, req.IntoID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ArchiveCategory hides a category from pickers
+func (h *Handler) ArchiveCategory(c *gin.Context) {
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.svc.ArchiveCategory(c.Request.Context(), id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	h.GetCategory(c)
+}
+
+// UnarchiveCategory restores an archived category
+func (h *Handler) UnarchiveCategory(c *gin.Context) {
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.svc.UnarchiveCategory(c.Request.Context(), id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	h.GetCategory(c)
+}
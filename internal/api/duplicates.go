@@ -0,0 +1,67 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"spending-tracker/models"
+)
+
+// CheckDuplicates returns the existing expenses that the expense in the body,
+// as it would be created, looks like a duplicate of
+func (h *Handler) CheckDuplicates(c *gin.Context) {
+	var req createExpenseRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	period := models.Period{Year: req.Year, Month: req.Month}
+	duplicates, err := h.svc.DuplicatesOf(c.Request.Context(), period, req.ExpenseInput)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, emptyIfNil(duplicates))
+}
+
+// GetDuplicates reports the expenses in a period that look like duplicates
+func (h *Handler) GetDuplicates(c *gin.Context) {
+	period, ok := periodParam(c)
+	if !ok {
+		return
+	}
+
+	groups, err := h.svc.FindDuplicates(c.Request.Context(), period)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, emptyIfNil(groups))
+}
+
+type mergeRequest struct {
+	DuplicateIDs []int64 `json:"duplicate_ids"`
+}
+
+// MergeDuplicates keeps an expense and deletes the given duplicates of it
+func (h *Handler) MergeDuplicates(c *gin.Context) {
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req mergeRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	expense, err := h.svc.MergeDuplicates(c.Request.Context(), id, req.DuplicateIDs)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, expense)
+}
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"spending-tracker/internal/service"
+	"spending-tracker/models"
+)
+
+type createExpenseRequest struct {
+	Year  int `json:"year"`
+	Month int `json:"month"`
+	service.ExpenseInput
+}
+
+// CreateExpense adds an expense to the period given in the body
+func (h *Handler) CreateExpense(c *gin.Context) {
+	var req createExpenseRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	period := models.Period{Year: req.Year, Month: req.Month}
+	expense, err := h.svc.CreateExpense(c.Request.Context(), period, req.ExpenseInput)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, expense)
+}
+
+// GetExpense returns a single expense
+func (h *Handler) GetExpense(c *gin.Context) {
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+
+	expense, err := h.svc.GetExpense(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, expense)
+}
+
+// UpdateExpense replaces an expense's editable fields
+func (h *Handler) UpdateExpense(c *gin.Context) {
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var input service.ExpenseInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	expense, err := h.svc.UpdateExpense(c.Request.Context(), id, input)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, expense)
+}
+
+// DeleteExpense deletes an expense
+func (h *Handler) DeleteExpense(c *gin.Context) {
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if _, err := h.svc.DeleteExpense(c.Request.Context(), id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,18 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetForecast projects the current period's spending to the end of the month
+func (h *Handler) GetForecast(c *gin.Context) {
+	forecast, err := h.svc.Forecast(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, forecast)
+}
@@ -0,0 +1,127 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"spending-tracker/internal/service"
+)
+
+func (h *Handler) ListGoals(c *gin.Context) {
+	goals, err := h.svc.ListGoals(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, emptyIfNil(goals))
+}
+
+func (h *Handler) GetGoal(c *gin.Context) {
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+
+	goal, err := h.svc.GetGoal(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, goal)
+}
+
+func (h *Handler) CreateGoal(c *gin.Context) {
+	var input service.GoalInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	goal, err := h.svc.CreateGoal(c.Request.Context(), input)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, goal)
+}
+
+func (h *Handler) UpdateGoal(c *gin.Context) {
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var input service.GoalInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	goal, err := h.svc.UpdateGoal(c.Request.Context(), id, input)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, goal)
+}
+
+func (h *Handler) DeleteGoal(c *gin.Context) {
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.svc.DeleteGoal(c.Request.Context(), id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetContributions returns what was put toward goals in a period
+func (h *Handler) GetContributions(c *gin.Context) {
+	period, ok := periodParam(c)
+	if !ok {
+		return
+	}
+
+	contributions, err := h.svc.ListContributions(c.Request.Context(), period)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, emptyIfNil(contributions))
+}
+
+func (h *Handler) CreateContribution(c *gin.Context) {
+	var input service.ContributionInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	contribution, err := h.svc.CreateContribution(c.Request.Context(), input)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, contribution)
+}
+
+func (h *Handler) DeleteContribution(c *gin.Context) {
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.svc.DeleteContribution(c.Request.Context(), id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,114 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"spending-tracker/internal/service"
+	"spending-tracker/models"
+)
+
+// Handler serves the versioned JSON API
+type Handler struct {
+	svc *service.Service
+}
+
+// NewHandler creates a new API Handler instance
+func NewHandler(svc *service.Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// errorBody is the shape of every API error response
+type errorBody struct {
+	Error errorDetail `json:"error"`
+}
+
+type errorDetail struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	// Current is the record as it is now, sent with conflict errors
+	Current any `json:"current,omitempty"`
+}
+
+func writeError(c *gin.Context, status int, code, message string) {
+	c.AbortWithStatusJSON(status, errorBody{Error: errorDetail{Code: code, Message: message}})
+}
+
+// respondError maps a service error to a status code and error body
+func respondError(c *gin.Context, err error) {
+	var validationErr *service.ValidationError
+	var conflictErr *service.ConflictError
+	switch {
+	case errors.As(err, &validationErr):
+		writeError(c, http.StatusUnprocessableEntity, "validation_error", validationErr.Message)
+	case errors.As(err, &conflictErr):
+		c.AbortWithStatusJSON(http.StatusConflict, errorBody{Error: errorDetail{
+			Code:    "conflict",
+			Message: conflictErr.Error(),
+			Current: conflictErr.Current,
+		}})
+	case errors.Is(err, service.ErrNotFound):
+		writeError(c, http.StatusNotFound, "not_found", "resource not found")
+	case errors.Is(err, service.ErrForbidden):
+		Forbidden(c)
+	default:
+		c.Error(err)
+		writeError(c, http.StatusInternalServerError, "internal_error", "internal server error")
+	}
+}
+
+// bindJSON decodes the request body, writing a 400 error on failure
+func bindJSON(c *gin.Context, v any) bool {
+	if err := c.ShouldBindJSON(v); err != nil {
+		writeError(c, http.StatusBadRequest, "invalid_body", err.Error())
+		return false
+	}
+	return true
+}
+
+// idParam reads a positive integer path parameter, writing a 400 error on failure
+func idParam(c *gin.Context, name string) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param(name), 10, 64)
+	if err != nil || id <= 0 {
+		writeError(c, http.StatusBadRequest, "invalid_id", "invalid "+name)
+		return 0, false
+	}
+	return id, true
+}
+
+// periodParam reads the :year and :month path parameters
+func periodParam(c *gin.Context) (models.Period, bool) {
+	year, yearErr := strconv.Atoi(c.Param("year"))
+	month, monthErr := strconv.Atoi(c.Param("month"))
+	period := models.Period{Year: year, Month: month}
+	if yearErr != nil || monthErr != nil || !period.IsValid() {
+		writeError(c, http.StatusBadRequest, "invalid_period", "year and month must name a valid period")
+		return period, false
+	}
+	return period, true
+}
+
+// emptyIfNil keeps empty lists encoding as [] rather than null
+func emptyIfNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
+
+// Unauthorized rejects API requests without a valid session
+func Unauthorized(c *gin.Context) {
+	writeError(c, http.StatusUnauthorized, "unauthorized", "authentication required")
+}
+
+// Forbidden rejects requests outside the caller's households or role
+func Forbidden(c *gin.Context) {
+	writeError(c, http.StatusForbidden, "forbidden", "you do not have permission to do that")
+}
+
+// CSRFRejected rejects cookie-authenticated mutations without a valid CSRF token
+func CSRFRejected(c *gin.Context) {
+	writeError(c, http.StatusForbidden, "csrf_mismatch", "missing or invalid CSRF token")
+}
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"spending-tracker/internal/auth"
+	"spending-tracker/models"
+)
+
+type householdResponse struct {
+	Household models.Household `json:"household"`
+	Role      models.Role      `json:"role"`
+	Members   []models.Member  `json:"members"`
+}
+
+// ListHouseholds returns the caller's households. Requests act on the one
+// named by the X-Household-ID header, or the default when it is omitted.
+func (h *Handler) ListHouseholds(c *gin.Context) {
+	households, err := h.svc.ListHouseholds(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, emptyIfNil(households))
+}
+
+// GetCurrentHousehold returns the household this request acts on, with its members
+func (h *Handler) GetCurrentHousehold(c *gin.Context) {
+	members, err := h.svc.HouseholdMembers(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	membership := auth.MembershipFromContext(c.Request.Context())
+	c.JSON(http.StatusOK, householdResponse{
+		Household: membership.Household,
+		Role:      membership.Role,
+		Members:   emptyIfNil(members),
+	})
+}
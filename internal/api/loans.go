@@ -0,0 +1,155 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"spending-tracker/internal/service"
+	"spending-tracker/models"
+)
+
+type scheduleResponse struct {
+	Loan *models.Loan             `json:"loan"`
+	Rows []models.AmortizationRow `json:"rows"`
+	// PaidOff is false when the monthly payment never pays the loan off
+	PaidOff bool `json:"paid_off"`
+}
+
+type expenseLoanRequest struct {
+	LoanID *int64 `json:"loan_id"`
+}
+
+func (h *Handler) ListLoans(c *gin.Context) {
+	loans, err := h.svc.ListLoans(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, emptyIfNil(loans))
+}
+
+func (h *Handler) GetLoan(c *gin.Context) {
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+
+	loan, err := h.svc.GetLoan(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, loan)
+}
+
+func (h *Handler) CreateLoan(c *gin.Context) {
+	var input service.LoanInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	loan, err := h.svc.CreateLoan(c.Request.Context(), input)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, loan)
+}
+
+func (h *Handler) UpdateLoan(c *gin.Context) {
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var input service.LoanInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	loan, err := h.svc.UpdateLoan(c.Request.Context(), id, input)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, loan)
+}
+
+func (h *Handler) DeleteLoan(c *gin.Context) {
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.svc.DeleteLoan(c.Request.Context(), id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetLoanSchedule returns the month-by-month paying off of a loan
+func (h *Handler) GetLoanSchedule(c *gin.Context) {
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+
+	loan, rows, paidOff, err := h.svc.LoanSchedule(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, scheduleResponse{Loan: loan, Rows: emptyIfNil(rows), PaidOff: paidOff})
+}
+
+// GetPayoffPlans compares the snowball and avalanche strategies with an
+// optional extra amount paid each month
+func (h *Handler) GetPayoffPlans(c *gin.Context) {
+	var extra float64
+	if raw := c.Query("extra"); raw != "" {
+		parsed, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			writeError(c, http.StatusBadRequest, "invalid_amount", "invalid extra")
+			return
+		}
+		extra = parsed
+	}
+
+	plans, err := h.svc.PayoffPlans(c.Request.Context(), extra)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, plans)
+}
+
+// PutExpenseLoan records an expense as an extra payment toward a loan, or
+// with a null loan_id makes it an ordinary expense again
+func (h *Handler) PutExpenseLoan(c *gin.Context) {
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req expenseLoanRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	expense, err := h.svc.SetExpenseLoan(c.Request.Context(), id, req.LoanID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, expense)
+}
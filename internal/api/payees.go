@@ -0,0 +1,95 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"spending-tracker/internal/service"
+)
+
+func (h *Handler) ListPayees(c *gin.Context) {
+	payees, err := h.svc.ListPayees(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, emptyIfNil(payees))
+}
+
+// GetPayeeTotals returns what has been spent with each payee, biggest first
+func (h *Handler) GetPayeeTotals(c *gin.Context) {
+	totals, err := h.svc.PayeeTotals(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, emptyIfNil(totals))
+}
+
+// GetPayeeHistory returns what has been spent with a payee in each period
+func (h *Handler) GetPayeeHistory(c *gin.Context) {
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+
+	history, err := h.svc.PayeeHistory(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	history.Periods = emptyIfNil(history.Periods)
+
+	c.JSON(http.StatusOK, history)
+}
+
+func (h *Handler) CreatePayee(c *gin.Context) {
+	var input service.PayeeInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	payee, err := h.svc.CreatePayee(c.Request.Context(), input)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, payee)
+}
+
+func (h *Handler) UpdatePayee(c *gin.Context) {
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var input service.PayeeInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	payee, err := h.svc.UpdatePayee(c.Request.Context(), id, input)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, payee)
+}
+
+func (h *Handler) DeletePayee(c *gin.Context) {
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.svc.DeletePayee(c.Request.Context(), id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
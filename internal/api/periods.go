@@ -0,0 +1,128 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"spending-tracker/models"
+)
+
+type periodResponse struct {
+	Period   models.Period    `json:"period"`
+	Income   float64          `json:"income"`
+	Expenses []models.Expense `json:"expenses"`
+	Summary  models.Summary   `json:"summary"`
+}
+
+type incomeRequest struct {
+	Amount    float64 `json:"amount"`
+	AccountID *int64  `json:"account_id"`
+}
+
+type incomeResponse struct {
+	Period    models.Period `json:"period"`
+	Amount    float64       `json:"amount"`
+	AccountID *int64        `json:"account_id"`
+}
+
+// GetPeriod returns a period's income, expenses and summary
+func (h *Handler) GetPeriod(c *gin.Context) {
+	period, ok := periodParam(c)
+	if !ok {
+		return
+	}
+
+	filter := models.ExpenseFilter(c.DefaultQuery("filter", string(models.FilterAll)))
+	state, err := h.svc.LoadPeriod(c.Request.Context(), period, filter)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, periodResponse{
+		Period:   state.Period,
+		Income:   state.Income,
+		Expenses: emptyIfNil(state.Expenses),
+		Summary:  state.Summary,
+	})
+}
+
+// GetPeriodExpenses returns a period's expenses, optionally filtered
+func (h *Handler) GetPeriodExpenses(c *gin.Context) {
+	period, ok := periodParam(c)
+	if !ok {
+		return
+	}
+
+	filter := models.ExpenseFilter(c.DefaultQuery("filter", string(models.FilterAll)))
+	state, err := h.svc.LoadPeriod(c.Request.Context(), period, filter)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, emptyIfNil(state.Expenses))
+}
+
+// GetSummary returns a period's summary totals and category breakdown
+func (h *Handler) GetSummary(c *gin.Context) {
+	period, ok := periodParam(c)
+	if !ok {
+		return
+	}
+
+	state, err := h.svc.LoadPeriod(c.Request.Context(), period, models.FilterAll)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	summary := state.Summary
+	summary.CategoryBreakdown = emptyIfNil(summary.CategoryBreakdown)
+	c.JSON(http.StatusOK, summary)
+}
+
+// GetIncome returns a period's income
+func (h *Handler) GetIncome(c *gin.Context) {
+	period, ok := periodParam(c)
+	if !ok {
+		return
+	}
+
+	amount, err := h.svc.GetIncome(c.Request.Context(), period)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	accountID, err := h.svc.GetIncomeAccount(c.Request.Context(), period)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, incomeResponse{Period: period, Amount: amount, AccountID: accountID})
+}
+
+// PutIncome sets a period's income and the account it was paid into
+func (h *Handler) PutIncome(c *gin.Context) {
+	period, ok := periodParam(c)
+	if !ok {
+		return
+	}
+
+	var req incomeRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	if err := h.svc.SetIncome(c.Request.Context(), period, req.Amount); err != nil {
+		respondError(c, err)
+		return
+	}
+	if err := h.svc.SetIncomeAccount(c.Request.Context(), period, req.AccountID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, incomeResponse{Period: period, Amount: req.Amount, AccountID: req.AccountID})
+}
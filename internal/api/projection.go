@@ -0,0 +1,33 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"spending-tracker/models"
+)
+
+// GetProjection projects cash flow over the next months
+func (h *Handler) GetProjection(c *gin.Context) {
+	h.respondProjection(c, models.ProjectionAdjustments{})
+}
+
+// PostProjection projects cash flow with what-if adjustments, which are
+// not saved
+func (h *Handler) PostProjection(c *gin.Context) {
+	var adjustments models.ProjectionAdjustments
+	if !bindJSON(c, &adjustments) {
+		return
+	}
+	h.respondProjection(c, adjustments)
+}
+
+func (h *Handler) respondProjection(c *gin.Context, adjustments models.ProjectionAdjustments) {
+	projection, err := h.svc.Projection(c.Request.Context(), adjustments)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, projection)
+}
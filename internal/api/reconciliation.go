@@ -0,0 +1,111 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"spending-tracker/internal/service"
+)
+
+// GetReconciliation returns an account's reconciliation in progress, the
+// expenses still to reconcile and the statements already reconciled
+func (h *Handler) GetReconciliation(c *gin.Context) {
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+
+	reconciliation, err := h.svc.Reconciliation(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	reconciliation.Expenses = emptyIfNil(reconciliation.Expenses)
+	reconciliation.History = emptyIfNil(reconciliation.History)
+	c.JSON(http.StatusOK, reconciliation)
+}
+
+// PutReconciliation starts reconciling an account against a statement, or
+// corrects the statement of the reconciliation in progress
+func (h *Handler) PutReconciliation(c *gin.Context) {
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var input service.ReconciliationInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	reconciliation, err := h.svc.StartReconciliation(c.Request.Context(), id, input)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reconciliation)
+}
+
+type clearedRequest struct {
+	Cleared bool `json:"cleared"`
+}
+
+// PutExpenseCleared ticks an expense off against the statement being reconciled
+func (h *Handler) PutExpenseCleared(c *gin.Context) {
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+	expenseID, ok := idParam(c, "expense_id")
+	if !ok {
+		return
+	}
+
+	var req clearedRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	reconciliation, err := h.svc.SetExpenseCleared(c.Request.Context(), id, expenseID, req.Cleared)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reconciliation)
+}
+
+// CompleteReconciliation locks the ticked expenses once the cleared balance
+// matches the statement
+func (h *Handler) CompleteReconciliation(c *gin.Context) {
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+
+	reconciliation, err := h.svc.CompleteReconciliation(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reconciliation)
+}
+
+// UnlockExpense releases a reconciled expense so it can be changed
+func (h *Handler) UnlockExpense(c *gin.Context) {
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+
+	expense, err := h.svc.UnlockExpense(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, expense)
+}
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"spending-tracker/internal/service"
+)
+
+// ListRecurring returns all recurring templates, active ones first
+func (h *Handler) ListRecurring(c *gin.Context) {
+	templates, err := h.svc.ListRecurring(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, emptyIfNil(templates))
+}
+
+// CreateRecurring creates a recurring template
+func (h *Handler) CreateRecurring(c *gin.Context) {
+	var input service.RecurringInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	template, err := h.svc.CreateRecurring(c.Request.Context(), input)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, template)
+}
+
+// GetRecurring returns a single recurring template
+func (h *Handler) GetRecurring(c *gin.Context) {
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+
+	template, err := h.svc.GetRecurring(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// UpdateRecurring replaces a recurring template's fields
+func (h *Handler) UpdateRecurring(c *gin.Context) {
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var input service.RecurringInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	template, err := h.svc.UpdateRecurring(c.Request.Context(), id, input)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// DeleteRecurring deactivates a recurring template
+func (h *Handler) DeleteRecurring(c *gin.Context) {
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if _, err := h.svc.DeactivateRecurring(c.Request.Context(), id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"spending-tracker/internal/service"
+	"spending-tracker/models"
+)
+
+type createRefundRequest struct {
+	Year  int `json:"year"`
+	Month int `json:"month"`
+	service.RefundInput
+}
+
+// CreateRefund records money coming back for an expense in the period given in the body
+func (h *Handler) CreateRefund(c *gin.Context) {
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req createRefundRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	period := models.Period{Year: req.Year, Month: req.Month}
+	refund, err := h.svc.CreateRefund(c.Request.Context(), id, period, req.RefundInput)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, refund)
+}
+
+// ListReimbursements returns the money still owed back, grouped by who owes it
+func (h *Handler) ListReimbursements(c *gin.Context) {
+	groups, err := h.svc.PendingReimbursements(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, emptyIfNil(groups))
+}
@@ -0,0 +1,132 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"spending-tracker/internal/service"
+	"spending-tracker/models"
+)
+
+type createPersonRequest struct {
+	Name   string `json:"name"`
+	UserID *int64 `json:"user_id"`
+}
+
+type balancesResponse struct {
+	Balances []models.Balance `json:"balances"`
+	SettleUp []models.Debt    `json:"settle_up"`
+}
+
+func (h *Handler) ListPeople(c *gin.Context) {
+	people, err := h.svc.ListPeople(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, emptyIfNil(people))
+}
+
+func (h *Handler) CreatePerson(c *gin.Context) {
+	var req createPersonRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	person, err := h.svc.CreatePerson(c.Request.Context(), req.Name, req.UserID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, person)
+}
+
+func (h *Handler) DeletePerson(c *gin.Context) {
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.svc.DeletePerson(c.Request.Context(), id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetBalances returns each person's net balance and the payments that settle them
+func (h *Handler) GetBalances(c *gin.Context) {
+	balances, debts, err := h.svc.Balances(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, balancesResponse{
+		Balances: emptyIfNil(balances),
+		SettleUp: emptyIfNil(debts),
+	})
+}
+
+func (h *Handler) ListSettlements(c *gin.Context) {
+	settlements, err := h.svc.ListSettlements(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, emptyIfNil(settlements))
+}
+
+func (h *Handler) CreateSettlement(c *gin.Context) {
+	var input service.SettlementInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	settlement, err := h.svc.RecordSettlement(c.Request.Context(), input)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, settlement)
+}
+
+func (h *Handler) DeleteSettlement(c *gin.Context) {
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.svc.DeleteSettlement(c.Request.Context(), id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// PutExpenseSharing sets who paid an expense and how it is shared
+func (h *Handler) PutExpenseSharing(c *gin.Context) {
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var input service.SharingInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	expense, err := h.svc.SetExpenseSharing(c.Request.Context(), id, input)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, expense)
+}
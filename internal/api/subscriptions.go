@@ -0,0 +1,32 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetSubscriptions reports on the recurring expenses as of a period, with
+// regular charges that have no recurring template
+func (h *Handler) GetSubscriptions(c *gin.Context) {
+	period, ok := periodParam(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.svc.Subscriptions(c.Request.Context(), period)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	report.Subscriptions = emptyIfNil(report.Subscriptions)
+	report.Untracked = emptyIfNil(report.Untracked)
+	for i := range report.Subscriptions {
+		report.Subscriptions[i].PriceChanges = emptyIfNil(report.Subscriptions[i].PriceChanges)
+	}
+	for i := range report.Untracked {
+		report.Untracked[i].PriceChanges = emptyIfNil(report.Untracked[i].PriceChanges)
+	}
+
+	c.JSON(http.StatusOK, report)
+}
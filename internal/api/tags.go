@@ -0,0 +1,67 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"spending-tracker/models"
+)
+
+type tagTotalsResponse struct {
+	From   models.Period     `json:"from"`
+	To     models.Period     `json:"to"`
+	Totals []models.TagTotal `json:"totals"`
+}
+
+type searchResponse struct {
+	models.SearchResults
+	Page       int `json:"page"`
+	TotalPages int `json:"total_pages"`
+}
+
+// ListTags returns all tags
+func (h *Handler) ListTags(c *gin.Context) {
+	tags, err := h.svc.ListTags(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, emptyIfNil(tags))
+}
+
+// GetTagTotals returns spending per tag between from and to (YYYY-MM, inclusive)
+func (h *Handler) GetTagTotals(c *gin.Context) {
+	from, fromOK := models.ParsePeriod(c.Query("from"))
+	to, toOK := models.ParsePeriod(c.Query("to"))
+	if !fromOK || !toOK {
+		writeError(c, http.StatusBadRequest, "invalid_period", "from and to must be given as YYYY-MM")
+		return
+	}
+
+	totals, err := h.svc.TagTotals(c.Request.Context(), from, to)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tagTotalsResponse{From: from, To: to, Totals: emptyIfNil(totals)})
+}
+
+// SearchExpenses searches expenses across all periods
+func (h *Handler) SearchExpenses(c *gin.Context) {
+	search := models.ParseExpenseSearch(c.Request.URL.Query())
+
+	results, err := h.svc.SearchExpenses(c.Request.Context(), search)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	results.Expenses = emptyIfNil(results.Expenses)
+	c.JSON(http.StatusOK, searchResponse{
+		SearchResults: results,
+		Page:          results.Search.Page,
+		TotalPages:    results.TotalPages(),
+	})
+}
@@ -0,0 +1,159 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"spending-tracker/models"
+)
+
+// SessionStore resolves a session token to its user
+type SessionStore interface {
+	SessionUser(ctx context.Context, token string) (*models.User, error)
+}
+
+// TokenStore resolves an API token to its user and scope
+type TokenStore interface {
+	TokenUser(ctx context.Context, token string) (*models.User, models.TokenScope, error)
+}
+
+// HouseholdResolver looks up which household a request acts on
+type HouseholdResolver interface {
+	// Membership returns the user's membership of a household, failing if they are not a member
+	Membership(ctx context.Context, userID, householdID int64) (*models.Membership, error)
+	// DefaultMembership returns the household to use when none was chosen
+	DefaultMembership(ctx context.Context, userID int64) (*models.Membership, error)
+}
+
+// RequireUser rejects requests without a valid session cookie by calling
+// unauthorized, and otherwise stores the user in the request context
+func RequireUser(store SessionStore, unauthorized gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Already authenticated, e.g. by BearerToken
+		if UserFromContext(c.Request.Context()) != nil {
+			c.Next()
+			return
+		}
+
+		token, err := c.Cookie(SessionCookie)
+		if err != nil || token == "" {
+			unauthorized(c)
+			c.Abort()
+			return
+		}
+
+		user, err := store.SessionUser(c.Request.Context(), token)
+		if err != nil || user == nil {
+			unauthorized(c)
+			c.Abort()
+			return
+		}
+
+		c.Request = c.Request.WithContext(WithUser(c.Request.Context(), user))
+		c.Next()
+	}
+}
+
+// BearerToken authenticates requests carrying an "Authorization: Bearer"
+// API token. Requests without the header pass through untouched so a later
+// RequireUser can fall back to the session cookie. Read-scoped tokens may
+// only make GET and HEAD requests, or call one of the readOnly routes, given
+// as full paths, that take a POST body but change nothing; anything else
+// calls forbidden.
+func BearerToken(store TokenStore, unauthorized, forbidden gin.HandlerFunc, readOnly ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		if header == "" {
+			c.Next()
+			return
+		}
+
+		token, ok := strings.CutPrefix(header, "Bearer ")
+		if !ok || token == "" {
+			unauthorized(c)
+			c.Abort()
+			return
+		}
+
+		user, scope, err := store.TokenUser(c.Request.Context(), strings.TrimSpace(token))
+		if err != nil || user == nil {
+			unauthorized(c)
+			c.Abort()
+			return
+		}
+
+		safe := c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead ||
+			(c.Request.Method == http.MethodPost && slices.Contains(readOnly, c.FullPath()))
+		if scope != models.TokenScopeWrite && !safe {
+			forbidden(c)
+			c.Abort()
+			return
+		}
+
+		c.Request = c.Request.WithContext(WithTokenAuth(WithUser(c.Request.Context(), user)))
+		c.Next()
+	}
+}
+
+// RequireAdmin rejects signed-in users who are not admins by calling forbidden
+func RequireAdmin(forbidden gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user := UserFromContext(c.Request.Context())
+		if user == nil || !user.IsAdmin {
+			forbidden(c)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireHousehold attaches the active household to the request context. An
+// explicit X-Household-ID header must name one of the user's households or
+// forbidden is called; a stale household cookie falls back to the default.
+// Must run after RequireUser.
+func RequireHousehold(resolver HouseholdResolver, forbidden gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		user := UserFromContext(ctx)
+		if user == nil {
+			forbidden(c)
+			c.Abort()
+			return
+		}
+
+		var membership *models.Membership
+		var err error
+		if header := c.GetHeader(HouseholdHeader); header != "" {
+			id, parseErr := strconv.ParseInt(header, 10, 64)
+			if parseErr == nil {
+				membership, err = resolver.Membership(ctx, user.ID, id)
+			}
+			if parseErr != nil || err != nil {
+				forbidden(c)
+				c.Abort()
+				return
+			}
+		} else {
+			if cookie, cookieErr := c.Cookie(HouseholdCookie); cookieErr == nil {
+				if id, parseErr := strconv.ParseInt(cookie, 10, 64); parseErr == nil {
+					membership, _ = resolver.Membership(ctx, user.ID, id)
+				}
+			}
+			if membership == nil {
+				membership, err = resolver.DefaultMembership(ctx, user.ID)
+			}
+			if err != nil {
+				c.AbortWithError(http.StatusInternalServerError, err)
+				return
+			}
+		}
+
+		c.Request = c.Request.WithContext(WithMembership(ctx, membership))
+		c.Next()
+	}
+}
@@ -0,0 +1,108 @@
+// Package events fans out change notifications to open pages so they can
+// refresh fragments another tab or household member has made stale.
+package events
+
+import (
+	"sync"
+
+	"spending-tracker/models"
+)
+
+// Kind names a change; it is sent as the SSE event name
+type Kind string
+
+const (
+	ExpenseCreated  Kind = "expense-created"
+	ExpenseUpdated  Kind = "expense-updated"
+	ExpenseDeleted  Kind = "expense-deleted"
+	IncomeChanged   Kind = "income-changed"
+	CategoryChanged Kind = "category-changed"
+)
+
+// Event is a change to one household's budget
+type Event struct {
+	HouseholdID int64
+	// Period is the period the change belongs to; the zero value affects every period
+	Period models.Period
+	Kind   Kind
+	// Origin is the tab that made the change, which has already updated itself
+	Origin string
+}
+
+// subscriberBuffer is how many events a slow subscriber can fall behind by
+// before further events are dropped. Events are only refresh hints, so a
+// dropped event costs a stale fragment rather than lost data.
+const subscriberBuffer = 16
+
+type subscription struct {
+	householdID int64
+	period      models.Period
+	tab         string
+	ch          chan Event
+}
+
+func (s *subscription) wants(e Event) bool {
+	if e.HouseholdID != s.householdID {
+		return false
+	}
+	if e.Origin != "" && e.Origin == s.tab {
+		return false
+	}
+	return e.Period == (models.Period{}) || e.Period == s.period
+}
+
+// Hub is an in-process pub/sub hub for change events
+type Hub struct {
+	mu   sync.Mutex
+	subs map[*subscription]struct{}
+}
+
+// NewHub creates a new Hub instance
+func NewHub() *Hub {
+	return &Hub{subs: make(map[*subscription]struct{})}
+}
+
+// Subscribe returns the events for a household's period, skipping those made
+// by the given tab, and a function that ends the subscription
+func (h *Hub) Subscribe(householdID int64, period models.Period, tab string) (<-chan Event, func()) {
+	sub := &subscription{
+		householdID: householdID,
+		period:      period,
+		tab:         tab,
+		ch:          make(chan Event, subscriberBuffer),
+	}
+
+	h.mu.Lock()
+	h.subs[sub] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	return sub.ch, func() {
+		once.Do(func() {
+			h.mu.Lock()
+			delete(h.subs, sub)
+			h.mu.Unlock()
+			close(sub.ch)
+		})
+	}
+}
+
+// Publish delivers an event to every interested subscriber without blocking.
+// Publishing on a nil Hub does nothing.
+func (h *Hub) Publish(e Event) {
+	if h == nil {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for sub := range h.subs {
+		if !sub.wants(e) {
+			continue
+		}
+		select {
+		case sub.ch <- e:
+		default:
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package events
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OriginHeader identifies the browser tab a request came from. Pages send it
+// with every htmx request so their own changes are not echoed back to them.
+const OriginHeader = "X-Tab-ID"
+
+type originKey struct{}
+
+// WithOrigin returns a context recording the tab a change came from
+func WithOrigin(ctx context.Context, tab string) context.Context {
+	return context.WithValue(ctx, originKey{}, tab)
+}
+
+// OriginFromContext returns the tab a change came from, or "" if unknown
+func OriginFromContext(ctx context.Context) string {
+	tab, _ := ctx.Value(originKey{}).(string)
+	return tab
+}
+
+// TrackOrigin stores the request's tab ID in its context
+func TrackOrigin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if tab := c.GetHeader(OriginHeader); tab != "" {
+			c.Request = c.Request.WithContext(WithOrigin(c.Request.Context(), tab))
+		}
+		c.Next()
+	}
+}
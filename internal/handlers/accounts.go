@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"spending-tracker/internal/auth"
+	"spending-tracker/internal/service"
+	"spending-tracker/models"
+	"spending-tracker/templates"
+	"spending-tracker/templates/components"
+)
+
+// AccountsPage shows account balances for a period and the transfers between accounts
+func (h *Handler) AccountsPage(c *gin.Context) {
+	h.renderAccountsPage(c, http.StatusOK, "")
+}
+
+func (h *Handler) CreateAccount(c *gin.Context) {
+	_, err := h.svc.CreateAccount(c.Request.Context(), accountInputFromForm(c))
+	if formError(c, err, "Error adding account", h.renderAccountsPage) {
+		return
+	}
+	c.Redirect(http.StatusSeeOther, accountsURL(requestPeriod(c)))
+}
+
+func (h *Handler) UpdateAccount(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.String(http.StatusBadRequest, "Invalid account ID")
+		return
+	}
+
+	_, err = h.svc.UpdateAccount(c.Request.Context(), id, accountInputFromForm(c))
+	if formError(c, err, "Error updating account", h.renderAccountsPage) {
+		return
+	}
+	c.Redirect(http.StatusSeeOther, accountsURL(requestPeriod(c)))
+}
+
+// DeleteAccount removes an account nothing has been assigned to
+func (h *Handler) DeleteAccount(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.String(http.StatusBadRequest, "Invalid account ID")
+		return
+	}
+
+	err = h.svc.DeleteAccount(c.Request.Context(), id)
+	if formError(c, err, "Error deleting account", h.renderAccountsPage) {
+		return
+	}
+	c.Redirect(http.StatusSeeOther, accountsURL(requestPeriod(c)))
+}
+
+// CreateTransfer moves money between two accounts
+func (h *Handler) CreateTransfer(c *gin.Context) {
+	fromID, _ := strconv.ParseInt(c.PostForm("from_account_id"), 10, 64)
+	toID, _ := strconv.ParseInt(c.PostForm("to_account_id"), 10, 64)
+	amount, _ := strconv.ParseFloat(c.PostForm("amount"), 64)
+	period, ok := models.ParsePeriod(c.PostForm("period"))
+	if !ok {
+		period = requestPeriod(c)
+	}
+
+	_, err := h.svc.CreateTransfer(c.Request.Context(), service.TransferInput{
+		FromAccountID: fromID,
+		ToAccountID:   toID,
+		Amount:        amount,
+		Year:          period.Year,
+		Month:         period.Month,
+		Note:          c.PostForm("note"),
+	})
+	if formError(c, err, "Error recording transfer", h.renderAccountsPage) {
+		return
+	}
+	c.Redirect(http.StatusSeeOther, accountsURL(requestPeriod(c)))
+}
+
+func (h *Handler) DeleteTransfer(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.String(http.StatusBadRequest, "Invalid transfer ID")
+		return
+	}
+
+	err = h.svc.DeleteTransfer(c.Request.Context(), id)
+	if formError(c, err, "Error deleting transfer", h.renderAccountsPage) {
+		return
+	}
+	c.Redirect(http.StatusSeeOther, accountsURL(requestPeriod(c)))
+}
+
+// AccountOptions returns the account options for an expense row's select
+func (h *Handler) AccountOptions(c *gin.Context) {
+	accounts, err := h.svc.ListAccounts(c.Request.Context())
+	if err != nil {
+		c.String(http.StatusInternalServerError, "Error loading accounts: %v", err)
+		return
+	}
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	components.AccountOptions(accounts, parseOptionalID(c.Query("selected"))).Render(c.Request.Context(), c.Writer)
+}
+
+// AccountBalances returns the accounts panel's running balances for a period
+func (h *Handler) AccountBalances(c *gin.Context) {
+	balances, err := h.svc.AccountBalances(c.Request.Context(), requestPeriod(c))
+	if err != nil {
+		c.String(errorStatus(err), "Error loading accounts: %v", err)
+		return
+	}
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	components.AccountBalances(balances).Render(c.Request.Context(), c.Writer)
+}
+
+func accountsURL(period models.Period) string {
+	return fmt.Sprintf("/accounts?year=%d&month=%d", period.Year, period.Month)
+}
+
+func accountInputFromForm(c *gin.Context) service.AccountInput {
+	opening, _ := strconv.ParseFloat(c.PostForm("opening_balance"), 64)
+	return service.AccountInput{
+		Name:           c.PostForm("name"),
+		Type:           models.AccountType(c.PostForm("account_type")),
+		OpeningBalance: opening,
+	}
+}
+
+func (h *Handler) renderAccountsPage(c *gin.Context, status int, errMsg string) {
+	ctx := c.Request.Context()
+	period := requestPeriod(c)
+	page := templates.AccountsPage{
+		Period:  period,
+		CanEdit: auth.MembershipFromContext(ctx).Role.CanEdit(),
+	}
+
+	var err error
+	if page.Balances, err = h.svc.AccountBalances(ctx, period); err != nil {
+		c.String(errorStatus(err), "Error loading accounts: %v", err)
+		return
+	}
+	if page.Transfers, err = h.svc.ListTransfers(ctx); err != nil {
+		c.String(errorStatus(err), "Error loading transfers: %v", err)
+		return
+	}
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(status)
+	templates.Accounts(page, errMsg).Render(ctx, c.Writer)
+}
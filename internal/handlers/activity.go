@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"spending-tracker/internal/service"
+	"spending-tracker/templates"
+)
+
+// ActivityPage lists the household's recent changes
+func (h *Handler) ActivityPage(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if page < 1 {
+		page = 1
+	}
+
+	entries, err := h.svc.Activity(c.Request.Context(), page)
+	if err != nil {
+		c.String(errorStatus(err), "Error loading activity: %v", err)
+		return
+	}
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	templates.Activity(entries, page, len(entries) == service.ActivityPageSize).Render(c.Request.Context(), c.Writer)
+}
+
+// UndoChange reverses a delete from the activity feed or an undo toast
+func (h *Handler) UndoChange(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.String(http.StatusBadRequest, "Invalid activity ID")
+		return
+	}
+
+	if _, err := h.svc.Undo(c.Request.Context(), id); err != nil {
+		c.String(errorStatus(err), "Error undoing change: %v", err)
+		return
+	}
+
+	// The restored record can appear anywhere on the page, so reload it
+	if c.GetHeader("HX-Request") != "" {
+		c.Header("HX-Refresh", "true")
+		c.Status(http.StatusNoContent)
+		return
+	}
+	c.Redirect(http.StatusSeeOther, "/activity")
+}
@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"spending-tracker/internal/auth"
+	"spending-tracker/internal/service"
+	"spending-tracker/templates"
+	"spending-tracker/templates/components"
+)
+
+// LoginPage renders the login form, or sends first-time visitors to setup
+func (h *Handler) LoginPage(c *gin.Context) {
+	needsSetup, err := h.svc.NeedsSetup(c.Request.Context())
+	if err != nil {
+		c.String(http.StatusInternalServerError, "Error loading users: %v", err)
+		return
+	}
+	if needsSetup {
+		c.Redirect(http.StatusSeeOther, "/setup")
+		return
+	}
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	templates.Login("", "", safeNext(c.Query("next"))).Render(c.Request.Context(), c.Writer)
+}
+
+// Login checks credentials and starts a session
+func (h *Handler) Login(c *gin.Context) {
+	username := c.PostForm("username")
+	next := safeNext(c.PostForm("next"))
+
+	user, err := h.svc.Authenticate(c.Request.Context(), username, c.PostForm("password"))
+	if errors.Is(err, service.ErrInvalidCredentials) {
+		c.Header("Content-Type", "text/html; charset=utf-8")
+		c.Status(http.StatusUnauthorized)
+		templates.Login(err.Error(), username, next).Render(c.Request.Context(), c.Writer)
+		return
+	}
+	if err != nil {
+		c.String(http.StatusInternalServerError, "Error signing in: %v", err)
+		return
+	}
+
+	if !h.startSession(c, user.ID) {
+		return
+	}
+	c.Redirect(http.StatusSeeOther, next)
+}
+
+// Logout ends the current session
+func (h *Handler) Logout(c *gin.Context) {
+	if token, err := c.Cookie(auth.SessionCookie); err == nil {
+		h.svc.EndSession(c.Request.Context(), token)
+	}
+	setCookie(c, auth.SessionCookie, "", time.Unix(0, 0))
+	c.Redirect(http.StatusSeeOther, "/login")
+}
+
+// SetupPage renders the first-run form for creating the initial admin
+func (h *Handler) SetupPage(c *gin.Context) {
+	needsSetup, err := h.svc.NeedsSetup(c.Request.Context())
+	if err != nil {
+		c.String(http.StatusInternalServerError, "Error loading users: %v", err)
+		return
+	}
+	if !needsSetup {
+		c.Redirect(http.StatusSeeOther, "/login")
+		return
+	}
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	templates.Setup("", "").Render(c.Request.Context(), c.Writer)
+}
+
+// Setup creates the initial admin and signs them in
+func (h *Handler) Setup(c *gin.Context) {
+	username := c.PostForm("username")
+	password := c.PostForm("password")
+
+	if password != c.PostForm("confirm_password") {
+		c.Header("Content-Type", "text/html; charset=utf-8")
+		c.Status(http.StatusBadRequest)
+		templates.Setup("Passwords do not match", username).Render(c.Request.Context(), c.Writer)
+		return
+	}
+
+	user, err := h.svc.Setup(c.Request.Context(), username, password)
+	if errors.Is(err, service.ErrSetupComplete) {
+		c.Redirect(http.StatusSeeOther, "/login")
+		return
+	}
+	var validationErr *service.ValidationError
+	if errors.As(err, &validationErr) {
+		c.Header("Content-Type", "text/html; charset=utf-8")
+		c.Status(http.StatusBadRequest)
+		templates.Setup(validationErr.Message, username).Render(c.Request.Context(), c.Writer)
+		return
+	}
+	if err != nil {
+		c.String(http.StatusInternalServerError, "Error creating account: %v", err)
+		return
+	}
+
+	if !h.startSession(c, user.ID) {
+		return
+	}
+	c.Redirect(http.StatusSeeOther, "/")
+}
+
+// UsersPage lists accounts and lets an admin add new ones
+func (h *Handler) UsersPage(c *gin.Context) {
+	h.renderUsersPage(c, http.StatusOK, "")
+}
+
+// CreateUser adds a local account
+func (h *Handler) CreateUser(c *gin.Context) {
+	_, err := h.svc.CreateUser(c.Request.Context(), c.PostForm("username"), c.PostForm("password"), c.PostForm("is_admin") == "on")
+	var validationErr *service.ValidationError
+	if errors.As(err, &validationErr) {
+		h.renderUsersPage(c, http.StatusBadRequest, validationErr.Message)
+		return
+	}
+	if err != nil {
+		c.String(http.StatusInternalServerError, "Error creating user: %v", err)
+		return
+	}
+
+	c.Redirect(http.StatusSeeOther, "/users")
+}
+
+func (h *Handler) renderUsersPage(c *gin.Context, status int, errMsg string) {
+	users, err := h.svc.ListUsers(c.Request.Context())
+	if err != nil {
+		c.String(http.StatusInternalServerError, "Error loading users: %v", err)
+		return
+	}
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(status)
+	templates.Users(users, errMsg).Render(c.Request.Context(), c.Writer)
+}
+
+// Unauthorized sends signed-out visitors to the login page. HTMX requests
+// get an HX-Redirect so the whole page navigates rather than a fragment.
+func (h *Handler) Unauthorized(c *gin.Context) {
+	loginURL := "/login"
+	if c.Request.Method == http.MethodGet && c.GetHeader("HX-Request") == "" {
+		loginURL += "?next=" + c.Request.URL.RequestURI()
+	}
+
+	if c.GetHeader("HX-Request") != "" {
+		c.Header("HX-Redirect", loginURL)
+		c.Status(http.StatusUnauthorized)
+		return
+	}
+	c.Redirect(http.StatusSeeOther, loginURL)
+}
+
+// CSRFRejected rejects mutations without a valid CSRF token. HTMX requests
+// get an error toast appended to the page instead of a swapped fragment.
+func (h *Handler) CSRFRejected(c *gin.Context) {
+	message := "Your session token did not match. Reload the page and try again."
+	if c.GetHeader("HX-Request") != "" {
+		c.Header("HX-Retarget", "body")
+		c.Header("HX-Reswap", "beforeend")
+	}
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(http.StatusForbidden)
+	components.ErrorToast(message).Render(c.Request.Context(), c.Writer)
+}
+
+// Forbidden rejects signed-in users lacking permission
+func (h *Handler) Forbidden(c *gin.Context) {
+	c.String(http.StatusForbidden, "You do not have permission to do that")
+}
+
+func (h *Handler) startSession(c *gin.Context, userID int64) bool {
+	token, expiresAt, err := h.svc.StartSession(c.Request.Context(), userID)
+	if err != nil {
+		c.String(http.StatusInternalServerError, "Error starting session: %v", err)
+		return false
+	}
+	setCookie(c, auth.SessionCookie, token, expiresAt)
+	return true
+}
+
+// setCookie writes an HttpOnly cookie. It is marked Secure when served over
+// TLS or when COOKIE_SECURE=true (e.g. behind a TLS-terminating proxy).
+func setCookie(c *gin.Context, name, value string, expiresAt time.Time) {
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     "/",
+		Expires:  expiresAt,
+		HttpOnly: true,
+		Secure:   c.Request.TLS != nil || os.Getenv("COOKIE_SECURE") == "true",
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// safeNext only allows redirects to local paths
+func safeNext(next string) string {
+	if !strings.HasPrefix(next, "/") || strings.HasPrefix(next, "//") || strings.HasPrefix(next, "/\\") {
+		return "/"
+	}
+	return next
+}
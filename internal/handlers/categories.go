@@ -5,13 +5,14 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
-	"spending-tracker/db"
+	"spending-tracker/internal/service"
+	"spending-tracker/models"
 	"spending-tracker/templates/components"
 )
 
 // GetCategories returns the list of all categories
 func (h *Handler) GetCategories(c *gin.Context) {
-	categories, err := db.GetAllCategories(c.Request.Context())
+	categories, err := h.svc.ListCategories(c.Request.Context())
 	if err != nil {
 		c.String(http.StatusInternalServerError, "Error loading categories: %v", err)
 		return
@@ -25,7 +26,7 @@ func (h *Handler) GetCategories(c *gin.Context) {
 func (h *Handler) GetCategoryOptions(c *gin.Context) {
 	selected, _ := strconv.ParseInt(c.Query("selected"), 10, 64)
 
-	categories, err := db.GetAllCategories(c.Request.Context())
+	categories, err := h.svc.ListCategories(c.Request.Context())
 	if err != nil {
 		c.String(http.StatusInternalServerError, "Error loading categories: %v", err)
 		return
@@ -37,21 +38,24 @@ func (h *Handler) GetCategoryOptions(c *gin.Context) {
 	}
 
 	c.Header("Content-Type", "text/html; charset=utf-8")
-	components.CategoryOptions(categories, selectedPtr).Render(c.Request.Context(), c.Writer)
+	components.CategoryOptions(models.ActiveCategories(categories, selectedPtr), selectedPtr).Render(c.Request.Context(), c.Writer)
 }
 
-// CreateCategory creates a new category
+// CreateCategory creates a new category, optionally nested under a parent
 func (h *Handler) CreateCategory(c *gin.Context) {
-	name := c.PostForm("name")
-	color := c.PostForm("color")
+	input := service.CategoryInput{
+		Name:     c.PostForm("name"),
+		Color:    c.PostForm("color"),
+		ParentID: parseOptionalID(c.PostForm("parent_id")),
+		Budget:   parseOptionalAmount(c.PostForm("budget")),
+	}
 
-	_, err := db.CreateCategory(c.Request.Context(), name, color)
-	if err != nil {
-		c.String(http.StatusInternalServerError, "Error creating category: %v", err)
+	if _, err := h.svc.CreateCategory(c.Request.Context(), input); err != nil {
+		c.String(errorStatus(err), "Error creating category: %v", err)
 		return
 	}
 
-	categories, err := db.GetAllCategories(c.Request.Context())
+	categories, err := h.svc.ListCategories(c.Request.Context())
 	if err != nil {
 		c.String(http.StatusInternalServerError, "Error loading categories: %v", err)
 		return
@@ -67,8 +71,9 @@ func (h *Handler) UpdateCategory(c *gin.Context) {
 	name := c.PostForm("name")
 	color := c.PostForm("color")
 
-	if err := db.UpdateCategory(c.Request.Context(), id, name, color); err != nil {
-		c.String(http.StatusInternalServerError, "Error updating category: %v", err)
+	cat, err := h.svc.RenameCategory(c.Request.Context(), id, name, color)
+	if err != nil {
+		c.String(errorStatus(err), "Error updating category: %v", err)
 		return
 	}
 
@@ -76,31 +81,62 @@ func (h *Handler) UpdateCategory(c *gin.Context) {
 	// Trigger expense list refresh after category update
 	c.Header("HX-Trigger", "categoryUpdated")
 
+	categories, err := h.svc.ListCategories(c.Request.Context())
+	if err != nil {
+		c.String(http.StatusInternalServerError, "Error loading categories: %v", err)
+		return
+	}
+
 	// If inline=true, return just the updated category item
 	if c.Query("inline") == "true" {
-		cat, err := db.GetCategoryByID(c.Request.Context(), id)
-		if err != nil {
-			c.String(http.StatusInternalServerError, "Error loading category: %v", err)
-			return
-		}
-		components.CategoryItem(*cat).Render(c.Request.Context(), c.Writer)
+		components.CategoryItem(*cat, categories).Render(c.Request.Context(), c.Writer)
 		return
 	}
 
 	// Otherwise return the full list
-	categories, err := db.GetAllCategories(c.Request.Context())
+	components.CategoryList(categories).Render(c.Request.Context(), c.Writer)
+}
+
+// UpdateCategoryParent moves a category under a new parent, or to the top level
+func (h *Handler) UpdateCategoryParent(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	parentID := parseOptionalID(c.PostForm("parent_id"))
+
+	if _, err := h.svc.SetCategoryParent(c.Request.Context(), id, parentID); err != nil {
+		c.String(errorStatus(err), "Error updating category: %v", err)
+		return
+	}
+
+	h.renderCategoryList(c)
+}
+
+// UpdateCategoryBudget sets or clears the monthly budget for a category
+func (h *Handler) UpdateCategoryBudget(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	budget := parseOptionalAmount(c.PostForm("budget"))
+
+	cat, err := h.svc.SetCategoryBudget(c.Request.Context(), id, budget)
+	if err != nil {
+		c.String(errorStatus(err), "Error updating budget: %v", err)
+		return
+	}
+
+	categories, err := h.svc.ListCategories(c.Request.Context())
 	if err != nil {
 		c.String(http.StatusInternalServerError, "Error loading categories: %v", err)
 		return
 	}
-	components.CategoryList(categories).Render(c.Request.Context(), c.Writer)
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Header("HX-Trigger", "categoryUpdated")
+	components.CategoryItem(*cat, categories).Render(c.Request.Context(), c.Writer)
 }
 
 // EditCategoryName returns the inline edit form for a category name
 func (h *Handler) EditCategoryName(c *gin.Context) {
 	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
 
-	cat, err := db.GetCategoryByID(c.Request.Context(), id)
+	cat, err := h.svc.GetCategory(c.Request.Context(), id)
 	if err != nil {
 		c.String(http.StatusNotFound, "Category not found")
 		return
@@ -114,7 +150,7 @@ func (h *Handler) EditCategoryName(c *gin.Context) {
 func (h *Handler) EditCategoryColor(c *gin.Context) {
 	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
 
-	cat, err := db.GetCategoryByID(c.Request.Context(), id)
+	cat, err := h.svc.GetCategory(c.Request.Context(), id)
 	if err != nil {
 		c.String(http.StatusNotFound, "Category not found")
 		return
@@ -124,28 +160,130 @@ func (h *Handler) EditCategoryColor(c *gin.Context) {
 	components.CategoryColorEdit(*cat).Render(c.Request.Context(), c.Writer)
 }
 
-// DeleteCategory deletes a category
+// ConfirmDeleteCategory returns the delete panel, showing how many rows
+// reference the category and asking for a replacement if any do
+func (h *Handler) ConfirmDeleteCategory(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+
+	cat, usage, categories, ok := h.loadCategoryForRemoval(c, id)
+	if !ok {
+		return
+	}
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	components.CategoryDeletePanel(*cat, usage, categories).Render(c.Request.Context(), c.Writer)
+}
+
+// ConfirmMergeCategory returns the panel for merging a category into another
+func (h *Handler) ConfirmMergeCategory(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+
+	cat, usage, categories, ok := h.loadCategoryForRemoval(c, id)
+	if !ok {
+		return
+	}
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	components.CategoryMergePanel(*cat, usage, categories).Render(c.Request.Context(), c.Writer)
+}
+
+// DeleteCategory deletes a category. Categories still in use must name a
+// replacement, which receives their expenses, templates and subcategories.
 func (h *Handler) DeleteCategory(c *gin.Context) {
 	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	replacementID := parseOptionalID(c.Query("replacement_id"))
 
-	if err := db.DeleteCategory(c.Request.Context(), id); err != nil {
-		c.String(http.StatusInternalServerError, "Error deleting category: %v", err)
+	entry, err := h.svc.DeleteCategory(c.Request.Context(), id, replacementID)
+	if err != nil {
+		c.String(errorStatus(err), "Error deleting category: %v", err)
+		return
+	}
+
+	h.renderCategoryList(c)
+	components.UndoToastOOB(entry).Render(c.Request.Context(), c.Writer)
+}
+
+// MergeCategory merges a category into another and deletes it
+func (h *Handler) MergeCategory(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	targetID := parseOptionalID(c.PostForm("into_id"))
+
+	if targetID == nil {
+		c.String(http.StatusBadRequest, "Choose a category to merge into")
+		return
+	}
+
+	if err := h.svc.MergeCategories(c.Request.Context(), id, *targetID); err != nil {
+		c.String(errorStatus(err), "Error merging category: %v", err)
+		return
+	}
+
+	h.renderCategoryList(c)
+}
+
+// ArchiveCategory hides a category from pickers while keeping its history
+func (h *Handler) ArchiveCategory(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+
+	if err := h.svc.ArchiveCategory(c.Request.Context(), id); err != nil {
+		c.String(errorStatus(err), "Error archiving category: %v", err)
+		return
+	}
+
+	h.renderCategoryList(c)
+}
+
+// UnarchiveCategory restores an archived category
+func (h *Handler) UnarchiveCategory(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+
+	if err := h.svc.UnarchiveCategory(c.Request.Context(), id); err != nil {
+		c.String(errorStatus(err), "Error restoring category: %v", err)
 		return
 	}
 
-	categories, err := db.GetAllCategories(c.Request.Context())
+	h.renderCategoryList(c)
+}
+
+func (h *Handler) loadCategoryForRemoval(c *gin.Context, id int64) (*models.Category, models.CategoryUsage, []models.Category, bool) {
+	cat, err := h.svc.GetCategory(c.Request.Context(), id)
+	if err != nil {
+		c.String(errorStatus(err), "Error loading category: %v", err)
+		return nil, models.CategoryUsage{}, nil, false
+	}
+
+	usage, err := h.svc.CategoryUsage(c.Request.Context(), id)
+	if err != nil {
+		c.String(errorStatus(err), "Error loading category usage: %v", err)
+		return nil, models.CategoryUsage{}, nil, false
+	}
+
+	categories, err := h.svc.ListCategories(c.Request.Context())
+	if err != nil {
+		c.String(http.StatusInternalServerError, "Error loading categories: %v", err)
+		return nil, models.CategoryUsage{}, nil, false
+	}
+
+	return cat, usage, categories, true
+}
+
+// renderCategoryList renders the full category list and asks the expense
+// list to refresh, since category changes can relabel existing rows
+func (h *Handler) renderCategoryList(c *gin.Context) {
+	categories, err := h.svc.ListCategories(c.Request.Context())
 	if err != nil {
 		c.String(http.StatusInternalServerError, "Error loading categories: %v", err)
 		return
 	}
 
 	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Header("HX-Trigger", "categoryUpdated")
 	components.CategoryList(categories).Render(c.Request.Context(), c.Writer)
 }
 
 // CategoryModal returns the manage categories modal form
 func (h *Handler) CategoryModal(c *gin.Context) {
-	categories, err := db.GetAllCategories(c.Request.Context())
+	categories, err := h.svc.ListCategories(c.Request.Context())
 	if err != nil {
 		c.String(http.StatusInternalServerError, "Error loading categories: %v", err)
 		return
@@ -154,3 +292,21 @@ func (h *Handler) CategoryModal(c *gin.Context) {
 	c.Header("Content-Type", "text/html; charset=utf-8")
 	components.CategoryModal(categories).Render(c.Request.Context(), c.Writer)
 }
+
+// parseOptionalID parses a form ID, treating blank or non-positive values as unset
+func parseOptionalID(value string) *int64 {
+	id, _ := strconv.ParseInt(value, 10, 64)
+	if id <= 0 {
+		return nil
+	}
+	return &id
+}
+
+// parseOptionalAmount parses a form amount, treating blank or non-positive values as unset
+func parseOptionalAmount(value string) *float64 {
+	amount, err := strconv.ParseFloat(value, 64)
+	if err != nil || amount <= 0 {
+		return nil
+	}
+	return &amount
+}
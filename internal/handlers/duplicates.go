@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"spending-tracker/internal/auth"
+	"spending-tracker/internal/service"
+	"spending-tracker/models"
+	"spending-tracker/templates"
+	"spending-tracker/templates/components"
+)
+
+// CheckDuplicates warns in the add modal about expenses the one being
+// entered may duplicate
+func (h *Handler) CheckDuplicates(c *gin.Context) {
+	year, _ := strconv.Atoi(c.Query("year"))
+	month, _ := strconv.Atoi(c.Query("month"))
+	amount, _ := strconv.ParseFloat(c.Query("amount"), 64)
+
+	duplicates, err := h.svc.DuplicatesOf(c.Request.Context(), models.Period{Year: year, Month: month}, service.ExpenseInput{
+		Description: c.Query("description"),
+		Amount:      amount,
+	})
+	if err != nil {
+		c.String(errorStatus(err), "Error checking for duplicates: %v", err)
+		return
+	}
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	components.DuplicateWarning(duplicates).Render(c.Request.Context(), c.Writer)
+}
+
+// DuplicatesPage reports the expenses in a period that look like duplicates
+func (h *Handler) DuplicatesPage(c *gin.Context) {
+	h.renderDuplicatesPage(c, http.StatusOK, requestPeriod(c), "")
+}
+
+// MergeDuplicates keeps the original expense and deletes its duplicates
+func (h *Handler) MergeDuplicates(c *gin.Context) {
+	keepID, err := strconv.ParseInt(c.PostForm("keep_id"), 10, 64)
+	if err != nil {
+		c.String(http.StatusBadRequest, "Invalid expense ID")
+		return
+	}
+	var duplicateIDs []int64
+	for _, value := range c.PostFormArray("duplicate_id") {
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			c.String(http.StatusBadRequest, "Invalid expense ID")
+			return
+		}
+		duplicateIDs = append(duplicateIDs, id)
+	}
+
+	period := requestPeriod(c)
+	_, err = h.svc.MergeDuplicates(c.Request.Context(), keepID, duplicateIDs)
+	var validationErr *service.ValidationError
+	if errors.As(err, &validationErr) {
+		h.renderDuplicatesPage(c, http.StatusBadRequest, period, validationErr.Message)
+		return
+	}
+	if err != nil {
+		c.String(errorStatus(err), "Error merging duplicates: %v", err)
+		return
+	}
+	c.Redirect(http.StatusSeeOther, duplicatesURL(period))
+}
+
+func duplicatesURL(period models.Period) string {
+	return fmt.Sprintf("/duplicates?year=%d&month=%d", period.Year, period.Month)
+}
+
+func (h *Handler) renderDuplicatesPage(c *gin.Context, status int, period models.Period, errMsg string) {
+	ctx := c.Request.Context()
+	groups, err := h.svc.FindDuplicates(ctx, period)
+	if err != nil {
+		c.String(errorStatus(err), "Error finding duplicates: %v", err)
+		return
+	}
+
+	page := templates.DuplicatesPage{
+		Period:  period,
+		Groups:  groups,
+		CanEdit: auth.MembershipFromContext(ctx).Role.CanEdit(),
+	}
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(status)
+	templates.Duplicates(page, errMsg).Render(ctx, c.Writer)
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"spending-tracker/internal/auth"
+	"spending-tracker/models"
+	"spending-tracker/templates/components"
+)
+
+// keepAliveInterval keeps idle event streams from being closed by proxies
+const keepAliveInterval = 30 * time.Second
+
+// Events streams changes to a period as Server-Sent Events until the page
+// goes away. Changes made by the subscribing tab itself are not sent.
+func (h *Handler) Events(c *gin.Context) {
+	year, _ := strconv.Atoi(c.Query("year"))
+	month, _ := strconv.Atoi(c.Query("month"))
+	period := models.Period{Year: year, Month: month}
+
+	membership := auth.MembershipFromContext(c.Request.Context())
+	changes, unsubscribe := h.hub.Subscribe(membership.Household.ID, period, c.Query("tab"))
+	defer unsubscribe()
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case event, ok := <-changes:
+			if !ok {
+				return false
+			}
+			// Browsers drop events without data, so the kind doubles as the payload
+			c.SSEvent(string(event.Kind), string(event.Kind))
+			return true
+		case <-keepAlive.C:
+			io.WriteString(w, ": keep-alive\n\n")
+			return true
+		case <-c.Request.Context().Done():
+			return false
+		}
+	})
+}
+
+// GetSummary returns the period's summary fragments as out-of-band swaps
+func (h *Handler) GetSummary(c *gin.Context) {
+	year, _ := strconv.Atoi(c.Query("year"))
+	month, _ := strconv.Atoi(c.Query("month"))
+
+	period := models.Period{Year: year, Month: month}
+	state, err := h.svc.LoadPeriod(c.Request.Context(), period, models.FilterAll)
+	if err != nil {
+		c.String(errorStatus(err), "Error loading data: %v", err)
+		return
+	}
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	components.SummaryOOB(state.Summary, state.Income, period).Render(c.Request.Context(), c.Writer)
+}
@@ -1,11 +1,13 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
-	"spending-tracker/db"
+	"spending-tracker/internal/service"
 	"spending-tracker/models"
 	"spending-tracker/templates/components"
 )
@@ -17,9 +19,9 @@ func (h *Handler) GetExpenses(c *gin.Context) {
 	filter := models.ExpenseFilter(c.DefaultQuery("filter", "all"))
 
 	period := models.Period{Year: year, Month: month}
-	state, err := h.loadAppState(c.Request.Context(), period, filter)
+	state, err := h.svc.LoadPeriod(c.Request.Context(), period, filter)
 	if err != nil {
-		c.String(http.StatusInternalServerError, "Error loading expenses: %v", err)
+		c.String(errorStatus(err), "Error loading expenses: %v", err)
 		return
 	}
 
@@ -31,81 +33,194 @@ func (h *Handler) GetExpenses(c *gin.Context) {
 func (h *Handler) CreateExpense(c *gin.Context) {
 	year, _ := strconv.Atoi(c.PostForm("year"))
 	month, _ := strconv.Atoi(c.PostForm("month"))
-	description := c.PostForm("description")
-	amount, _ := strconv.ParseFloat(c.PostForm("amount"), 64)
-	categoryID, _ := strconv.ParseInt(c.PostForm("category_id"), 10, 64)
-	expenseType := models.ExpenseType(c.PostForm("expense_type"))
-
-	var catIDPtr *int64
-	if categoryID > 0 {
-		catIDPtr = &categoryID
-	}
+	period := models.Period{Year: year, Month: month}
 
-	expense := models.Expense{
-		Description: description,
-		Amount:      amount,
-		CategoryID:  catIDPtr,
-		Type:        expenseType,
-		Year:        year,
-		Month:       month,
+	created, err := h.svc.CreateExpense(c.Request.Context(), period, expenseInputFromForm(c))
+	if err != nil {
+		c.String(errorStatus(err), "Error creating expense: %v", err)
+		return
 	}
 
-	created, err := db.CreateExpense(c.Request.Context(), expense)
+	state, err := h.svc.LoadPeriod(c.Request.Context(), period, models.FilterAll)
 	if err != nil {
-		c.String(http.StatusInternalServerError, "Error creating expense: %v", err)
+		c.String(errorStatus(err), "Error loading data: %v", err)
 		return
 	}
 
-	if expenseType == models.ExpenseTypeRecurring {
-		db.CreateRecurringExpense(c.Request.Context(), description, amount, catIDPtr)
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	if created.IsReimbursable() {
+		c.Header("HX-Trigger", "accountsChanged, reimbursementsChanged")
+	} else {
+		c.Header("HX-Trigger", "accountsChanged")
 	}
+	components.ExpenseRowWithOOB(*created, state.Categories, state.Summary, state.Income, period).Render(c.Request.Context(), c.Writer)
+}
 
-	period := models.Period{Year: year, Month: month}
-	state, err := h.loadAppState(c.Request.Context(), period, models.FilterAll)
+// GetExpenseRow returns a single expense row, e.g. to discard a conflicting edit
+func (h *Handler) GetExpenseRow(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	year, _ := strconv.Atoi(c.Query("year"))
+	month, _ := strconv.Atoi(c.Query("month"))
+
+	expense, err := h.svc.GetExpense(c.Request.Context(), id)
+	if err != nil {
+		c.String(errorStatus(err), "Error loading expense: %v", err)
+		return
+	}
+	categories, err := h.svc.ListCategories(c.Request.Context())
 	if err != nil {
-		c.String(http.StatusInternalServerError, "Error loading data: %v", err)
+		c.String(http.StatusInternalServerError, "Error loading categories: %v", err)
 		return
 	}
 
 	c.Header("Content-Type", "text/html; charset=utf-8")
-	components.ExpenseRowWithOOB(*created, state.Categories, state.Summary, state.Income, period).Render(c.Request.Context(), c.Writer)
+	components.ExpenseRow(*expense, categories, models.Period{Year: year, Month: month}).Render(c.Request.Context(), c.Writer)
 }
 
-// UpdateExpense updates an existing expense
+// UpdateExpense updates an existing expense. An edit based on a stale
+// version re-renders the row with both versions to choose between.
 func (h *Handler) UpdateExpense(c *gin.Context) {
 	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
-	description := c.PostForm("description")
-	amount, _ := strconv.ParseFloat(c.PostForm("amount"), 64)
-	categoryID, _ := strconv.ParseInt(c.PostForm("category_id"), 10, 64)
-	expenseType := models.ExpenseType(c.PostForm("expense_type"))
 	year, _ := strconv.Atoi(c.PostForm("year"))
 	month, _ := strconv.Atoi(c.PostForm("month"))
+	period := models.Period{Year: year, Month: month}
 
-	var catIDPtr *int64
-	if categoryID > 0 {
-		catIDPtr = &categoryID
+	input := expenseInputFromForm(c)
+	expense, err := h.svc.UpdateExpense(c.Request.Context(), id, input)
+	var conflictErr *service.ConflictError
+	if errors.As(err, &conflictErr) {
+		h.renderExpenseConflict(c, input, *conflictErr.Current, period)
+		return
+	}
+	var validationErr *service.ValidationError
+	if errors.As(err, &validationErr) && input.Splits != nil {
+		h.renderSplitEditorError(c, id, input, period, validationErr.Message)
+		return
+	}
+	if err != nil {
+		c.String(errorStatus(err), "Error updating expense: %v", err)
+		return
 	}
 
-	if err := db.UpdateExpense(c.Request.Context(), id, description, amount, catIDPtr, expenseType); err != nil {
-		c.String(http.StatusInternalServerError, "Error updating expense: %v", err)
+	state, err := h.svc.LoadPeriod(c.Request.Context(), period, models.FilterAll)
+	if err != nil {
+		c.String(errorStatus(err), "Error loading data: %v", err)
+		return
+	}
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	components.ExpenseRowWithOOB(*expense, state.Categories, state.Summary, state.Income, period).Render(c.Request.Context(), c.Writer)
+}
+
+func (h *Handler) renderExpenseConflict(c *gin.Context, input service.ExpenseInput, current models.Expense, period models.Period) {
+	categories, err := h.svc.ListCategories(c.Request.Context())
+	if err != nil {
+		c.String(http.StatusInternalServerError, "Error loading categories: %v", err)
+		return
+	}
+
+	accounts, err := h.svc.ListAccounts(c.Request.Context())
+	if err != nil {
+		c.String(http.StatusInternalServerError, "Error loading accounts: %v", err)
+		return
+	}
+
+	mine := current
+	mine.Description = input.Description
+	mine.Amount = input.Amount
+	mine.CategoryID = input.CategoryID
+	mine.AccountID = input.AccountID
+	mine.AccountName = ""
+	for _, a := range accounts {
+		if input.AccountID != nil && a.ID == *input.AccountID {
+			mine.AccountName = a.Name
+		}
+	}
+	mine.Type = input.Type
+	mine.Tags = make([]models.Tag, len(input.Tags))
+	for i, name := range input.Tags {
+		mine.Tags[i] = models.Tag{Name: name}
+	}
+	if input.Splits != nil {
+		mine.Splits = splitLinesFromInput(input.Splits, categories)
+	}
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Header("HX-Retarget", fmt.Sprintf("#expense-%d", current.ID))
+	c.Header("HX-Reswap", "outerHTML")
+	c.Status(http.StatusConflict)
+	components.ExpenseConflictRow(mine, current, categories, period).Render(c.Request.Context(), c.Writer)
+}
+
+// SplitEditor opens the split editor below an expense row
+func (h *Handler) SplitEditor(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	year, _ := strconv.Atoi(c.Query("year"))
+	month, _ := strconv.Atoi(c.Query("month"))
+
+	expense, err := h.svc.GetExpense(c.Request.Context(), id)
+	if err != nil {
+		c.String(errorStatus(err), "Error loading expense: %v", err)
+		return
+	}
+	categories, err := h.svc.ListCategories(c.Request.Context())
+	if err != nil {
+		c.String(http.StatusInternalServerError, "Error loading categories: %v", err)
 		return
 	}
 
 	period := models.Period{Year: year, Month: month}
-	state, err := h.loadAppState(c.Request.Context(), period, models.FilterAll)
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	components.SplitEditor(*expense, components.EditableSplits(*expense), categories, period, "").Render(c.Request.Context(), c.Writer)
+}
+
+// SplitLine returns an empty line for the split editor
+func (h *Handler) SplitLine(c *gin.Context) {
+	categories, err := h.svc.ListCategories(c.Request.Context())
 	if err != nil {
-		c.String(http.StatusInternalServerError, "Error loading data: %v", err)
+		c.String(http.StatusInternalServerError, "Error loading categories: %v", err)
 		return
 	}
 
-	expense, err := db.GetExpenseByID(c.Request.Context(), id)
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	components.SplitLine(models.ExpenseSplit{}, categories).Render(c.Request.Context(), c.Writer)
+}
+
+// renderSplitEditorError shows a rejected split in the editor, keeping what was entered
+func (h *Handler) renderSplitEditorError(c *gin.Context, id int64, input service.ExpenseInput, period models.Period, message string) {
+	expense, err := h.svc.GetExpense(c.Request.Context(), id)
+	if err != nil {
+		c.String(errorStatus(err), "Error loading expense: %v", err)
+		return
+	}
+	categories, err := h.svc.ListCategories(c.Request.Context())
 	if err != nil {
-		c.String(http.StatusInternalServerError, "Error loading expense: %v", err)
+		c.String(http.StatusInternalServerError, "Error loading categories: %v", err)
 		return
 	}
 
+	expense.Amount = input.Amount
+	lines := splitLinesFromInput(input.Splits, categories)
+
 	c.Header("Content-Type", "text/html; charset=utf-8")
-	components.ExpenseRowWithOOB(*expense, state.Categories, state.Summary, state.Income, period).Render(c.Request.Context(), c.Writer)
+	c.Header("HX-Retarget", fmt.Sprintf("#expense-splits-%d", id))
+	c.Header("HX-Reswap", "innerHTML")
+	c.Status(http.StatusBadRequest)
+	components.SplitEditor(*expense, lines, categories, period, message).Render(c.Request.Context(), c.Writer)
+}
+
+// splitLinesFromInput turns submitted split lines back into displayable ones
+func splitLinesFromInput(input []service.SplitInput, categories []models.Category) []models.ExpenseSplit {
+	lines := make([]models.ExpenseSplit, len(input))
+	for i, line := range input {
+		lines[i] = models.ExpenseSplit{CategoryID: line.CategoryID, Amount: line.Amount, Note: line.Note}
+		for _, cat := range categories {
+			if line.CategoryID != nil && cat.ID == *line.CategoryID {
+				lines[i].Category = &cat
+			}
+		}
+	}
+	return lines
 }
 
 // DeleteExpense deletes an expense
@@ -114,20 +229,23 @@ func (h *Handler) DeleteExpense(c *gin.Context) {
 	year, _ := strconv.Atoi(c.Query("year"))
 	month, _ := strconv.Atoi(c.Query("month"))
 
-	if err := db.DeleteExpense(c.Request.Context(), id); err != nil {
-		c.String(http.StatusInternalServerError, "Error deleting expense: %v", err)
+	entry, err := h.svc.DeleteExpense(c.Request.Context(), id)
+	if err != nil {
+		c.String(errorStatus(err), "Error deleting expense: %v", err)
 		return
 	}
 
 	period := models.Period{Year: year, Month: month}
-	state, err := h.loadAppState(c.Request.Context(), period, models.FilterAll)
+	state, err := h.svc.LoadPeriod(c.Request.Context(), period, models.FilterAll)
 	if err != nil {
-		c.String(http.StatusInternalServerError, "Error loading data: %v", err)
+		c.String(errorStatus(err), "Error loading data: %v", err)
 		return
 	}
 
 	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Header("HX-Trigger", "reimbursementsChanged")
 	components.SummaryOOB(state.Summary, state.Income, period).Render(c.Request.Context(), c.Writer)
+	components.UndoToastOOB(entry).Render(c.Request.Context(), c.Writer)
 }
 
 // ExpenseModal returns the add expense modal form
@@ -136,12 +254,71 @@ func (h *Handler) ExpenseModal(c *gin.Context) {
 	month, _ := strconv.Atoi(c.Query("month"))
 
 	period := models.Period{Year: year, Month: month}
-	categories, err := db.GetAllCategories(c.Request.Context())
+	categories, err := h.svc.ListCategories(c.Request.Context())
 	if err != nil {
 		c.String(http.StatusInternalServerError, "Error loading categories: %v", err)
 		return
 	}
+	accounts, err := h.svc.ListAccounts(c.Request.Context())
+	if err != nil {
+		c.String(http.StatusInternalServerError, "Error loading accounts: %v", err)
+		return
+	}
 
 	c.Header("Content-Type", "text/html; charset=utf-8")
-	components.AddExpenseModal(categories, period).Render(c.Request.Context(), c.Writer)
+	components.AddExpenseModal(models.ActiveCategories(categories, nil), accounts, period).Render(c.Request.Context(), c.Writer)
+}
+
+// expenseInputFromForm reads the expense fields posted by the row and modal forms
+func expenseInputFromForm(c *gin.Context) service.ExpenseInput {
+	amount, _ := strconv.ParseFloat(c.PostForm("amount"), 64)
+	version, _ := strconv.Atoi(c.PostForm("version"))
+	input := service.ExpenseInput{
+		Description: c.PostForm("description"),
+		Amount:      amount,
+		CategoryID:  parseOptionalID(c.PostForm("category_id")),
+		AccountID:   parseOptionalID(c.PostForm("account_id")),
+		Type:        models.ExpenseType(c.PostForm("expense_type")),
+		Tags:        models.ParseTags(c.PostForm("tags")),
+		Splits:      splitsFromForm(c),
+		Version:     version,
+	}
+	// Only the add modal asks who reimburses the expense
+	if from, ok := c.GetPostForm("reimbursable_from"); ok {
+		input.ReimbursableFrom = &from
+	}
+	return input
+}
+
+// splitsFromForm reads the split editor's lines. It returns nil when the form
+// has no split editor, and skips lines left completely blank.
+func splitsFromForm(c *gin.Context) []service.SplitInput {
+	if c.PostForm("split") != "true" {
+		return nil
+	}
+
+	categoryIDs := c.PostFormArray("split_category_id")
+	amounts := c.PostFormArray("split_amount")
+	notes := c.PostFormArray("split_note")
+
+	splits := []service.SplitInput{}
+	for i := range amounts {
+		var categoryID, note string
+		if i < len(categoryIDs) {
+			categoryID = categoryIDs[i]
+		}
+		if i < len(notes) {
+			note = notes[i]
+		}
+		if amounts[i] == "" && categoryID == "" && note == "" {
+			continue
+		}
+		amount, _ := strconv.ParseFloat(amounts[i], 64)
+		splits = append(splits, service.SplitInput{
+			CategoryID: parseOptionalID(categoryID),
+			Amount:     amount,
+			Note:       note,
+		})
+	}
+	return splits
 }
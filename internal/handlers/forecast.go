@@ -0,0 +1,19 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"spending-tracker/templates/components"
+)
+
+// Forecast returns the current period's end-of-month projection shown below
+// the summary cards
+func (h *Handler) Forecast(c *gin.Context) {
+	forecast, err := h.svc.Forecast(c.Request.Context())
+	if err != nil {
+		c.String(errorStatus(err), "Error forecasting: %v", err)
+		return
+	}
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	components.ForecastContent(*forecast).Render(c.Request.Context(), c.Writer)
+}
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"spending-tracker/internal/auth"
+	"spending-tracker/internal/service"
+	"spending-tracker/models"
+	"spending-tracker/templates"
+)
+
+// GoalsPage shows the savings goals and what was put toward them in a period
+func (h *Handler) GoalsPage(c *gin.Context) {
+	h.renderGoalsPage(c, http.StatusOK, "")
+}
+
+func (h *Handler) CreateGoal(c *gin.Context) {
+	_, err := h.svc.CreateGoal(c.Request.Context(), goalInputFromForm(c))
+	if formError(c, err, "Error adding goal", h.renderGoalsPage) {
+		return
+	}
+	c.Redirect(http.StatusSeeOther, goalsURL(requestPeriod(c)))
+}
+
+func (h *Handler) UpdateGoal(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.String(http.StatusBadRequest, "Invalid goal ID")
+		return
+	}
+
+	_, err = h.svc.UpdateGoal(c.Request.Context(), id, goalInputFromForm(c))
+	if formError(c, err, "Error updating goal", h.renderGoalsPage) {
+		return
+	}
+	c.Redirect(http.StatusSeeOther, goalsURL(requestPeriod(c)))
+}
+
+func (h *Handler) DeleteGoal(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.String(http.StatusBadRequest, "Invalid goal ID")
+		return
+	}
+
+	err = h.svc.DeleteGoal(c.Request.Context(), id)
+	if formError(c, err, "Error deleting goal", h.renderGoalsPage) {
+		return
+	}
+	c.Redirect(http.StatusSeeOther, goalsURL(requestPeriod(c)))
+}
+
+// CreateContribution records money put toward a goal in the page's period
+func (h *Handler) CreateContribution(c *gin.Context) {
+	goalID, _ := strconv.ParseInt(c.PostForm("goal_id"), 10, 64)
+	amount, _ := strconv.ParseFloat(c.PostForm("amount"), 64)
+	period := requestPeriod(c)
+
+	_, err := h.svc.CreateContribution(c.Request.Context(), service.ContributionInput{
+		GoalID: goalID,
+		Amount: amount,
+		Year:   period.Year,
+		Month:  period.Month,
+		Note:   c.PostForm("note"),
+	})
+	if formError(c, err, "Error recording contribution", h.renderGoalsPage) {
+		return
+	}
+	c.Redirect(http.StatusSeeOther, goalsURL(period))
+}
+
+func (h *Handler) DeleteContribution(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.String(http.StatusBadRequest, "Invalid contribution ID")
+		return
+	}
+
+	err = h.svc.DeleteContribution(c.Request.Context(), id)
+	if formError(c, err, "Error deleting contribution", h.renderGoalsPage) {
+		return
+	}
+	c.Redirect(http.StatusSeeOther, goalsURL(requestPeriod(c)))
+}
+
+func goalInputFromForm(c *gin.Context) service.GoalInput {
+	target, _ := strconv.ParseFloat(c.PostForm("target_amount"), 64)
+	return service.GoalInput{
+		Name:         c.PostForm("name"),
+		TargetAmount: target,
+		TargetDate:   c.PostForm("target_date"),
+		AccountID:    parseOptionalID(c.PostForm("account_id")),
+	}
+}
+
+func goalsURL(period models.Period) string {
+	return fmt.Sprintf("/goals?year=%d&month=%d", period.Year, period.Month)
+}
+
+func (h *Handler) renderGoalsPage(c *gin.Context, status int, errMsg string) {
+	ctx := c.Request.Context()
+	period := requestPeriod(c)
+	page := templates.GoalsPage{
+		Period:  period,
+		CanEdit: auth.MembershipFromContext(ctx).Role.CanEdit(),
+	}
+
+	var err error
+	if page.Goals, err = h.svc.ListGoals(ctx); err != nil {
+		c.String(errorStatus(err), "Error loading goals: %v", err)
+		return
+	}
+	if page.Contributions, err = h.svc.ListContributions(ctx, period); err != nil {
+		c.String(errorStatus(err), "Error loading contributions: %v", err)
+		return
+	}
+	if page.Accounts, err = h.svc.ListAccounts(ctx); err != nil {
+		c.String(errorStatus(err), "Error loading accounts: %v", err)
+		return
+	}
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(status)
+	templates.Goals(page, errMsg).Render(ctx, c.Writer)
+}
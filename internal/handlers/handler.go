@@ -1,61 +1,68 @@
 package handlers
 
 import (
-	"context"
-	"spending-tracker/db"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"spending-tracker/internal/events"
+	"spending-tracker/internal/service"
 	"spending-tracker/models"
 )
 
 // Handler handles HTTP requests for the application
-type Handler struct{}
+type Handler struct {
+	svc *service.Service
+	hub *events.Hub
+}
 
 // NewHandler creates a new Handler instance
-func NewHandler() *Handler {
-	return &Handler{}
+func NewHandler(svc *service.Service, hub *events.Hub) *Handler {
+	return &Handler{svc: svc, hub: hub}
 }
 
-// loadAppState loads the complete application state for a given period and filter
-func (h *Handler) loadAppState(ctx context.Context, period models.Period, filter models.ExpenseFilter) (models.AppState, error) {
-	if err := db.InitializeMonth(ctx, period.Year, period.Month); err != nil {
-		return models.AppState{}, err
-	}
-
-	income, err := db.GetIncomeByPeriod(ctx, period.Year, period.Month)
-	if err != nil {
-		return models.AppState{}, err
-	}
-
-	var expenses []models.Expense
-	switch filter {
-	case models.FilterRecurring:
-		expenses, err = db.GetExpensesByPeriodAndType(ctx, period.Year, period.Month, models.ExpenseTypeRecurring)
-	case models.FilterOneTime:
-		expenses, err = db.GetExpensesByPeriodAndType(ctx, period.Year, period.Month, models.ExpenseTypeOneTime)
+// errorStatus maps a service error to the HTTP status to report it with
+func errorStatus(err error) int {
+	var validationErr *service.ValidationError
+	var conflictErr *service.ConflictError
+	switch {
+	case errors.As(err, &validationErr):
+		return http.StatusBadRequest
+	case errors.As(err, &conflictErr):
+		return http.StatusConflict
+	case errors.Is(err, service.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrForbidden):
+		return http.StatusForbidden
 	default:
-		expenses, err = db.GetExpensesByPeriod(ctx, period.Year, period.Month)
-	}
-	if err != nil {
-		return models.AppState{}, err
+		return http.StatusInternalServerError
 	}
+}
 
-	allExpenses, err := db.GetExpensesByPeriod(ctx, period.Year, period.Month)
-	if err != nil {
-		return models.AppState{}, err
+// formError reports err and returns whether there was one. Validation errors
+// re-render the form's page through render so they can be corrected; others
+// are reported as plain text after prefix.
+func formError(c *gin.Context, err error, prefix string, render func(c *gin.Context, status int, errMsg string)) bool {
+	if err == nil {
+		return false
 	}
 
-	categories, err := db.GetAllCategories(ctx)
-	if err != nil {
-		return models.AppState{}, err
+	var validationErr *service.ValidationError
+	if errors.As(err, &validationErr) {
+		render(c, http.StatusBadRequest, validationErr.Message)
+		return true
 	}
+	c.String(errorStatus(err), "%s: %v", prefix, err)
+	return true
+}
 
-	summary := models.CalculateSummary(income, allExpenses, period.DaysInMonth())
-
-	return models.AppState{
-		Period:     period,
-		Income:     income,
-		Expenses:   expenses,
-		Categories: categories,
-		Summary:    summary,
-		Filter:     filter,
-	}, nil
+// requestPeriod reads the period from the query or form, defaulting to the current one
+func requestPeriod(c *gin.Context) models.Period {
+	year, yearErr := strconv.Atoi(c.Request.FormValue("year"))
+	month, monthErr := strconv.Atoi(c.Request.FormValue("month"))
+	if yearErr == nil && monthErr == nil && month >= 1 && month <= 12 {
+		return models.Period{Year: year, Month: month}
+	}
+	return models.CurrentPeriod()
 }
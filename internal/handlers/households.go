@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"spending-tracker/internal/auth"
+	"spending-tracker/models"
+	"spending-tracker/templates"
+)
+
+// householdCookieLifetime is how long the switcher remembers a choice
+const householdCookieLifetime = 365 * 24 * time.Hour
+
+// HouseholdsPage lists the user's households and manages the active one
+func (h *Handler) HouseholdsPage(c *gin.Context) {
+	h.renderHouseholdsPage(c, http.StatusOK, "")
+}
+
+// CreateHousehold creates a household owned by the user and switches to it
+func (h *Handler) CreateHousehold(c *gin.Context) {
+	household, err := h.svc.CreateHousehold(c.Request.Context(), c.PostForm("name"))
+	if formError(c, err, "Error creating household", h.renderHouseholdsPage) {
+		return
+	}
+
+	setCookie(c, auth.HouseholdCookie, strconv.FormatInt(household.ID, 10), time.Now().Add(householdCookieLifetime))
+	c.Redirect(http.StatusSeeOther, "/")
+}
+
+// SwitchHousehold makes another of the user's households active
+func (h *Handler) SwitchHousehold(c *gin.Context) {
+	id, err := strconv.ParseInt(c.PostForm("household_id"), 10, 64)
+	if err != nil {
+		c.String(http.StatusBadRequest, "Invalid household ID")
+		return
+	}
+
+	user := auth.UserFromContext(c.Request.Context())
+	if _, err := h.svc.Membership(c.Request.Context(), user.ID, id); err != nil {
+		c.String(errorStatus(err), "Error switching household: %v", err)
+		return
+	}
+
+	setCookie(c, auth.HouseholdCookie, strconv.FormatInt(id, 10), time.Now().Add(householdCookieLifetime))
+	if c.GetHeader("HX-Request") != "" {
+		c.Header("HX-Redirect", "/")
+		c.Status(http.StatusNoContent)
+		return
+	}
+	c.Redirect(http.StatusSeeOther, "/")
+}
+
+// RenameHousehold renames the active household
+func (h *Handler) RenameHousehold(c *gin.Context) {
+	err := h.svc.RenameHousehold(c.Request.Context(), c.PostForm("name"))
+	if formError(c, err, "Error renaming household", h.renderHouseholdsPage) {
+		return
+	}
+	c.Redirect(http.StatusSeeOther, "/households")
+}
+
+// AddHouseholdMember adds an existing user to the active household
+func (h *Handler) AddHouseholdMember(c *gin.Context) {
+	err := h.svc.AddHouseholdMember(c.Request.Context(), c.PostForm("username"), models.Role(c.PostForm("role")))
+	if formError(c, err, "Error adding member", h.renderHouseholdsPage) {
+		return
+	}
+	c.Redirect(http.StatusSeeOther, "/households")
+}
+
+// UpdateHouseholdMember changes a member's role
+func (h *Handler) UpdateHouseholdMember(c *gin.Context) {
+	userID, err := strconv.ParseInt(c.Param("user_id"), 10, 64)
+	if err != nil {
+		c.String(http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	err = h.svc.SetHouseholdMemberRole(c.Request.Context(), userID, models.Role(c.PostForm("role")))
+	if formError(c, err, "Error updating member", h.renderHouseholdsPage) {
+		return
+	}
+	c.Redirect(http.StatusSeeOther, "/households")
+}
+
+// RemoveHouseholdMember removes a member from the active household
+func (h *Handler) RemoveHouseholdMember(c *gin.Context) {
+	userID, err := strconv.ParseInt(c.Param("user_id"), 10, 64)
+	if err != nil {
+		c.String(http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	err = h.svc.RemoveHouseholdMember(c.Request.Context(), userID)
+	if formError(c, err, "Error removing member", h.renderHouseholdsPage) {
+		return
+	}
+	c.Redirect(http.StatusSeeOther, "/households")
+}
+
+func (h *Handler) renderHouseholdsPage(c *gin.Context, status int, errMsg string) {
+	ctx := c.Request.Context()
+	households, err := h.svc.ListHouseholds(ctx)
+	if err != nil {
+		c.String(errorStatus(err), "Error loading households: %v", err)
+		return
+	}
+
+	members, err := h.svc.HouseholdMembers(ctx)
+	if err != nil {
+		c.String(errorStatus(err), "Error loading members: %v", err)
+		return
+	}
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(status)
+	templates.Households(*auth.MembershipFromContext(ctx), households, members, errMsg).Render(ctx, c.Writer)
+}
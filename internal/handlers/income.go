@@ -1,33 +1,68 @@
 package handlers
 
 import (
-	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
-	"spending-tracker/db"
 	"spending-tracker/models"
 	"spending-tracker/templates/components"
 )
 
+// GetIncome returns the income section, e.g. after another tab changed it
+func (h *Handler) GetIncome(c *gin.Context) {
+	year, _ := strconv.Atoi(c.Query("year"))
+	month, _ := strconv.Atoi(c.Query("month"))
+
+	period := models.Period{Year: year, Month: month}
+	state, err := h.svc.LoadPeriod(c.Request.Context(), period, models.FilterAll)
+	if err != nil {
+		c.String(errorStatus(err), "Error loading income: %v", err)
+		return
+	}
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	components.IncomeSection(state).Render(c.Request.Context(), c.Writer)
+}
+
 // UpdateIncome updates the income for a given period
 func (h *Handler) UpdateIncome(c *gin.Context) {
 	year, _ := strconv.Atoi(c.PostForm("year"))
 	month, _ := strconv.Atoi(c.PostForm("month"))
 	amount, _ := strconv.ParseFloat(c.PostForm("amount"), 64)
 
-	if err := db.UpsertIncome(c.Request.Context(), year, month, amount); err != nil {
-		c.String(http.StatusInternalServerError, "Error updating income: %v", err)
+	period := models.Period{Year: year, Month: month}
+	if err := h.svc.SetIncome(c.Request.Context(), period, amount); err != nil {
+		c.String(errorStatus(err), "Error updating income: %v", err)
 		return
 	}
 
-	period := models.Period{Year: year, Month: month}
-	state, err := h.loadAppState(c.Request.Context(), period, models.FilterAll)
+	state, err := h.svc.LoadPeriod(c.Request.Context(), period, models.FilterAll)
 	if err != nil {
-		c.String(http.StatusInternalServerError, "Error loading data: %v", err)
+		c.String(errorStatus(err), "Error loading data: %v", err)
 		return
 	}
 
 	c.Header("Content-Type", "text/html; charset=utf-8")
 	components.IncomeWithOOB(state).Render(c.Request.Context(), c.Writer)
 }
+
+// UpdateIncomeAccount records which account the period's income was paid into
+func (h *Handler) UpdateIncomeAccount(c *gin.Context) {
+	year, _ := strconv.Atoi(c.PostForm("year"))
+	month, _ := strconv.Atoi(c.PostForm("month"))
+
+	period := models.Period{Year: year, Month: month}
+	if err := h.svc.SetIncomeAccount(c.Request.Context(), period, parseOptionalID(c.PostForm("account_id"))); err != nil {
+		c.String(errorStatus(err), "Error updating income: %v", err)
+		return
+	}
+
+	state, err := h.svc.LoadPeriod(c.Request.Context(), period, models.FilterAll)
+	if err != nil {
+		c.String(errorStatus(err), "Error loading data: %v", err)
+		return
+	}
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	components.IncomeSection(state).Render(c.Request.Context(), c.Writer)
+}
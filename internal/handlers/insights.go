@@ -0,0 +1,19 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"spending-tracker/templates/components"
+)
+
+// Insights returns the insights panel's list of what is unusual about a
+// period's spending
+func (h *Handler) Insights(c *gin.Context) {
+	anomalies, err := h.svc.Anomalies(c.Request.Context(), requestPeriod(c))
+	if err != nil {
+		c.String(errorStatus(err), "Error finding anomalies: %v", err)
+		return
+	}
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	components.InsightsContent(anomalies).Render(c.Request.Context(), c.Writer)
+}
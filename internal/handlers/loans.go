@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"spending-tracker/internal/auth"
+	"spending-tracker/internal/service"
+	"spending-tracker/models"
+	"spending-tracker/templates"
+	"spending-tracker/templates/components"
+)
+
+// LoansPage shows the loans with what is owed on each and compares paying
+// them off by the snowball and avalanche strategies
+func (h *Handler) LoansPage(c *gin.Context) {
+	h.renderLoansPage(c, http.StatusOK, "")
+}
+
+func (h *Handler) CreateLoan(c *gin.Context) {
+	_, err := h.svc.CreateLoan(c.Request.Context(), loanInputFromForm(c))
+	if formError(c, err, "Error adding loan", h.renderLoansPage) {
+		return
+	}
+	c.Redirect(http.StatusSeeOther, "/loans")
+}
+
+func (h *Handler) UpdateLoan(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.String(http.StatusBadRequest, "Invalid loan ID")
+		return
+	}
+
+	_, err = h.svc.UpdateLoan(c.Request.Context(), id, loanInputFromForm(c))
+	if formError(c, err, "Error updating loan", h.renderLoansPage) {
+		return
+	}
+	c.Redirect(http.StatusSeeOther, "/loans")
+}
+
+func (h *Handler) DeleteLoan(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.String(http.StatusBadRequest, "Invalid loan ID")
+		return
+	}
+
+	err = h.svc.DeleteLoan(c.Request.Context(), id)
+	if formError(c, err, "Error deleting loan", h.renderLoansPage) {
+		return
+	}
+	c.Redirect(http.StatusSeeOther, "/loans")
+}
+
+// LoanSchedulePage shows the month-by-month paying off of one loan
+func (h *Handler) LoanSchedulePage(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.String(http.StatusBadRequest, "Invalid loan ID")
+		return
+	}
+
+	loan, rows, paidOff, err := h.svc.LoanSchedule(c.Request.Context(), id)
+	if err != nil {
+		c.String(errorStatus(err), "Error loading loan: %v", err)
+		return
+	}
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	templates.LoanSchedule(*loan, rows, paidOff).Render(c.Request.Context(), c.Writer)
+}
+
+// LoanEditor opens the editor recording an expense as an extra payment
+// toward a loan below its row
+func (h *Handler) LoanEditor(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	year, _ := strconv.Atoi(c.Query("year"))
+	month, _ := strconv.Atoi(c.Query("month"))
+	h.renderLoanEditor(c, http.StatusOK, id, models.Period{Year: year, Month: month}, "")
+}
+
+// UpdateExpenseLoan records an expense as an extra payment toward a loan,
+// or makes it an ordinary expense again
+func (h *Handler) UpdateExpenseLoan(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	year, _ := strconv.Atoi(c.PostForm("year"))
+	month, _ := strconv.Atoi(c.PostForm("month"))
+	period := models.Period{Year: year, Month: month}
+
+	expense, err := h.svc.SetExpenseLoan(c.Request.Context(), id, parseOptionalID(c.PostForm("loan_id")))
+	if err != nil {
+		var validationErr *service.ValidationError
+		if errors.As(err, &validationErr) {
+			c.Header("HX-Retarget", fmt.Sprintf("#expense-loan-%d", id))
+			c.Header("HX-Reswap", "innerHTML")
+			h.renderLoanEditor(c, http.StatusBadRequest, id, period, validationErr.Message)
+			return
+		}
+		c.String(errorStatus(err), "Error updating expense: %v", err)
+		return
+	}
+
+	categories, err := h.svc.ListCategories(c.Request.Context())
+	if err != nil {
+		c.String(http.StatusInternalServerError, "Error loading categories: %v", err)
+		return
+	}
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	components.ExpenseRow(*expense, categories, period).Render(c.Request.Context(), c.Writer)
+}
+
+func (h *Handler) renderLoanEditor(c *gin.Context, status int, id int64, period models.Period, errMsg string) {
+	expense, err := h.svc.GetExpense(c.Request.Context(), id)
+	if err != nil {
+		c.String(errorStatus(err), "Error loading expense: %v", err)
+		return
+	}
+	loans, err := h.svc.ListLoans(c.Request.Context())
+	if err != nil {
+		c.String(errorStatus(err), "Error loading loans: %v", err)
+		return
+	}
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(status)
+	components.LoanEditor(*expense, loans, period, errMsg).Render(c.Request.Context(), c.Writer)
+}
+
+func loanInputFromForm(c *gin.Context) service.LoanInput {
+	principal, _ := strconv.ParseFloat(c.PostForm("principal"), 64)
+	apr, _ := strconv.ParseFloat(c.PostForm("apr"), 64)
+	minimum, _ := strconv.ParseFloat(c.PostForm("minimum_payment"), 64)
+	input := service.LoanInput{
+		Name:               c.PostForm("name"),
+		Principal:          principal,
+		APR:                apr,
+		MinimumPayment:     minimum,
+		RecurringExpenseID: parseOptionalID(c.PostForm("recurring_expense_id")),
+	}
+	if start, ok := models.ParsePeriod(c.PostForm("start")); ok {
+		input.StartYear, input.StartMonth = start.Year, start.Month
+	}
+	return input
+}
+
+func (h *Handler) renderLoansPage(c *gin.Context, status int, errMsg string) {
+	ctx := c.Request.Context()
+	page := templates.LoansPage{
+		CanEdit: auth.MembershipFromContext(ctx).Role.CanEdit(),
+	}
+	if extra, err := strconv.ParseFloat(c.Query("extra"), 64); err == nil {
+		page.Extra = extra
+	}
+
+	var err error
+	if page.Loans, err = h.svc.ListLoans(ctx); err != nil {
+		c.String(errorStatus(err), "Error loading loans: %v", err)
+		return
+	}
+	if page.Recurring, err = h.svc.ListRecurring(ctx); err != nil {
+		c.String(errorStatus(err), "Error loading recurring expenses: %v", err)
+		return
+	}
+	page.Plans, err = h.svc.PayoffPlans(ctx, page.Extra)
+	var validationErr *service.ValidationError
+	if errors.As(err, &validationErr) {
+		status, errMsg = http.StatusBadRequest, validationErr.Message
+		page.Extra = 0
+		page.Plans, err = h.svc.PayoffPlans(ctx, 0)
+	}
+	if err != nil {
+		c.String(errorStatus(err), "Error planning payoff: %v", err)
+		return
+	}
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(status)
+	templates.Loans(page, errMsg).Render(ctx, c.Writer)
+}
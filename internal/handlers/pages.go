@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -10,12 +9,19 @@ import (
 	"spending-tracker/templates/components"
 )
 
-// Index renders the main index page for the current period
+// Index renders the main index page for the current period, or for the
+// period given by the year and month query parameters
 func (h *Handler) Index(c *gin.Context) {
 	period := models.CurrentPeriod()
-	state, err := h.loadAppState(c.Request.Context(), period, models.FilterAll)
+	year, yearErr := strconv.Atoi(c.Query("year"))
+	month, monthErr := strconv.Atoi(c.Query("month"))
+	if yearErr == nil && monthErr == nil && month >= 1 && month <= 12 {
+		period = models.Period{Year: year, Month: month}
+	}
+
+	state, err := h.svc.LoadPeriod(c.Request.Context(), period, models.FilterAll)
 	if err != nil {
-		c.String(http.StatusInternalServerError, "Error loading data: %v", err)
+		c.String(errorStatus(err), "Error loading data: %v", err)
 		return
 	}
 
@@ -30,9 +36,9 @@ func (h *Handler) Period(c *gin.Context) {
 	filter := models.ExpenseFilter(c.DefaultQuery("filter", "all"))
 
 	period := models.Period{Year: year, Month: month}
-	state, err := h.loadAppState(c.Request.Context(), period, filter)
+	state, err := h.svc.LoadPeriod(c.Request.Context(), period, filter)
 	if err != nil {
-		c.String(http.StatusInternalServerError, "Error loading data: %v", err)
+		c.String(errorStatus(err), "Error loading data: %v", err)
 		return
 	}
 
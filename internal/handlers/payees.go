@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"spending-tracker/internal/auth"
+	"spending-tracker/internal/service"
+	"spending-tracker/templates"
+)
+
+// PayeesPage lists the household's payees with what has been spent with each
+func (h *Handler) PayeesPage(c *gin.Context) {
+	h.renderPayeesPage(c, http.StatusOK, "")
+}
+
+// PayeeDetail shows what has been spent with a payee in each period
+func (h *Handler) PayeeDetail(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.String(http.StatusBadRequest, "Invalid payee ID")
+		return
+	}
+
+	history, err := h.svc.PayeeHistory(c.Request.Context(), id)
+	if err != nil {
+		c.String(errorStatus(err), "Error loading payee: %v", err)
+		return
+	}
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	templates.PayeeDetail(*history).Render(c.Request.Context(), c.Writer)
+}
+
+func (h *Handler) CreatePayee(c *gin.Context) {
+	_, err := h.svc.CreatePayee(c.Request.Context(), payeeInputFromForm(c))
+	if formError(c, err, "Error adding payee", h.renderPayeesPage) {
+		return
+	}
+	c.Redirect(http.StatusSeeOther, "/payees")
+}
+
+func (h *Handler) UpdatePayee(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.String(http.StatusBadRequest, "Invalid payee ID")
+		return
+	}
+
+	_, err = h.svc.UpdatePayee(c.Request.Context(), id, payeeInputFromForm(c))
+	if formError(c, err, "Error updating payee", h.renderPayeesPage) {
+		return
+	}
+	c.Redirect(http.StatusSeeOther, "/payees")
+}
+
+func (h *Handler) DeletePayee(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.String(http.StatusBadRequest, "Invalid payee ID")
+		return
+	}
+
+	err = h.svc.DeletePayee(c.Request.Context(), id)
+	if formError(c, err, "Error deleting payee", h.renderPayeesPage) {
+		return
+	}
+	c.Redirect(http.StatusSeeOther, "/payees")
+}
+
+func payeeInputFromForm(c *gin.Context) service.PayeeInput {
+	return service.PayeeInput{
+		Name:              c.PostForm("name"),
+		DefaultCategoryID: parseOptionalID(c.PostForm("default_category_id")),
+		Aliases:           strings.Split(c.PostForm("aliases"), ","),
+	}
+}
+
+func (h *Handler) renderPayeesPage(c *gin.Context, status int, errMsg string) {
+	ctx := c.Request.Context()
+	page := templates.PayeesPage{CanEdit: auth.MembershipFromContext(ctx).Role.CanEdit()}
+
+	var err error
+	if page.Totals, err = h.svc.PayeeTotals(ctx); err != nil {
+		c.String(errorStatus(err), "Error loading payees: %v", err)
+		return
+	}
+	if page.Categories, err = h.svc.ListCategories(ctx); err != nil {
+		c.String(errorStatus(err), "Error loading categories: %v", err)
+		return
+	}
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(status)
+	templates.Payees(page, errMsg).Render(ctx, c.Writer)
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"spending-tracker/internal/service"
+	"spending-tracker/models"
+	"spending-tracker/templates"
+)
+
+// ProjectionPage shows the cash-flow projection for the next months. What-if
+// adjustments come from the query string, so they are never saved.
+func (h *Handler) ProjectionPage(c *gin.Context) {
+	ctx := c.Request.Context()
+	adjustments := projectionAdjustmentsFromQuery(c)
+
+	status, errMsg := http.StatusOK, ""
+	projection, err := h.svc.Projection(ctx, adjustments)
+	var validationErr *service.ValidationError
+	if errors.As(err, &validationErr) {
+		status, errMsg = http.StatusBadRequest, validationErr.Message
+		projection, err = h.svc.Projection(ctx, models.ProjectionAdjustments{})
+	}
+	if err != nil {
+		c.String(errorStatus(err), "Error projecting cash flow: %v", err)
+		return
+	}
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(status)
+	templates.Projection(*projection, errMsg).Render(ctx, c.Writer)
+}
+
+// projectionAdjustmentsFromQuery reads the what-if form: recurring expenses
+// to cancel, a change to income and one-off rows, skipping blank ones
+func projectionAdjustmentsFromQuery(c *gin.Context) models.ProjectionAdjustments {
+	var adjustments models.ProjectionAdjustments
+	for _, raw := range c.QueryArray("cancel") {
+		if id, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			adjustments.Cancel = append(adjustments.Cancel, id)
+		}
+	}
+	adjustments.IncomeChange, _ = strconv.ParseFloat(c.Query("income_change"), 64)
+
+	months := c.QueryArray("one_off_month")
+	amounts := c.QueryArray("one_off_amount")
+	descriptions := c.QueryArray("one_off_description")
+	for i, raw := range amounts {
+		amount, err := strconv.ParseFloat(raw, 64)
+		if err != nil || i >= len(months) {
+			continue
+		}
+		period, _ := models.ParsePeriod(months[i])
+		oneOff := models.OneOff{Year: period.Year, Month: period.Month, Amount: amount}
+		if i < len(descriptions) {
+			oneOff.Description = descriptions[i]
+		}
+		adjustments.OneOffs = append(adjustments.OneOffs, oneOff)
+	}
+	return adjustments
+}
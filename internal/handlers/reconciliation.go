@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"spending-tracker/internal/auth"
+	"spending-tracker/internal/service"
+	"spending-tracker/models"
+	"spending-tracker/templates"
+	"spending-tracker/templates/components"
+)
+
+// ReconcilePage shows an account's reconciliation against its statement
+func (h *Handler) ReconcilePage(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.String(http.StatusBadRequest, "Invalid account ID")
+		return
+	}
+	h.renderReconcilePage(c, http.StatusOK, id, "")
+}
+
+// StartReconciliation records the statement an account is reconciled against
+func (h *Handler) StartReconciliation(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.String(http.StatusBadRequest, "Invalid account ID")
+		return
+	}
+
+	balance, _ := strconv.ParseFloat(c.PostForm("statement_balance"), 64)
+	_, err = h.svc.StartReconciliation(c.Request.Context(), id, service.ReconciliationInput{
+		StatementDate:    c.PostForm("statement_date"),
+		StatementBalance: balance,
+	})
+	if formError(c, err, "Error starting reconciliation", h.reconcilePageFor(id)) {
+		return
+	}
+	c.Redirect(http.StatusSeeOther, reconcileURL(id))
+}
+
+// UpdateExpenseCleared ticks an expense off against the statement and
+// returns the updated summary
+func (h *Handler) UpdateExpenseCleared(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.String(http.StatusBadRequest, "Invalid account ID")
+		return
+	}
+	expenseID, err := strconv.ParseInt(c.Param("expense_id"), 10, 64)
+	if err != nil {
+		c.String(http.StatusBadRequest, "Invalid expense ID")
+		return
+	}
+
+	r, err := h.svc.SetExpenseCleared(c.Request.Context(), id, expenseID, c.PostForm("cleared") == "true")
+	if err != nil {
+		c.String(errorStatus(err), "Error updating expense: %v", err)
+		return
+	}
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	components.ReconciliationSummary(*r, true).Render(c.Request.Context(), c.Writer)
+}
+
+// CompleteReconciliation finishes reconciling once the difference is zero
+func (h *Handler) CompleteReconciliation(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.String(http.StatusBadRequest, "Invalid account ID")
+		return
+	}
+
+	_, err = h.svc.CompleteReconciliation(c.Request.Context(), id)
+	if formError(c, err, "Error completing reconciliation", h.reconcilePageFor(id)) {
+		return
+	}
+	c.Redirect(http.StatusSeeOther, reconcileURL(id))
+}
+
+// UnlockExpense releases a reconciled expense for editing and re-renders its row
+func (h *Handler) UnlockExpense(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	year, _ := strconv.Atoi(c.Query("year"))
+	month, _ := strconv.Atoi(c.Query("month"))
+
+	expense, err := h.svc.UnlockExpense(c.Request.Context(), id)
+	if err != nil {
+		c.String(errorStatus(err), "Error unlocking expense: %v", err)
+		return
+	}
+	categories, err := h.svc.ListCategories(c.Request.Context())
+	if err != nil {
+		c.String(http.StatusInternalServerError, "Error loading categories: %v", err)
+		return
+	}
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	components.ExpenseRow(*expense, categories, models.Period{Year: year, Month: month}).Render(c.Request.Context(), c.Writer)
+}
+
+func reconcileURL(accountID int64) string {
+	return fmt.Sprintf("/accounts/%d/reconcile", accountID)
+}
+
+// reconcilePageFor renders an account's reconciliation page for formError
+func (h *Handler) reconcilePageFor(id int64) func(c *gin.Context, status int, errMsg string) {
+	return func(c *gin.Context, status int, errMsg string) {
+		h.renderReconcilePage(c, status, id, errMsg)
+	}
+}
+
+func (h *Handler) renderReconcilePage(c *gin.Context, status int, id int64, errMsg string) {
+	ctx := c.Request.Context()
+	page, err := h.svc.Reconciliation(ctx, id)
+	if err != nil {
+		c.String(errorStatus(err), "Error loading reconciliation: %v", err)
+		return
+	}
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(status)
+	templates.Reconcile(*page, auth.MembershipFromContext(ctx).Role.CanEdit(), errMsg).Render(ctx, c.Writer)
+}
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"spending-tracker/internal/service"
+	"spending-tracker/models"
+	"spending-tracker/templates/components"
+)
+
+// RefundEditor opens the refund and reimbursement editor below an expense row
+func (h *Handler) RefundEditor(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	year, _ := strconv.Atoi(c.Query("year"))
+	month, _ := strconv.Atoi(c.Query("month"))
+	h.renderRefundEditor(c, http.StatusOK, id, models.Period{Year: year, Month: month}, "")
+}
+
+// CreateRefund records money coming back for an expense. The refund is added
+// to the open list when it lands in the period being viewed.
+func (h *Handler) CreateRefund(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	year, _ := strconv.Atoi(c.PostForm("year"))
+	month, _ := strconv.Atoi(c.PostForm("month"))
+	period := models.Period{Year: year, Month: month}
+
+	received, ok := models.ParsePeriod(c.PostForm("received"))
+	if !ok {
+		received = period
+	}
+	amount, _ := strconv.ParseFloat(c.PostForm("amount"), 64)
+
+	refund, err := h.svc.CreateRefund(c.Request.Context(), id, received, service.RefundInput{
+		Amount:      amount,
+		Description: c.PostForm("description"),
+		CategoryID:  parseOptionalID(c.PostForm("category_id")),
+	})
+	if formError(c, err, "Error recording refund", h.refundEditorFor(id, period)) {
+		return
+	}
+
+	expense, err := h.svc.GetExpense(c.Request.Context(), id)
+	if err != nil {
+		c.String(errorStatus(err), "Error loading expense: %v", err)
+		return
+	}
+	state, err := h.svc.LoadPeriod(c.Request.Context(), period, models.FilterAll)
+	if err != nil {
+		c.String(errorStatus(err), "Error loading data: %v", err)
+		return
+	}
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Header("HX-Trigger", "reimbursementsChanged")
+	components.ExpenseRowWithOOB(*expense, state.Categories, state.Summary, state.Income, period).Render(c.Request.Context(), c.Writer)
+	if refund.Year == period.Year && refund.Month == period.Month {
+		components.ExpenseRowAppendOOB(*refund, state.Categories, period).Render(c.Request.Context(), c.Writer)
+	}
+}
+
+// UpdateReimbursable records who owes an expense back
+func (h *Handler) UpdateReimbursable(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	year, _ := strconv.Atoi(c.PostForm("year"))
+	month, _ := strconv.Atoi(c.PostForm("month"))
+	period := models.Period{Year: year, Month: month}
+
+	expense, err := h.svc.SetReimbursable(c.Request.Context(), id, c.PostForm("reimbursable_from"))
+	if formError(c, err, "Error updating expense", h.refundEditorFor(id, period)) {
+		return
+	}
+
+	categories, err := h.svc.ListCategories(c.Request.Context())
+	if err != nil {
+		c.String(http.StatusInternalServerError, "Error loading categories: %v", err)
+		return
+	}
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Header("HX-Trigger", "reimbursementsChanged")
+	components.ExpenseRow(*expense, categories, period).Render(c.Request.Context(), c.Writer)
+}
+
+// PendingReimbursements lists the money still owed back
+func (h *Handler) PendingReimbursements(c *gin.Context) {
+	groups, err := h.svc.PendingReimbursements(c.Request.Context())
+	if err != nil {
+		c.String(errorStatus(err), "Error loading reimbursements: %v", err)
+		return
+	}
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	components.PendingReimbursements(groups).Render(c.Request.Context(), c.Writer)
+}
+
+// refundEditorFor shows errors in an expense's refund editor for formError
+func (h *Handler) refundEditorFor(id int64, period models.Period) func(c *gin.Context, status int, errMsg string) {
+	return func(c *gin.Context, status int, errMsg string) {
+		c.Header("HX-Retarget", fmt.Sprintf("#expense-refunds-%d", id))
+		c.Header("HX-Reswap", "innerHTML")
+		h.renderRefundEditor(c, status, id, period, errMsg)
+	}
+}
+
+func (h *Handler) renderRefundEditor(c *gin.Context, status int, id int64, period models.Period, errMsg string) {
+	expense, err := h.svc.GetExpense(c.Request.Context(), id)
+	if err != nil {
+		c.String(errorStatus(err), "Error loading expense: %v", err)
+		return
+	}
+	categories, err := h.svc.ListCategories(c.Request.Context())
+	if err != nil {
+		c.String(http.StatusInternalServerError, "Error loading categories: %v", err)
+		return
+	}
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(status)
+	components.RefundEditor(*expense, models.ActiveCategories(categories, expense.RefundCategoryID()), period, errMsg).Render(c.Request.Context(), c.Writer)
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"spending-tracker/models"
+	"spending-tracker/templates"
+	"spending-tracker/templates/components"
+)
+
+// SearchPage renders the cross-period expense search page
+func (h *Handler) SearchPage(c *gin.Context) {
+	search := models.ParseExpenseSearch(c.Request.URL.Query())
+
+	results, err := h.svc.SearchExpenses(c.Request.Context(), search)
+	if err != nil {
+		c.String(errorStatus(err), "Error searching expenses: %v", err)
+		return
+	}
+
+	categories, err := h.svc.ListCategories(c.Request.Context())
+	if err != nil {
+		c.String(http.StatusInternalServerError, "Error loading categories: %v", err)
+		return
+	}
+
+	tags, err := h.svc.ListTags(c.Request.Context())
+	if err != nil {
+		c.String(http.StatusInternalServerError, "Error loading tags: %v", err)
+		return
+	}
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	templates.Search(results, categories, tags).Render(c.Request.Context(), c.Writer)
+}
+
+// SearchResults returns a page of search results for the search form
+func (h *Handler) SearchResults(c *gin.Context) {
+	search := models.ParseExpenseSearch(c.Request.URL.Query())
+
+	results, err := h.svc.SearchExpenses(c.Request.Context(), search)
+	if err != nil {
+		c.String(errorStatus(err), "Error searching expenses: %v", err)
+		return
+	}
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	// Keep the address bar in sync so searches can be bookmarked and shared
+	c.Header("HX-Push-Url", "/search?"+c.Request.URL.RawQuery)
+	components.SearchResults(results).Render(c.Request.Context(), c.Writer)
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"spending-tracker/internal/service"
+	"spending-tracker/models"
+	"spending-tracker/templates"
+)
+
+// SettingsPage shows the user's API tokens
+func (h *Handler) SettingsPage(c *gin.Context) {
+	h.renderSettingsPage(c, http.StatusOK, "", "")
+}
+
+// CreateAPIToken issues a token and shows it once
+func (h *Handler) CreateAPIToken(c *gin.Context) {
+	expiresInDays, _ := strconv.Atoi(c.PostForm("expires_in_days"))
+	token, _, err := h.svc.CreateAPIToken(c.Request.Context(), service.APITokenInput{
+		Name:          c.PostForm("name"),
+		Scope:         models.TokenScope(c.PostForm("scope")),
+		ExpiresInDays: expiresInDays,
+	})
+	var validationErr *service.ValidationError
+	if errors.As(err, &validationErr) {
+		h.renderSettingsPage(c, http.StatusBadRequest, "", validationErr.Message)
+		return
+	}
+	if err != nil {
+		c.String(errorStatus(err), "Error creating token: %v", err)
+		return
+	}
+
+	// Rendered directly rather than redirected: this is the only time the token is shown
+	h.renderSettingsPage(c, http.StatusOK, token, "")
+}
+
+// RevokeAPIToken deletes a token
+func (h *Handler) RevokeAPIToken(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.String(http.StatusBadRequest, "Invalid token ID")
+		return
+	}
+
+	if err := h.svc.RevokeAPIToken(c.Request.Context(), id); err != nil {
+		c.String(errorStatus(err), "Error revoking token: %v", err)
+		return
+	}
+	c.Redirect(http.StatusSeeOther, "/settings")
+}
+
+func (h *Handler) renderSettingsPage(c *gin.Context, status int, newToken, errMsg string) {
+	tokens, err := h.svc.ListAPITokens(c.Request.Context())
+	if err != nil {
+		c.String(errorStatus(err), "Error loading tokens: %v", err)
+		return
+	}
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(status)
+	templates.Settings(tokens, newToken, errMsg).Render(c.Request.Context(), c.Writer)
+}
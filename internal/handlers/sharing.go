@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"spending-tracker/internal/auth"
+	"spending-tracker/internal/service"
+	"spending-tracker/models"
+	"spending-tracker/templates"
+	"spending-tracker/templates/components"
+)
+
+// SharingPage shows balances between the people sharing expenses
+func (h *Handler) SharingPage(c *gin.Context) {
+	h.renderSharingPage(c, http.StatusOK, "")
+}
+
+// CreatePerson adds someone to share expenses with
+func (h *Handler) CreatePerson(c *gin.Context) {
+	_, err := h.svc.CreatePerson(c.Request.Context(), c.PostForm("name"), parseOptionalID(c.PostForm("user_id")))
+	if formError(c, err, "Error adding person", h.renderSharingPage) {
+		return
+	}
+	c.Redirect(http.StatusSeeOther, "/sharing")
+}
+
+// DeletePerson removes someone with no shared expenses or settlements
+func (h *Handler) DeletePerson(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.String(http.StatusBadRequest, "Invalid person ID")
+		return
+	}
+
+	err = h.svc.DeletePerson(c.Request.Context(), id)
+	if formError(c, err, "Error removing person", h.renderSharingPage) {
+		return
+	}
+	c.Redirect(http.StatusSeeOther, "/sharing")
+}
+
+// RecordSettlement records a payment between two people
+func (h *Handler) RecordSettlement(c *gin.Context) {
+	fromID, _ := strconv.ParseInt(c.PostForm("from_person_id"), 10, 64)
+	toID, _ := strconv.ParseInt(c.PostForm("to_person_id"), 10, 64)
+	amount, _ := strconv.ParseFloat(c.PostForm("amount"), 64)
+
+	_, err := h.svc.RecordSettlement(c.Request.Context(), service.SettlementInput{
+		FromPersonID: fromID,
+		ToPersonID:   toID,
+		Amount:       amount,
+		Note:         c.PostForm("note"),
+	})
+	if formError(c, err, "Error recording payment", h.renderSharingPage) {
+		return
+	}
+	c.Redirect(http.StatusSeeOther, "/sharing")
+}
+
+// DeleteSettlement removes a recorded payment
+func (h *Handler) DeleteSettlement(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.String(http.StatusBadRequest, "Invalid payment ID")
+		return
+	}
+
+	err = h.svc.DeleteSettlement(c.Request.Context(), id)
+	if formError(c, err, "Error deleting payment", h.renderSharingPage) {
+		return
+	}
+	c.Redirect(http.StatusSeeOther, "/sharing")
+}
+
+// SharingEditor opens the editor for who paid an expense and who shares it
+func (h *Handler) SharingEditor(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	year, _ := strconv.Atoi(c.Query("year"))
+	month, _ := strconv.Atoi(c.Query("month"))
+	h.renderSharingEditor(c, http.StatusOK, id, models.Period{Year: year, Month: month}, "")
+}
+
+// UpdateExpenseSharing saves how an expense is shared and re-renders its row
+func (h *Handler) UpdateExpenseSharing(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	year, _ := strconv.Atoi(c.PostForm("year"))
+	month, _ := strconv.Atoi(c.PostForm("month"))
+	period := models.Period{Year: year, Month: month}
+
+	expense, err := h.svc.SetExpenseSharing(c.Request.Context(), id, sharingInputFromForm(c))
+	var validationErr *service.ValidationError
+	if errors.As(err, &validationErr) {
+		c.Header("HX-Retarget", fmt.Sprintf("#expense-sharing-%d", id))
+		c.Header("HX-Reswap", "innerHTML")
+		h.renderSharingEditor(c, http.StatusBadRequest, id, period, validationErr.Message)
+		return
+	}
+	if err != nil {
+		c.String(errorStatus(err), "Error sharing expense: %v", err)
+		return
+	}
+
+	state, err := h.svc.LoadPeriod(c.Request.Context(), period, models.FilterAll)
+	if err != nil {
+		c.String(errorStatus(err), "Error loading data: %v", err)
+		return
+	}
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	components.ExpenseRowWithOOB(*expense, state.Categories, state.Summary, state.Income, period).Render(c.Request.Context(), c.Writer)
+}
+
+func (h *Handler) renderSharingEditor(c *gin.Context, status int, id int64, period models.Period, errMsg string) {
+	expense, err := h.svc.GetExpense(c.Request.Context(), id)
+	if err != nil {
+		c.String(errorStatus(err), "Error loading expense: %v", err)
+		return
+	}
+	people, err := h.svc.ListPeople(c.Request.Context())
+	if err != nil {
+		c.String(errorStatus(err), "Error loading people: %v", err)
+		return
+	}
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(status)
+	components.SharingEditor(*expense, people, period, errMsg).Render(c.Request.Context(), c.Writer)
+}
+
+// sharingInputFromForm reads the sharing editor; "stop" clears the sharing
+func sharingInputFromForm(c *gin.Context) service.SharingInput {
+	if c.PostForm("stop") == "true" {
+		return service.SharingInput{}
+	}
+
+	input := service.SharingInput{
+		PaidBy: parseOptionalID(c.PostForm("paid_by")),
+		Method: models.ShareMethod(c.PostForm("method")),
+	}
+	for _, raw := range c.PostFormArray("person_id") {
+		personID, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			continue
+		}
+		value, _ := strconv.ParseFloat(c.PostForm(fmt.Sprintf("value_%d", personID)), 64)
+		input.Participants = append(input.Participants, service.ShareInput{PersonID: personID, Value: value})
+	}
+	return input
+}
+
+func (h *Handler) renderSharingPage(c *gin.Context, status int, errMsg string) {
+	ctx := c.Request.Context()
+	page := templates.SharingPage{CanEdit: auth.MembershipFromContext(ctx).Role.CanEdit()}
+
+	var err error
+	if page.People, err = h.svc.ListPeople(ctx); err != nil {
+		c.String(errorStatus(err), "Error loading people: %v", err)
+		return
+	}
+	if page.Members, err = h.svc.HouseholdMembers(ctx); err != nil {
+		c.String(errorStatus(err), "Error loading members: %v", err)
+		return
+	}
+	if page.Balances, page.Debts, err = h.svc.Balances(ctx); err != nil {
+		c.String(errorStatus(err), "Error loading balances: %v", err)
+		return
+	}
+	if page.Settlements, err = h.svc.ListSettlements(ctx); err != nil {
+		c.String(errorStatus(err), "Error loading payments: %v", err)
+		return
+	}
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(status)
+	templates.Sharing(page, errMsg).Render(ctx, c.Writer)
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"spending-tracker/internal/auth"
+	"spending-tracker/internal/service"
+	"spending-tracker/models"
+	"spending-tracker/templates"
+)
+
+// SubscriptionsPage reports on the recurring expenses as of a period
+func (h *Handler) SubscriptionsPage(c *gin.Context) {
+	h.renderSubscriptionsPage(c, http.StatusOK, requestPeriod(c), "")
+}
+
+// TrackSubscription adds a recurring expense for charges that look like a
+// subscription
+func (h *Handler) TrackSubscription(c *gin.Context) {
+	amount, _ := strconv.ParseFloat(c.PostForm("amount"), 64)
+	period := requestPeriod(c)
+
+	_, err := h.svc.CreateRecurring(c.Request.Context(), service.RecurringInput{
+		Description: c.PostForm("description"),
+		Amount:      amount,
+		CategoryID:  parseOptionalID(c.PostForm("category_id")),
+	})
+	var validationErr *service.ValidationError
+	if errors.As(err, &validationErr) {
+		h.renderSubscriptionsPage(c, http.StatusBadRequest, period, validationErr.Message)
+		return
+	}
+	if err != nil {
+		c.String(errorStatus(err), "Error tracking subscription: %v", err)
+		return
+	}
+	c.Redirect(http.StatusSeeOther, subscriptionsURL(period))
+}
+
+func subscriptionsURL(period models.Period) string {
+	return fmt.Sprintf("/subscriptions?year=%d&month=%d", period.Year, period.Month)
+}
+
+func (h *Handler) renderSubscriptionsPage(c *gin.Context, status int, period models.Period, errMsg string) {
+	ctx := c.Request.Context()
+	report, err := h.svc.Subscriptions(ctx, period)
+	if err != nil {
+		c.String(errorStatus(err), "Error loading subscriptions: %v", err)
+		return
+	}
+
+	page := templates.SubscriptionsPage{
+		Report:  *report,
+		CanEdit: auth.MembershipFromContext(ctx).Role.CanEdit(),
+	}
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(status)
+	templates.Subscriptions(page, errMsg).Render(ctx, c.Writer)
+}
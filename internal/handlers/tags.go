@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"spending-tracker/models"
+	"spending-tracker/templates/components"
+)
+
+// TagSuggestions returns datalist options completing the last tag being typed
+func (h *Handler) TagSuggestions(c *gin.Context) {
+	suggestions, err := h.svc.SuggestTags(c.Request.Context(), c.Query("tags"))
+	if err != nil {
+		c.String(errorStatus(err), "Error loading tags: %v", err)
+		return
+	}
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	components.TagSuggestionOptions(suggestions).Render(c.Request.Context(), c.Writer)
+}
+
+// TagTotals returns spending per tag over a range of periods
+func (h *Handler) TagTotals(c *gin.Context) {
+	to, ok := models.ParsePeriod(c.Query("to"))
+	if !ok {
+		to = models.CurrentPeriod()
+	}
+	from, ok := models.ParsePeriod(c.Query("from"))
+	if !ok {
+		from = to.AddMonths(-11)
+	}
+	if from.Index() > to.Index() {
+		from, to = to, from
+	}
+
+	totals, err := h.svc.TagTotals(c.Request.Context(), from, to)
+	if err != nil {
+		c.String(errorStatus(err), "Error loading tag totals: %v", err)
+		return
+	}
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	components.TagTotalsContent(totals, from, to).Render(c.Request.Context(), c.Writer)
+}
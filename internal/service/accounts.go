@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"spending-tracker/db"
+	"spending-tracker/models"
+)
+
+// AccountInput holds the editable fields of an account
+type AccountInput struct {
+	Name           string             `json:"name"`
+	Type           models.AccountType `json:"account_type"`
+	OpeningBalance float64            `json:"opening_balance"`
+}
+
+// TransferInput moves money between two accounts in a period
+type TransferInput struct {
+	FromAccountID int64   `json:"from_account_id"`
+	ToAccountID   int64   `json:"to_account_id"`
+	Amount        float64 `json:"amount"`
+	Year          int     `json:"year"`
+	Month         int     `json:"month"`
+	Note          string  `json:"note"`
+}
+
+func validateAccount(input *AccountInput) error {
+	input.Name = strings.TrimSpace(input.Name)
+	if input.Name == "" {
+		return invalid("name is required")
+	}
+	if len(input.Name) > 100 {
+		return invalid("name must be at most 100 characters")
+	}
+	if !input.Type.IsValid() {
+		return invalid("unknown account type %q", input.Type)
+	}
+	return nil
+}
+
+// validateAccountRef checks that an optional account belongs to the household
+func validateAccountRef(ctx context.Context, accountID *int64) error {
+	if accountID == nil {
+		return nil
+	}
+	if _, err := db.GetAccount(ctx, householdID(ctx), *accountID); err != nil {
+		if errors.Is(notFound(err), ErrNotFound) {
+			return invalid("account %d not found", *accountID)
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *Service) ListAccounts(ctx context.Context) ([]models.Account, error) {
+	return db.GetAccounts(ctx, householdID(ctx))
+}
+
+func (s *Service) GetAccount(ctx context.Context, id int64) (*models.Account, error) {
+	account, err := db.GetAccount(ctx, householdID(ctx), id)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return account, nil
+}
+
+func (s *Service) CreateAccount(ctx context.Context, input AccountInput) (*models.Account, error) {
+	hid, err := editableHousehold(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateAccount(&input); err != nil {
+		return nil, err
+	}
+
+	account, err := db.CreateAccount(ctx, hid, models.Account{
+		Name:           input.Name,
+		Type:           input.Type,
+		OpeningBalance: input.OpeningBalance,
+	})
+	if isUniqueViolation(err) {
+		return nil, invalid("an account called %s already exists", input.Name)
+	}
+	return account, err
+}
+
+func (s *Service) UpdateAccount(ctx context.Context, id int64, input AccountInput) (*models.Account, error) {
+	hid, err := editableHousehold(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateAccount(&input); err != nil {
+		return nil, err
+	}
+
+	updated, err := db.UpdateAccount(ctx, hid, models.Account{
+		ID:             id,
+		Name:           input.Name,
+		Type:           input.Type,
+		OpeningBalance: input.OpeningBalance,
+	})
+	if isUniqueViolation(err) {
+		return nil, invalid("an account called %s already exists", input.Name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, ErrNotFound
+	}
+	return s.GetAccount(ctx, id)
+}
+
+// DeleteAccount removes an account that no expense, income or transfer uses
+func (s *Service) DeleteAccount(ctx context.Context, id int64) error {
+	hid, err := editableHousehold(ctx)
+	if err != nil {
+		return err
+	}
+	deleted, err := db.DeleteAccount(ctx, hid, id)
+	if isForeignKeyViolation(err) {
+		return invalid("this account still has expenses, income or transfers")
+	}
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// AccountBalances returns each account's running balance over a period
+func (s *Service) AccountBalances(ctx context.Context, period models.Period) ([]models.AccountBalance, error) {
+	if !period.IsValid() {
+		return nil, invalid("invalid period %d-%d", period.Year, period.Month)
+	}
+	return db.GetAccountBalances(ctx, householdID(ctx), period.Year, period.Month)
+}
+
+func (s *Service) GetIncomeAccount(ctx context.Context, period models.Period) (*int64, error) {
+	if !period.IsValid() {
+		return nil, invalid("invalid period %d-%d", period.Year, period.Month)
+	}
+	return db.GetIncomeAccountID(ctx, householdID(ctx), period.Year, period.Month)
+}
+
+// SetIncomeAccount records which account a period's income was paid into
+func (s *Service) SetIncomeAccount(ctx context.Context, period models.Period, accountID *int64) error {
+	hid, err := editableHousehold(ctx)
+	if err != nil {
+		return err
+	}
+	if !period.IsValid() {
+		return invalid("invalid period %d-%d", period.Year, period.Month)
+	}
+	if err := validateAccountRef(ctx, accountID); err != nil {
+		return err
+	}
+	return db.SetIncomeAccount(ctx, hid, period.Year, period.Month, accountID)
+}
+
+func (s *Service) ListTransfers(ctx context.Context) ([]models.Transfer, error) {
+	return db.GetTransfers(ctx, householdID(ctx))
+}
+
+// CreateTransfer moves money between accounts. Transfers change account
+// balances but are not spending, so they never appear in the summary.
+func (s *Service) CreateTransfer(ctx context.Context, input TransferInput) (*models.Transfer, error) {
+	hid, err := editableHousehold(ctx)
+	if err != nil {
+		return nil, err
+	}
+	period := models.Period{Year: input.Year, Month: input.Month}
+	if !period.IsValid() {
+		return nil, invalid("invalid period %d-%d", period.Year, period.Month)
+	}
+	if input.FromAccountID == input.ToAccountID {
+		return nil, invalid("a transfer must be between two different accounts")
+	}
+	if toCents(input.Amount) <= 0 {
+		return nil, invalid("amount must be positive")
+	}
+	input.Note = strings.TrimSpace(input.Note)
+	if len(input.Note) > 255 {
+		return nil, invalid("note must be at most 255 characters")
+	}
+	if err := validateAccountRef(ctx, &input.FromAccountID); err != nil {
+		return nil, err
+	}
+	if err := validateAccountRef(ctx, &input.ToAccountID); err != nil {
+		return nil, err
+	}
+
+	id, err := db.CreateTransfer(ctx, hid, input.FromAccountID, input.ToAccountID, input.Amount, input.Year, input.Month, input.Note)
+	if err != nil {
+		return nil, err
+	}
+	return db.GetTransfer(ctx, hid, id)
+}
+
+func (s *Service) DeleteTransfer(ctx context.Context, id int64) error {
+	hid, err := editableHousehold(ctx)
+	if err != nil {
+		return err
+	}
+	deleted, err := db.DeleteTransfer(ctx, hid, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrNotFound
+	}
+	return nil
+}
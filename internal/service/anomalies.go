@@ -0,0 +1,31 @@
+package service
+
+import (
+	"context"
+
+	"spending-tracker/db"
+	"spending-tracker/internal/analytics"
+	"spending-tracker/models"
+)
+
+// Anomalies finds what is unusual about a period's spending compared with
+// the periods before it
+func (s *Service) Anomalies(ctx context.Context, period models.Period) ([]models.Anomaly, error) {
+	if !period.IsValid() {
+		return nil, invalid("invalid period %d-%d", period.Year, period.Month)
+	}
+	hid := householdID(ctx)
+	expenses, err := db.GetExpensesByPeriod(ctx, hid, period.Year, period.Month)
+	if err != nil {
+		return nil, err
+	}
+	return detectAnomalies(ctx, hid, period, expenses)
+}
+
+func detectAnomalies(ctx context.Context, hid int64, period models.Period, expenses []models.Expense) ([]models.Anomaly, error) {
+	history, err := db.GetExpenseHistory(ctx, hid, period.AddMonths(-analytics.TrailingPeriods), period.Prev())
+	if err != nil {
+		return nil, err
+	}
+	return analytics.Detect(expenses, history), nil
+}
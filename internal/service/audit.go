@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"spending-tracker/db"
+	"spending-tracker/internal/auth"
+	"spending-tracker/models"
+)
+
+// ActivityPageSize is the number of audit entries per activity feed page
+const ActivityPageSize = 50
+
+// snapshot encodes a record for the audit log, with nil meaning no record
+func snapshot(v any) ([]byte, error) {
+	if v == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil || string(b) == "null" {
+		return nil, err
+	}
+	return b, nil
+}
+
+// record writes an audit entry for a change to the active household.
+// entityID of zero is stored as no ID, as for income which is keyed by period.
+func (s *Service) record(ctx context.Context, entity models.AuditEntity, entityID int64, action models.AuditAction, before, after any) (*models.AuditEntry, error) {
+	beforeJSON, err := snapshot(before)
+	if err != nil {
+		return nil, err
+	}
+	afterJSON, err := snapshot(after)
+	if err != nil {
+		return nil, err
+	}
+
+	var userID *int64
+	if user := auth.UserFromContext(ctx); user != nil {
+		userID = &user.ID
+	}
+	var id *int64
+	if entityID != 0 {
+		id = &entityID
+	}
+	entry, err := db.CreateAuditEntry(ctx, householdID(ctx), userID, entity, id, action, beforeJSON, afterJSON)
+	if err != nil {
+		return nil, err
+	}
+	s.publish(ctx, entity, action, before, after)
+	return entry, nil
+}
+
+// Activity returns a page of the household's changes, newest first
+func (s *Service) Activity(ctx context.Context, page int) ([]models.AuditEntry, error) {
+	if page < 1 {
+		page = 1
+	}
+	return db.GetAuditEntries(ctx, householdID(ctx), ActivityPageSize, (page-1)*ActivityPageSize)
+}
+
+func (s *Service) GetAuditEntry(ctx context.Context, id int64) (*models.AuditEntry, error) {
+	entry, err := db.GetAuditEntry(ctx, householdID(ctx), id)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return entry, nil
+}
+
+// Undo reverses a delete, restoring the record with its original ID and
+// relationships, and returns the entry recording the restore
+func (s *Service) Undo(ctx context.Context, id int64) (*models.AuditEntry, error) {
+	hid, err := editableHousehold(ctx)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := s.GetAuditEntry(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !entry.CanUndo() {
+		return nil, invalid("this change cannot be undone")
+	}
+
+	var restored *models.AuditEntry
+	switch entry.EntityType {
+	case models.AuditEntityExpense:
+		restored, err = s.restoreExpense(ctx, hid, entry.Before)
+	case models.AuditEntityCategory:
+		restored, err = s.restoreCategory(ctx, hid, entry.Before)
+	case models.AuditEntityRecurring:
+		restored, err = s.restoreRecurring(ctx, hid, entry.Before)
+	default:
+		return nil, invalid("this change cannot be undone")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := db.MarkAuditEntryUndone(ctx, hid, entry.ID); err != nil {
+		return nil, err
+	}
+	return restored, nil
+}
+
+func (s *Service) restoreExpense(ctx context.Context, hid int64, before json.RawMessage) (*models.AuditEntry, error) {
+	var e models.Expense
+	if err := json.Unmarshal(before, &e); err != nil {
+		return nil, err
+	}
+
+	// The expense and its tags, split and shares come back together or not at all
+	err := db.InTx(ctx, func(ctx context.Context) error {
+		if err := db.RestoreExpense(ctx, hid, e); err != nil {
+			return err
+		}
+		if len(e.Tags) > 0 {
+			names := make([]string, len(e.Tags))
+			for i, t := range e.Tags {
+				names[i] = t.Name
+			}
+			if err := db.SetExpenseTags(ctx, hid, e.ID, names); err != nil {
+				return err
+			}
+		}
+		if e.IsSplit() {
+			if err := db.SetExpenseSplits(ctx, hid, e.ID, e.Splits); err != nil {
+				return err
+			}
+		}
+		if e.IsShared() {
+			return db.SetExpenseSharing(ctx, hid, e.ID, e.PaidBy, e.ShareMethod, e.Shares)
+		}
+		return nil
+	})
+	if isUniqueViolation(err) {
+		return nil, invalid("this expense has already been restored")
+	}
+	if isForeignKeyViolation(err) {
+		return nil, invalid("someone this expense was shared with has since been removed")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	restored, err := s.GetExpense(ctx, e.ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.record(ctx, models.AuditEntityExpense, e.ID, models.AuditActionRestore, nil, restored)
+}
+
+func (s *Service) restoreCategory(ctx context.Context, hid int64, before json.RawMessage) (*models.AuditEntry, error) {
+	var c models.Category
+	if err := json.Unmarshal(before, &c); err != nil {
+		return nil, err
+	}
+
+	err := db.RestoreCategory(ctx, hid, c)
+	if isUniqueViolation(err) {
+		return nil, invalid("a category named %q already exists", c.Name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	restored, err := s.GetCategory(ctx, c.ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.record(ctx, models.AuditEntityCategory, c.ID, models.AuditActionRestore, nil, restored)
+}
+
+// restoreRecurring reactivates a template; deactivation never removes the row
+func (s *Service) restoreRecurring(ctx context.Context, hid int64, before json.RawMessage) (*models.AuditEntry, error) {
+	var r models.RecurringExpense
+	if err := json.Unmarshal(before, &r); err != nil {
+		return nil, err
+	}
+
+	current, err := s.GetRecurring(ctx, r.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.UpdateRecurringExpense(ctx, hid, r.ID, current.Description, current.Amount, current.CategoryID, true); err != nil {
+		return nil, err
+	}
+
+	restored, err := s.GetRecurring(ctx, r.ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.record(ctx, models.AuditEntityRecurring, r.ID, models.AuditActionRestore, current, restored)
+}
+
+// isUniqueViolation reports whether err is a Postgres unique constraint violation
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
+
+// isForeignKeyViolation reports whether err is a Postgres foreign key violation,
+// e.g. deleting a row that others still reference
+func isForeignKeyViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23503"
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"spending-tracker/models"
+)
+
+func TestUndoDeleteRestoresNothingWhenSharingFails(t *testing.T) {
+	s, ctx := newTestService(t)
+	period := models.CurrentPeriod()
+
+	alex, err := s.CreatePerson(ctx, "Alex", nil)
+	if err != nil {
+		t.Fatalf("creating person: %v", err)
+	}
+	sam, err := s.CreatePerson(ctx, "Sam", nil)
+	if err != nil {
+		t.Fatalf("creating person: %v", err)
+	}
+	expense, err := s.CreateExpense(ctx, period, ExpenseInput{
+		Description: "Dinner",
+		Amount:      30,
+		Type:        models.ExpenseTypeOneTime,
+		Tags:        []string{"birthday"},
+	})
+	if err != nil {
+		t.Fatalf("creating expense: %v", err)
+	}
+	if _, err := s.SetExpenseSharing(ctx, expense.ID, SharingInput{
+		PaidBy:       &alex.ID,
+		Method:       models.ShareEqual,
+		Participants: []ShareInput{{PersonID: alex.ID}, {PersonID: sam.ID}},
+	}); err != nil {
+		t.Fatalf("sharing expense: %v", err)
+	}
+
+	entry, err := s.DeleteExpense(ctx, expense.ID)
+	if err != nil {
+		t.Fatalf("deleting expense: %v", err)
+	}
+	if err := s.DeletePerson(ctx, sam.ID); err != nil {
+		t.Fatalf("deleting person: %v", err)
+	}
+
+	_, err = s.Undo(ctx, entry.ID)
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("undo error = %v, want a validation error", err)
+	}
+	if _, err := s.GetExpense(ctx, expense.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expense after a failed undo: err = %v, want not found", err)
+	}
+}
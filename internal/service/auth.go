@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"spending-tracker/db"
+	"spending-tracker/internal/auth"
+	"spending-tracker/models"
+)
+
+const (
+	SessionLifetime   = 30 * 24 * time.Hour
+	minPasswordLength = 8
+)
+
+var (
+	// ErrInvalidCredentials is returned for an unknown username or wrong password
+	ErrInvalidCredentials = errors.New("invalid username or password")
+	// ErrSetupComplete is returned when the initial admin already exists
+	ErrSetupComplete = errors.New("setup has already been completed")
+)
+
+func validateCredentials(username, password string) (string, error) {
+	username = strings.ToLower(strings.TrimSpace(username))
+	if username == "" {
+		return "", invalid("username is required")
+	}
+	if len(password) < minPasswordLength {
+		return "", invalid("password must be at least %d characters", minPasswordLength)
+	}
+	return username, nil
+}
+
+// NeedsSetup reports whether no accounts exist yet
+func (s *Service) NeedsSetup(ctx context.Context) (bool, error) {
+	count, err := db.CountUsers(ctx)
+	return count == 0, err
+}
+
+// Setup creates the initial admin account on first run
+func (s *Service) Setup(ctx context.Context, username, password string) (*models.User, error) {
+	username, err := validateCredentials(username, password)
+	if err != nil {
+		return nil, err
+	}
+
+	hash, err := auth.HashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+
+	user, created, err := db.CreateFirstUser(ctx, username, hash)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, ErrSetupComplete
+	}
+	return user, nil
+}
+
+func (s *Service) ListUsers(ctx context.Context) ([]models.User, error) {
+	return db.GetAllUsers(ctx)
+}
+
+// CreateUser adds a local account
+func (s *Service) CreateUser(ctx context.Context, username, password string, isAdmin bool) (*models.User, error) {
+	username, err := validateCredentials(username, password)
+	if err != nil {
+		return nil, err
+	}
+
+	hash, err := auth.HashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := db.CreateUser(ctx, username, hash, isAdmin)
+	if isUniqueViolation(err) {
+		return nil, invalid("username %q is already taken", username)
+	}
+	return user, err
+}
+
+// Authenticate checks a username and password
+func (s *Service) Authenticate(ctx context.Context, username, password string) (*models.User, error) {
+	user, err := db.GetUserByUsername(ctx, strings.ToLower(strings.TrimSpace(username)))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !auth.CheckPassword(user.PasswordHash, password) {
+		return nil, ErrInvalidCredentials
+	}
+	return user, nil
+}
+
+// StartSession creates a server-side session and returns its cookie token
+func (s *Service) StartSession(ctx context.Context, userID int64) (string, time.Time, error) {
+	token, err := auth.NewToken()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	expiresAt := time.Now().Add(SessionLifetime)
+	if err := db.CreateSession(ctx, auth.HashToken(token), userID, expiresAt); err != nil {
+		return "", time.Time{}, err
+	}
+
+	// Opportunistically clear out old sessions
+	db.DeleteExpiredSessions(ctx)
+
+	return token, expiresAt, nil
+}
+
+// SessionUser returns the user for a session token
+func (s *Service) SessionUser(ctx context.Context, token string) (*models.User, error) {
+	user, err := db.GetSessionUser(ctx, auth.HashToken(token))
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return user, nil
+}
+
+func (s *Service) EndSession(ctx context.Context, token string) error {
+	return db.DeleteSession(ctx, auth.HashToken(token))
+}
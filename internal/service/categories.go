@@ -0,0 +1,305 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+
+	"spending-tracker/db"
+	"spending-tracker/models"
+)
+
+// CategoryInput holds the fields used to create a category
+type CategoryInput struct {
+	Name     string   `json:"name"`
+	Color    string   `json:"color"`
+	ParentID *int64   `json:"parent_id"`
+	Budget   *float64 `json:"budget"`
+}
+
+func (s *Service) ListCategories(ctx context.Context) ([]models.Category, error) {
+	return db.GetAllCategories(ctx, householdID(ctx))
+}
+
+func (s *Service) GetCategory(ctx context.Context, id int64) (*models.Category, error) {
+	cat, err := db.GetCategoryByID(ctx, householdID(ctx), id)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return cat, nil
+}
+
+func (s *Service) CategoryUsage(ctx context.Context, id int64) (models.CategoryUsage, error) {
+	if _, err := s.GetCategory(ctx, id); err != nil {
+		return models.CategoryUsage{}, err
+	}
+	return db.GetCategoryUsage(ctx, householdID(ctx), id)
+}
+
+func validateCategoryDetails(name, color string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", invalid("name is required")
+	}
+	if !slices.Contains(models.CategoryColors, color) {
+		return "", invalid("unknown color %q", color)
+	}
+	return name, nil
+}
+
+func validateBudget(budget *float64) (*float64, error) {
+	if budget == nil || *budget == 0 {
+		return nil, nil
+	}
+	if *budget < 0 {
+		return nil, invalid("budget must not be negative")
+	}
+	return budget, nil
+}
+
+// validateParent checks that parentID can hold id as a subcategory.
+// Only one level of nesting is allowed.
+func (s *Service) validateParent(ctx context.Context, id int64, parentID *int64) error {
+	if parentID == nil {
+		return nil
+	}
+	if *parentID == id {
+		return invalid("a category cannot be its own parent")
+	}
+
+	parent, err := db.GetCategoryByID(ctx, householdID(ctx), *parentID)
+	if err != nil {
+		if errors.Is(notFound(err), ErrNotFound) {
+			return invalid("parent category %d not found", *parentID)
+		}
+		return err
+	}
+	if !parent.IsTopLevel() {
+		return invalid("subcategories cannot have their own subcategories")
+	}
+
+	if id == 0 {
+		return nil
+	}
+	hasChildren, err := db.HasSubcategories(ctx, householdID(ctx), id)
+	if err != nil {
+		return err
+	}
+	if hasChildren {
+		return invalid("categories with subcategories must stay at the top level")
+	}
+	return nil
+}
+
+func (s *Service) CreateCategory(ctx context.Context, input CategoryInput) (*models.Category, error) {
+	hid, err := editableHousehold(ctx)
+	if err != nil {
+		return nil, err
+	}
+	name, err := validateCategoryDetails(input.Name, input.Color)
+	if err != nil {
+		return nil, err
+	}
+	budget, err := validateBudget(input.Budget)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validateParent(ctx, 0, input.ParentID); err != nil {
+		return nil, err
+	}
+
+	cat, err := db.CreateCategory(ctx, hid, name, input.Color, input.ParentID, budget)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.record(ctx, models.AuditEntityCategory, cat.ID, models.AuditActionCreate, nil, cat); err != nil {
+		return nil, err
+	}
+	return cat, nil
+}
+
+// changeCategory applies change to an existing category and records the
+// result as a single audit entry
+func (s *Service) changeCategory(ctx context.Context, id int64, change func(hid int64) error) (*models.Category, error) {
+	hid, err := editableHousehold(ctx)
+	if err != nil {
+		return nil, err
+	}
+	before, err := s.GetCategory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := change(hid); err != nil {
+		return nil, err
+	}
+
+	after, err := s.GetCategory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.record(ctx, models.AuditEntityCategory, id, models.AuditActionUpdate, before, after); err != nil {
+		return nil, err
+	}
+	return after, nil
+}
+
+func (s *Service) renameCategory(ctx context.Context, hid, id int64, name, color string) error {
+	name, err := validateCategoryDetails(name, color)
+	if err != nil {
+		return err
+	}
+	return db.UpdateCategory(ctx, hid, id, name, color)
+}
+
+func (s *Service) moveCategory(ctx context.Context, hid, id int64, parentID *int64) error {
+	if err := s.validateParent(ctx, id, parentID); err != nil {
+		return err
+	}
+	return db.UpdateCategoryParent(ctx, hid, id, parentID)
+}
+
+func (s *Service) budgetCategory(ctx context.Context, hid, id int64, budget *float64) error {
+	budget, err := validateBudget(budget)
+	if err != nil {
+		return err
+	}
+	return db.UpdateCategoryBudget(ctx, hid, id, budget)
+}
+
+// RenameCategory updates a category's name and color
+func (s *Service) RenameCategory(ctx context.Context, id int64, name, color string) (*models.Category, error) {
+	return s.changeCategory(ctx, id, func(hid int64) error {
+		return s.renameCategory(ctx, hid, id, name, color)
+	})
+}
+
+// SetCategoryParent moves a category under a new parent, or to the top level when nil
+func (s *Service) SetCategoryParent(ctx context.Context, id int64, parentID *int64) (*models.Category, error) {
+	return s.changeCategory(ctx, id, func(hid int64) error {
+		return s.moveCategory(ctx, hid, id, parentID)
+	})
+}
+
+// SetCategoryBudget sets the monthly budget, clearing it when nil or zero
+func (s *Service) SetCategoryBudget(ctx context.Context, id int64, budget *float64) (*models.Category, error) {
+	return s.changeCategory(ctx, id, func(hid int64) error {
+		return s.budgetCategory(ctx, hid, id, budget)
+	})
+}
+
+// UpdateCategory replaces every editable field of a category
+func (s *Service) UpdateCategory(ctx context.Context, id int64, input CategoryInput) (*models.Category, error) {
+	return s.changeCategory(ctx, id, func(hid int64) error {
+		if err := s.renameCategory(ctx, hid, id, input.Name, input.Color); err != nil {
+			return err
+		}
+		if err := s.moveCategory(ctx, hid, id, input.ParentID); err != nil {
+			return err
+		}
+		return s.budgetCategory(ctx, hid, id, input.Budget)
+	})
+}
+
+func (s *Service) ArchiveCategory(ctx context.Context, id int64) error {
+	_, err := s.changeCategory(ctx, id, func(hid int64) error {
+		return db.ArchiveCategory(ctx, hid, id)
+	})
+	return err
+}
+
+func (s *Service) UnarchiveCategory(ctx context.Context, id int64) error {
+	_, err := s.changeCategory(ctx, id, func(hid int64) error {
+		return db.UnarchiveCategory(ctx, hid, id)
+	})
+	return err
+}
+
+// DeleteCategory deletes a category. Categories still in use must name a
+// replacement, which receives their expenses, templates and subcategories.
+// Plain deletes return the audit entry that can undo them; merges cannot be undone.
+func (s *Service) DeleteCategory(ctx context.Context, id int64, replacementID *int64) (*models.AuditEntry, error) {
+	if replacementID != nil {
+		return nil, s.MergeCategories(ctx, id, *replacementID)
+	}
+	hid, err := editableHousehold(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	usage, err := s.CategoryUsage(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if usage.Total() > 0 {
+		return nil, invalid("category is in use by %d expenses and %d recurring templates; choose a replacement category",
+			usage.Expenses, usage.RecurringTemplates)
+	}
+
+	// Snapshot the subcategories so undo can move them back under the category
+	categories, err := s.ListCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var before *models.Category
+	for _, c := range models.BuildCategoryTree(categories) {
+		if c.ID == id {
+			before = &c
+		}
+		for _, child := range c.Children {
+			if child.ID == id {
+				before = &child
+			}
+		}
+	}
+
+	if err := db.DeleteCategory(ctx, hid, id); err != nil {
+		return nil, err
+	}
+	return s.record(ctx, models.AuditEntityCategory, id, models.AuditActionDelete, before, nil)
+}
+
+// categoryMerge records where a merged category's history went
+type categoryMerge struct {
+	MergedInto int64 `json:"merged_into"`
+}
+
+// MergeCategories moves everything from sourceID into targetID and deletes the source
+func (s *Service) MergeCategories(ctx context.Context, sourceID, targetID int64) error {
+	hid, err := editableHousehold(ctx)
+	if err != nil {
+		return err
+	}
+	if sourceID == targetID {
+		return invalid("a category cannot be merged into itself")
+	}
+	source, err := s.GetCategory(ctx, sourceID)
+	if err != nil {
+		return err
+	}
+
+	target, err := db.GetCategoryByID(ctx, hid, targetID)
+	if err != nil {
+		if errors.Is(notFound(err), ErrNotFound) {
+			return invalid("replacement category %d not found", targetID)
+		}
+		return err
+	}
+	if target.IsArchived() {
+		return invalid("cannot merge into an archived category")
+	}
+
+	// Subcategories that would end up beside one with the same name trip
+	// the unique index, rolling the whole merge back
+	err = db.MergeCategories(ctx, hid, sourceID, targetID)
+	if isUniqueViolation(err) {
+		return invalid("merging %q into %q would leave two categories with the same name in one place; rename one first",
+			source.Name, target.Name)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = s.record(ctx, models.AuditEntityCategory, sourceID, models.AuditActionDelete, source, categoryMerge{MergedInto: targetID})
+	return err
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"spending-tracker/models"
+)
+
+func TestMergeCategoriesNameCollisions(t *testing.T) {
+	tests := []struct {
+		name string
+		// setup creates the categories and returns the source and target
+		setup func(create func(name string, parent *models.Category) *models.Category) (source, target *models.Category)
+	}{
+		{
+			name: "subcategories with the same name under source and target",
+			setup: func(create func(string, *models.Category) *models.Category) (*models.Category, *models.Category) {
+				food, groceries := create("Food", nil), create("Groceries", nil)
+				create("Other", food)
+				create("Other", groceries)
+				return food, groceries
+			},
+		},
+		{
+			name: "parent's subcategory moving beside a top-level sibling of the same name",
+			setup: func(create func(string, *models.Category) *models.Category) (*models.Category, *models.Category) {
+				food, home := create("Food", nil), create("Home", nil)
+				create("Snacks", food)
+				create("Snacks", nil)
+				return food, create("Kitchen", home)
+			},
+		},
+		{
+			name: "subcategory taking its parent's place beside a sibling of the same name",
+			setup: func(create func(string, *models.Category) *models.Category) (*models.Category, *models.Category) {
+				food := create("Food", nil)
+				create("Takeaway", nil)
+				return food, create("Takeaway", food)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, ctx := newTestService(t)
+			create := func(name string, parent *models.Category) *models.Category {
+				input := CategoryInput{Name: name, Color: "blue"}
+				if parent != nil {
+					input.ParentID = &parent.ID
+				}
+				c, err := s.CreateCategory(ctx, input)
+				if err != nil {
+					t.Fatalf("creating %s: %v", name, err)
+				}
+				return c
+			}
+			source, target := tt.setup(create)
+
+			err := s.MergeCategories(ctx, source.ID, target.ID)
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("merge error = %v, want a validation error", err)
+			}
+			if _, err := s.GetCategory(ctx, source.ID); err != nil {
+				t.Errorf("source category after a failed merge: %v", err)
+			}
+		})
+	}
+}
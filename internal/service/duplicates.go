@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"spending-tracker/db"
+	"spending-tracker/models"
+)
+
+// DuplicatesOf returns existing expenses that look like the one about to be
+// added to a period: the same amount and a similar description, in the same
+// period or added within models.DuplicateWindow
+func (s *Service) DuplicatesOf(ctx context.Context, period models.Period, input ExpenseInput) ([]models.Expense, error) {
+	if !period.IsValid() {
+		return nil, invalid("invalid period %d-%d", period.Year, period.Month)
+	}
+	candidate := models.Expense{Description: strings.TrimSpace(input.Description), Amount: input.Amount}
+	if candidate.Description == "" || toCents(candidate.Amount) == 0 {
+		return nil, nil
+	}
+
+	since := time.Now().Add(-models.DuplicateWindow)
+	existing, err := db.GetDuplicateCandidates(ctx, householdID(ctx), period.Year, period.Month, candidate.Amount, since)
+	if err != nil {
+		return nil, err
+	}
+
+	var duplicates []models.Expense
+	for _, e := range existing {
+		if candidate.IsDuplicateOf(e) {
+			duplicates = append(duplicates, e)
+		}
+	}
+	return duplicates, nil
+}
+
+// FindDuplicates reports the expenses in a period that look like duplicates
+// of an earlier one
+func (s *Service) FindDuplicates(ctx context.Context, period models.Period) ([]models.DuplicateGroup, error) {
+	if !period.IsValid() {
+		return nil, invalid("invalid period %d-%d", period.Year, period.Month)
+	}
+	expenses, err := db.GetExpensesByPeriod(ctx, householdID(ctx), period.Year, period.Month)
+	if err != nil {
+		return nil, err
+	}
+	return models.FindDuplicates(expenses), nil
+}
+
+// MergeDuplicates keeps one expense and deletes duplicates of it, moving
+// their tags onto the one kept. Each delete can be undone from the activity feed.
+func (s *Service) MergeDuplicates(ctx context.Context, keepID int64, duplicateIDs []int64) (*models.Expense, error) {
+	hid, err := editableHousehold(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(duplicateIDs) == 0 {
+		return nil, invalid("choose the duplicates to merge")
+	}
+	keep, err := s.GetExpense(ctx, keepID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check every duplicate first so a merge is not left half done
+	tags := models.ParseTags(keep.TagNames())
+	for _, id := range duplicateIDs {
+		duplicate, err := s.GetExpense(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if !duplicate.IsDuplicateOf(*keep) {
+			return nil, invalid("%q is not a duplicate of %q", duplicate.Description, keep.Description)
+		}
+		if err := checkDeletable(*duplicate); err != nil {
+			return nil, err
+		}
+		tags = models.ParseTags(strings.Join(tags, ",") + "," + duplicate.TagNames())
+	}
+
+	if len(tags) > len(keep.Tags) {
+		if err := db.SetExpenseTags(ctx, hid, keepID, tags); err != nil {
+			return nil, err
+		}
+		merged, err := s.GetExpense(ctx, keepID)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := s.record(ctx, models.AuditEntityExpense, keepID, models.AuditActionUpdate, keep, merged); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, id := range duplicateIDs {
+		if _, err := s.DeleteExpense(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+	return s.GetExpense(ctx, keepID)
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+
+	"spending-tracker/internal/events"
+	"spending-tracker/models"
+)
+
+// publish tells open pages about a recorded change so they can refresh.
+// It takes the same snapshots as record, which calls it for every change.
+func (s *Service) publish(ctx context.Context, entity models.AuditEntity, action models.AuditAction, before, after any) {
+	event := events.Event{
+		HouseholdID: householdID(ctx),
+		Origin:      events.OriginFromContext(ctx),
+	}
+
+	switch entity {
+	case models.AuditEntityExpense:
+		expense, ok := after.(*models.Expense)
+		if !ok {
+			expense, ok = before.(*models.Expense)
+		}
+		if !ok {
+			return
+		}
+		event.Period = models.Period{Year: expense.Year, Month: expense.Month}
+		switch action {
+		case models.AuditActionCreate, models.AuditActionRestore:
+			event.Kind = events.ExpenseCreated
+		case models.AuditActionDelete:
+			event.Kind = events.ExpenseDeleted
+		default:
+			event.Kind = events.ExpenseUpdated
+		}
+	case models.AuditEntityIncome:
+		income, ok := after.(incomeSnapshot)
+		if !ok {
+			return
+		}
+		event.Period = models.Period{Year: income.Year, Month: income.Month}
+		event.Kind = events.IncomeChanged
+	case models.AuditEntityCategory:
+		// Category names, colors and budgets show in every period
+		event.Kind = events.CategoryChanged
+	default:
+		// Recurring templates only reach a page through the expenses they create
+		return
+	}
+
+	s.events.Publish(event)
+}
@@ -0,0 +1,350 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+
+	"spending-tracker/db"
+	"spending-tracker/models"
+)
+
+// ExpenseInput holds the editable fields of an expense
+type ExpenseInput struct {
+	Description string             `json:"description"`
+	Amount      float64            `json:"amount"`
+	CategoryID  *int64             `json:"category_id"`
+	AccountID   *int64             `json:"account_id"`
+	Type        models.ExpenseType `json:"expense_type"`
+	// Tags replaces the expense's tags; nil leaves them unchanged on update
+	Tags []string `json:"tags"`
+	// Splits divides the expense across categories and must sum to Amount.
+	// Nil leaves an existing split unchanged on update; empty removes it.
+	Splits []SplitInput `json:"splits"`
+	// ReimbursableFrom names who owes the expense back, e.g. an employer.
+	// Nil leaves it unchanged on update; empty marks it not reimbursable.
+	ReimbursableFrom *string `json:"reimbursable_from"`
+	// Version is the version an update was based on. A stale version fails
+	// with a ConflictError; zero overwrites unconditionally.
+	Version int `json:"version"`
+}
+
+// SplitInput is one line of a split expense
+type SplitInput struct {
+	CategoryID *int64  `json:"category_id"`
+	Amount     float64 `json:"amount"`
+	Note       string  `json:"note"`
+}
+
+func (s *Service) validateExpense(ctx context.Context, input *ExpenseInput) error {
+	input.Description = strings.TrimSpace(input.Description)
+	if input.Description == "" {
+		return invalid("description is required")
+	}
+
+	switch input.Type {
+	case "":
+		input.Type = models.ExpenseTypeOneTime
+	case models.ExpenseTypeOneTime, models.ExpenseTypeRecurring:
+	default:
+		return invalid("unknown expense type %q", input.Type)
+	}
+
+	// Negative amounts are one-off adjustments, such as money coming back
+	if input.Amount < 0 {
+		if input.Type == models.ExpenseTypeRecurring {
+			return invalid("a recurring expense's amount must not be negative")
+		}
+		if len(input.Splits) > 0 {
+			return invalid("an expense with a negative amount cannot be split")
+		}
+	}
+
+	if input.ReimbursableFrom != nil {
+		from := strings.TrimSpace(*input.ReimbursableFrom)
+		input.ReimbursableFrom = &from
+		if err := validateReimbursableFrom(from, input.Amount); err != nil {
+			return err
+		}
+	}
+
+	if err := validateCategoryRef(ctx, input.CategoryID); err != nil {
+		return err
+	}
+	if err := validateAccountRef(ctx, input.AccountID); err != nil {
+		return err
+	}
+	if err := validateSplits(ctx, input); err != nil {
+		return err
+	}
+
+	if input.Tags != nil {
+		input.Tags = models.ParseTags(strings.Join(input.Tags, ","))
+	}
+	return nil
+}
+
+// validateCategoryRef checks that an optional category belongs to the household
+func validateCategoryRef(ctx context.Context, categoryID *int64) error {
+	if categoryID == nil {
+		return nil
+	}
+	if _, err := db.GetCategoryByID(ctx, householdID(ctx), *categoryID); err != nil {
+		if errors.Is(notFound(err), ErrNotFound) {
+			return invalid("category %d not found", *categoryID)
+		}
+		return err
+	}
+	return nil
+}
+
+func validateSplits(ctx context.Context, input *ExpenseInput) error {
+	if len(input.Splits) == 0 {
+		return nil
+	}
+	if len(input.Splits) < 2 {
+		return invalid("a split needs at least two lines")
+	}
+
+	var cents int64
+	for i := range input.Splits {
+		line := &input.Splits[i]
+		line.Note = strings.TrimSpace(line.Note)
+		if line.Amount <= 0 {
+			return invalid("split line %d must have a positive amount", i+1)
+		}
+		if len(line.Note) > 255 {
+			return invalid("split line %d note must be at most 255 characters", i+1)
+		}
+		if err := validateCategoryRef(ctx, line.CategoryID); err != nil {
+			return err
+		}
+		cents += toCents(line.Amount)
+	}
+
+	if cents != toCents(input.Amount) {
+		return invalid("split lines add up to £%.2f but the expense is £%.2f", float64(cents)/100, input.Amount)
+	}
+	return nil
+}
+
+// toCents rounds an amount to whole pence so sums compare exactly
+func toCents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
+
+func splitLines(input []SplitInput) []models.ExpenseSplit {
+	splits := make([]models.ExpenseSplit, len(input))
+	for i, line := range input {
+		splits[i] = models.ExpenseSplit{CategoryID: line.CategoryID, Amount: line.Amount, Note: line.Note}
+	}
+	return splits
+}
+
+func (s *Service) GetExpense(ctx context.Context, id int64) (*models.Expense, error) {
+	expense, err := db.GetExpenseByID(ctx, householdID(ctx), id)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return expense, nil
+}
+
+// CreateExpense adds an expense to a period. Recurring expenses also create
+// a template so they are copied into future periods.
+func (s *Service) CreateExpense(ctx context.Context, period models.Period, input ExpenseInput) (*models.Expense, error) {
+	hid, err := editableHousehold(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !period.IsValid() {
+		return nil, invalid("invalid period %d-%d", period.Year, period.Month)
+	}
+	if err := s.validateExpense(ctx, &input); err != nil {
+		return nil, err
+	}
+
+	// The description is matched to a payee, whose default category fills in
+	// for one left blank
+	payee, err := matchPayee(ctx, input.Description)
+	if err != nil {
+		return nil, err
+	}
+	var payeeID *int64
+	if payee != nil {
+		payeeID = &payee.ID
+		if input.CategoryID == nil && len(input.Splits) == 0 {
+			input.CategoryID = payee.DefaultCategoryID
+		}
+	}
+
+	newExpense := models.Expense{
+		Description: input.Description,
+		Amount:      input.Amount,
+		CategoryID:  input.CategoryID,
+		AccountID:   input.AccountID,
+		PayeeID:     payeeID,
+		Type:        input.Type,
+		Year:        period.Year,
+		Month:       period.Month,
+	}
+	if input.ReimbursableFrom != nil {
+		newExpense.ReimbursableFrom = *input.ReimbursableFrom
+	}
+
+	// A new recurring expense is this period's charge of the template it
+	// creates, so later periods and the forecast see it as charged
+	if input.Type == models.ExpenseTypeRecurring {
+		templateID, err := db.CreateRecurringExpense(ctx, hid, input.Description, input.Amount, input.CategoryID)
+		if err != nil {
+			return nil, err
+		}
+		template, err := s.GetRecurring(ctx, templateID)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := s.record(ctx, models.AuditEntityRecurring, templateID, models.AuditActionCreate, nil, template); err != nil {
+			return nil, err
+		}
+		newExpense.RecurringExpenseID = &templateID
+	}
+
+	created, err := db.CreateExpense(ctx, hid, newExpense)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Tags != nil {
+		if err := db.SetExpenseTags(ctx, hid, created.ID, input.Tags); err != nil {
+			return nil, err
+		}
+	}
+	if len(input.Splits) > 0 {
+		if err := db.SetExpenseSplits(ctx, hid, created.ID, splitLines(input.Splits)); err != nil {
+			return nil, err
+		}
+	}
+
+	expense, err := s.GetExpense(ctx, created.ID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.record(ctx, models.AuditEntityExpense, expense.ID, models.AuditActionCreate, nil, expense); err != nil {
+		return nil, err
+	}
+	return expense, nil
+}
+
+func (s *Service) UpdateExpense(ctx context.Context, id int64, input ExpenseInput) (*models.Expense, error) {
+	hid, err := editableHousehold(ctx)
+	if err != nil {
+		return nil, err
+	}
+	before, err := s.GetExpense(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkUnlocked(*before); err != nil {
+		return nil, err
+	}
+	if err := s.validateExpense(ctx, &input); err != nil {
+		return nil, err
+	}
+	if input.Splits == nil && before.IsSplit() && toCents(input.Amount) != toCents(before.Amount) {
+		return nil, invalid("this expense is split; change its split lines along with the amount")
+	}
+	if before.ShareMethod == models.ShareExact && toCents(input.Amount) != toCents(before.Amount) {
+		return nil, invalid("this expense is shared by exact amounts; change the shares along with the amount")
+	}
+	if before.IsShared() && toCents(input.Amount) <= 0 {
+		return nil, invalid("a shared expense's amount must be positive; stop sharing it first")
+	}
+	if err := s.checkRefundedAmount(ctx, *before, input); err != nil {
+		return nil, err
+	}
+
+	payee, err := matchPayee(ctx, input.Description)
+	if err != nil {
+		return nil, err
+	}
+	var payeeID *int64
+	if payee != nil {
+		payeeID = &payee.ID
+	}
+
+	// The update and its tags, split, reimbursement and shares are saved
+	// together or not at all
+	var updated bool
+	err = db.InTx(ctx, func(ctx context.Context) error {
+		var err error
+		updated, err = db.UpdateExpense(ctx, hid, id, input.Version, input.Description, input.Amount, input.CategoryID, input.AccountID, payeeID, input.Type)
+		if err != nil || !updated {
+			return err
+		}
+		if input.Tags != nil {
+			if err := db.SetExpenseTags(ctx, hid, id, input.Tags); err != nil {
+				return err
+			}
+		}
+		if input.Splits != nil {
+			if err := db.SetExpenseSplits(ctx, hid, id, splitLines(input.Splits)); err != nil {
+				return err
+			}
+		}
+		if input.ReimbursableFrom != nil {
+			if _, err := db.SetExpenseReimbursable(ctx, hid, id, *input.ReimbursableFrom); err != nil {
+				return err
+			}
+		}
+		return reshare(ctx, hid, *before, input.Amount)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		current, err := s.GetExpense(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		return nil, &ConflictError{Current: current}
+	}
+
+	expense, err := s.GetExpense(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.record(ctx, models.AuditEntityExpense, id, models.AuditActionUpdate, before, expense); err != nil {
+		return nil, err
+	}
+	return expense, nil
+}
+
+// DeleteExpense deletes an expense, returning the audit entry that can undo it
+func (s *Service) DeleteExpense(ctx context.Context, id int64) (*models.AuditEntry, error) {
+	hid, err := editableHousehold(ctx)
+	if err != nil {
+		return nil, err
+	}
+	before, err := s.GetExpense(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkDeletable(*before); err != nil {
+		return nil, err
+	}
+	if err := db.DeleteExpense(ctx, hid, id); err != nil {
+		return nil, err
+	}
+	return s.record(ctx, models.AuditEntityExpense, id, models.AuditActionDelete, before, nil)
+}
+
+// checkDeletable rejects deleting an expense that is locked or has refunds
+func checkDeletable(expense models.Expense) error {
+	if err := checkUnlocked(expense); err != nil {
+		return err
+	}
+	if toCents(expense.Refunded) > 0 {
+		return invalid("this expense has refunds; delete them first")
+	}
+	return nil
+}
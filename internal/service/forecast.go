@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"spending-tracker/db"
+	"spending-tracker/models"
+)
+
+// Forecast projects the current period's spending to the end of the month.
+// Like the summary, it counts only the signed-in person's share of shared
+// expenses.
+func (s *Service) Forecast(ctx context.Context) (*models.Forecast, error) {
+	return s.forecast(ctx, true)
+}
+
+// forecast projects the current period's spending, counting only the
+// signed-in person's share of shared expenses when personal is set and
+// every expense in full otherwise
+func (s *Service) forecast(ctx context.Context, personal bool) (*models.Forecast, error) {
+	hid := householdID(ctx)
+	now := time.Now()
+	period := models.Period{Year: now.Year(), Month: int(now.Month())}
+
+	// The period's recurring expenses are charged when it is first loaded
+	if err := db.InitializeMonth(ctx, hid, period.Year, period.Month); err != nil {
+		return nil, err
+	}
+	income, err := db.GetIncomeByPeriod(ctx, hid, period.Year, period.Month)
+	if err != nil {
+		return nil, err
+	}
+	savedToGoals, err := db.GetContributionTotal(ctx, hid, period.Year, period.Month)
+	if err != nil {
+		return nil, err
+	}
+	person, err := s.currentPerson(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	counted := func(expenses []models.Expense) []models.Expense {
+		if person == nil || !personal {
+			return expenses
+		}
+		return models.PersonalExpenses(expenses, person.ID)
+	}
+
+	expenses, err := db.GetExpensesByPeriod(ctx, hid, period.Year, period.Month)
+	if err != nil {
+		return nil, err
+	}
+
+	charged := make(map[int64]bool)
+	for _, e := range expenses {
+		if e.RecurringExpenseID != nil {
+			charged[*e.RecurringExpenseID] = true
+		}
+	}
+	templates, err := db.GetActiveRecurringExpenses(ctx, hid)
+	if err != nil {
+		return nil, err
+	}
+	var pending []models.PendingCharge
+	for _, t := range templates {
+		if t.RecurringExpenseID == nil || charged[*t.RecurringExpenseID] {
+			continue
+		}
+		pending = append(pending, models.PendingCharge{
+			RecurringExpenseID: *t.RecurringExpenseID,
+			Description:        t.Description,
+			Amount:             t.Amount,
+		})
+	}
+
+	var past []models.PastMonth
+	for year := period.Year - 1; year >= period.Year-models.ForecastYears; year-- {
+		earlier, err := db.GetExpensesByPeriod(ctx, hid, year, period.Month)
+		if err != nil {
+			return nil, err
+		}
+		if len(earlier) == 0 {
+			continue
+		}
+		past = append(past, models.PastMonth{Year: year, OneTime: models.OneTimeTotal(counted(earlier))})
+	}
+
+	forecast := models.BuildForecast(period, now.Day(), income, savedToGoals, counted(expenses), pending, past)
+	return &forecast, nil
+}
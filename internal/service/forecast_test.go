@@ -0,0 +1,42 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"spending-tracker/models"
+)
+
+func TestForecastCountsNewRecurringExpenseOnce(t *testing.T) {
+	s, ctx := newTestService(t)
+	now := time.Now()
+	period := models.Period{Year: now.Year(), Month: int(now.Month())}
+
+	// Load the period first so the new template is not charged again when
+	// the month is initialised
+	if _, err := s.Forecast(ctx); err != nil {
+		t.Fatalf("forecast: %v", err)
+	}
+	created, err := s.CreateExpense(ctx, period, ExpenseInput{
+		Description: "Gym",
+		Amount:      40,
+		Type:        models.ExpenseTypeRecurring,
+	})
+	if err != nil {
+		t.Fatalf("creating recurring expense: %v", err)
+	}
+	if created.RecurringExpenseID == nil {
+		t.Fatal("recurring expense is not linked to the template it created")
+	}
+
+	forecast, err := s.Forecast(ctx)
+	if err != nil {
+		t.Fatalf("forecast: %v", err)
+	}
+	if len(forecast.Pending) != 0 {
+		t.Errorf("pending = %+v, want none", forecast.Pending)
+	}
+	if forecast.ProjectedSpending != 40 {
+		t.Errorf("projected spending = %.2f, want 40", forecast.ProjectedSpending)
+	}
+}
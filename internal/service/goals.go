@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"spending-tracker/db"
+	"spending-tracker/models"
+)
+
+// GoalInput holds the editable fields of a savings goal
+type GoalInput struct {
+	Name         string  `json:"name"`
+	TargetAmount float64 `json:"target_amount"`
+	// TargetDate is when the goal should be reached, as YYYY-MM-DD
+	TargetDate string `json:"target_date"`
+	AccountID  *int64 `json:"account_id"`
+}
+
+// ContributionInput puts money toward a goal in a period; a negative amount
+// takes money back out
+type ContributionInput struct {
+	GoalID int64   `json:"goal_id"`
+	Amount float64 `json:"amount"`
+	Year   int     `json:"year"`
+	Month  int     `json:"month"`
+	Note   string  `json:"note"`
+}
+
+func validateGoal(ctx context.Context, input GoalInput) (models.SavingsGoal, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return models.SavingsGoal{}, invalid("name is required")
+	}
+	if len(name) > 100 {
+		return models.SavingsGoal{}, invalid("name must be at most 100 characters")
+	}
+	if toCents(input.TargetAmount) <= 0 {
+		return models.SavingsGoal{}, invalid("target amount must be positive")
+	}
+	targetDate, err := time.Parse("2006-01-02", input.TargetDate)
+	if err != nil {
+		return models.SavingsGoal{}, invalid("target date must be a date like 2025-12-31")
+	}
+	if err := validateAccountRef(ctx, input.AccountID); err != nil {
+		return models.SavingsGoal{}, err
+	}
+	return models.SavingsGoal{
+		Name:         name,
+		TargetAmount: input.TargetAmount,
+		TargetDate:   targetDate,
+		AccountID:    input.AccountID,
+	}, nil
+}
+
+// ListGoals returns the household's savings goals with what each needs per
+// month from the current period to be reached on time
+func (s *Service) ListGoals(ctx context.Context) ([]models.SavingsGoal, error) {
+	goals, err := db.GetGoals(ctx, householdID(ctx))
+	if err != nil {
+		return nil, err
+	}
+	current := models.CurrentPeriod()
+	for i := range goals {
+		goals[i].RequiredMonthly = goals[i].RequiredMonthlyFrom(current)
+	}
+	return goals, nil
+}
+
+func (s *Service) GetGoal(ctx context.Context, id int64) (*models.SavingsGoal, error) {
+	goal, err := db.GetGoal(ctx, householdID(ctx), id)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	goal.RequiredMonthly = goal.RequiredMonthlyFrom(models.CurrentPeriod())
+	return goal, nil
+}
+
+func (s *Service) CreateGoal(ctx context.Context, input GoalInput) (*models.SavingsGoal, error) {
+	hid, err := editableHousehold(ctx)
+	if err != nil {
+		return nil, err
+	}
+	goal, err := validateGoal(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := db.CreateGoal(ctx, hid, goal)
+	if isUniqueViolation(err) {
+		return nil, invalid("a goal called %s already exists", goal.Name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.GetGoal(ctx, id)
+}
+
+func (s *Service) UpdateGoal(ctx context.Context, id int64, input GoalInput) (*models.SavingsGoal, error) {
+	hid, err := editableHousehold(ctx)
+	if err != nil {
+		return nil, err
+	}
+	goal, err := validateGoal(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	goal.ID = id
+	updated, err := db.UpdateGoal(ctx, hid, goal)
+	if isUniqueViolation(err) {
+		return nil, invalid("a goal called %s already exists", goal.Name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, ErrNotFound
+	}
+	return s.GetGoal(ctx, id)
+}
+
+// DeleteGoal removes a goal along with its contributions, which then no
+// longer count as saved in their periods' summaries
+func (s *Service) DeleteGoal(ctx context.Context, id int64) error {
+	hid, err := editableHousehold(ctx)
+	if err != nil {
+		return err
+	}
+	deleted, err := db.DeleteGoal(ctx, hid, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ListContributions returns what was put toward goals in a period
+func (s *Service) ListContributions(ctx context.Context, period models.Period) ([]models.GoalContribution, error) {
+	if !period.IsValid() {
+		return nil, invalid("invalid period %d-%d", period.Year, period.Month)
+	}
+	return db.GetContributions(ctx, householdID(ctx), period.Year, period.Month)
+}
+
+// CreateContribution records money put toward a goal. Contributions are
+// saving rather than spending: the summary shows them as saved toward goals
+// apart from what is left over.
+func (s *Service) CreateContribution(ctx context.Context, input ContributionInput) (*models.GoalContribution, error) {
+	hid, err := editableHousehold(ctx)
+	if err != nil {
+		return nil, err
+	}
+	period := models.Period{Year: input.Year, Month: input.Month}
+	if !period.IsValid() {
+		return nil, invalid("invalid period %d-%d", period.Year, period.Month)
+	}
+	if toCents(input.Amount) == 0 {
+		return nil, invalid("amount must not be zero")
+	}
+	input.Note = strings.TrimSpace(input.Note)
+	if len(input.Note) > 255 {
+		return nil, invalid("note must be at most 255 characters")
+	}
+	if _, err := s.GetGoal(ctx, input.GoalID); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, invalid("goal %d not found", input.GoalID)
+		}
+		return nil, err
+	}
+
+	id, err := db.CreateContribution(ctx, hid, models.GoalContribution{
+		GoalID: input.GoalID,
+		Amount: input.Amount,
+		Year:   input.Year,
+		Month:  input.Month,
+		Note:   input.Note,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return db.GetContribution(ctx, hid, id)
+}
+
+func (s *Service) DeleteContribution(ctx context.Context, id int64) error {
+	hid, err := editableHousehold(ctx)
+	if err != nil {
+		return err
+	}
+	deleted, err := db.DeleteContribution(ctx, hid, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"spending-tracker/db"
+	"spending-tracker/internal/auth"
+	"spending-tracker/models"
+)
+
+// ErrForbidden is returned when the member's role does not allow an operation
+var ErrForbidden = errors.New("forbidden")
+
+// householdID returns the household the request acts on. Outside household
+// routes it is zero, which matches no rows.
+func householdID(ctx context.Context) int64 {
+	if m := auth.MembershipFromContext(ctx); m != nil {
+		return m.Household.ID
+	}
+	return 0
+}
+
+// editableHousehold returns the active household if the member may change its data
+func editableHousehold(ctx context.Context) (int64, error) {
+	m := auth.MembershipFromContext(ctx)
+	if m == nil || !m.Role.CanEdit() {
+		return 0, ErrForbidden
+	}
+	return m.Household.ID, nil
+}
+
+// managedHousehold returns the active household if the member owns it
+func managedHousehold(ctx context.Context) (int64, error) {
+	m := auth.MembershipFromContext(ctx)
+	if m == nil || !m.Role.CanManage() {
+		return 0, ErrForbidden
+	}
+	return m.Household.ID, nil
+}
+
+func validateHouseholdName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", invalid("household name is required")
+	}
+	return name, nil
+}
+
+// Membership returns a user's membership of a household
+func (s *Service) Membership(ctx context.Context, userID, householdID int64) (*models.Membership, error) {
+	m, err := db.GetMembership(ctx, userID, householdID)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return m, nil
+}
+
+// DefaultMembership returns the user's first household, creating a personal
+// one for users who do not belong to any
+func (s *Service) DefaultMembership(ctx context.Context, userID int64) (*models.Membership, error) {
+	memberships, err := db.GetMemberships(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(memberships) > 0 {
+		return &memberships[0], nil
+	}
+
+	name := "My budget"
+	if user := auth.UserFromContext(ctx); user != nil {
+		name = user.Username + "'s budget"
+	}
+	household, err := db.CreateHousehold(ctx, name, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.Membership{Household: *household, Role: models.RoleOwner}, nil
+}
+
+// ListHouseholds returns the signed-in user's households
+func (s *Service) ListHouseholds(ctx context.Context) ([]models.Membership, error) {
+	user := auth.UserFromContext(ctx)
+	if user == nil {
+		return nil, ErrForbidden
+	}
+	return db.GetMemberships(ctx, user.ID)
+}
+
+// CreateHousehold creates a household owned by the signed-in user
+func (s *Service) CreateHousehold(ctx context.Context, name string) (*models.Household, error) {
+	user := auth.UserFromContext(ctx)
+	if user == nil {
+		return nil, ErrForbidden
+	}
+	name, err := validateHouseholdName(name)
+	if err != nil {
+		return nil, err
+	}
+	return db.CreateHousehold(ctx, name, user.ID)
+}
+
+func (s *Service) RenameHousehold(ctx context.Context, name string) error {
+	hid, err := managedHousehold(ctx)
+	if err != nil {
+		return err
+	}
+	name, err = validateHouseholdName(name)
+	if err != nil {
+		return err
+	}
+	return db.RenameHousehold(ctx, hid, name)
+}
+
+func (s *Service) HouseholdMembers(ctx context.Context) ([]models.Member, error) {
+	return db.GetHouseholdMembers(ctx, householdID(ctx))
+}
+
+// AddHouseholdMember gives an existing user access to the active household
+func (s *Service) AddHouseholdMember(ctx context.Context, username string, role models.Role) error {
+	hid, err := managedHousehold(ctx)
+	if err != nil {
+		return err
+	}
+	if !role.IsValid() {
+		return invalid("unknown role %q", role)
+	}
+
+	user, err := db.GetUserByUsername(ctx, strings.ToLower(strings.TrimSpace(username)))
+	if errors.Is(notFound(err), ErrNotFound) {
+		return invalid("no user named %q", username)
+	}
+	if err != nil {
+		return err
+	}
+
+	err = db.AddHouseholdMember(ctx, hid, user.ID, role)
+	if isUniqueViolation(err) {
+		return invalid("%s is already a member", user.Username)
+	}
+	return err
+}
+
+// SetHouseholdMemberRole changes a member's role, keeping at least one owner
+func (s *Service) SetHouseholdMemberRole(ctx context.Context, userID int64, role models.Role) error {
+	hid, err := managedHousehold(ctx)
+	if err != nil {
+		return err
+	}
+	if !role.IsValid() {
+		return invalid("unknown role %q", role)
+	}
+	if role != models.RoleOwner {
+		if err := s.keepAnOwner(ctx, hid, userID); err != nil {
+			return err
+		}
+	}
+	return db.UpdateHouseholdMemberRole(ctx, hid, userID, role)
+}
+
+// RemoveHouseholdMember revokes a member's access, keeping at least one owner
+func (s *Service) RemoveHouseholdMember(ctx context.Context, userID int64) error {
+	hid, err := managedHousehold(ctx)
+	if err != nil {
+		return err
+	}
+	if err := s.keepAnOwner(ctx, hid, userID); err != nil {
+		return err
+	}
+	return db.RemoveHouseholdMember(ctx, hid, userID)
+}
+
+// keepAnOwner rejects demoting or removing userID if they are the last owner
+func (s *Service) keepAnOwner(ctx context.Context, hid, userID int64) error {
+	m, err := db.GetMembership(ctx, userID, hid)
+	if err != nil {
+		return notFound(err)
+	}
+	if m.Role != models.RoleOwner {
+		return nil
+	}
+	owners, err := db.CountHouseholdOwners(ctx, hid)
+	if err != nil {
+		return err
+	}
+	if owners <= 1 {
+		return invalid("a household needs at least one owner")
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+
+	"spending-tracker/db"
+	"spending-tracker/models"
+)
+
+// incomeSnapshot is how income changes appear in the audit log
+type incomeSnapshot struct {
+	Year   int     `json:"year"`
+	Month  int     `json:"month"`
+	Amount float64 `json:"amount"`
+}
+
+func (s *Service) GetIncome(ctx context.Context, period models.Period) (float64, error) {
+	if !period.IsValid() {
+		return 0, invalid("invalid period %d-%d", period.Year, period.Month)
+	}
+	return db.GetIncomeByPeriod(ctx, householdID(ctx), period.Year, period.Month)
+}
+
+func (s *Service) SetIncome(ctx context.Context, period models.Period, amount float64) error {
+	hid, err := editableHousehold(ctx)
+	if err != nil {
+		return err
+	}
+	if !period.IsValid() {
+		return invalid("invalid period %d-%d", period.Year, period.Month)
+	}
+	if amount < 0 {
+		return invalid("income must not be negative")
+	}
+
+	before, err := db.GetIncomeByPeriod(ctx, hid, period.Year, period.Month)
+	if err != nil {
+		return err
+	}
+	if err := db.UpsertIncome(ctx, hid, period.Year, period.Month, amount); err != nil {
+		return err
+	}
+
+	_, err = s.record(ctx, models.AuditEntityIncome, 0, models.AuditActionUpdate,
+		incomeSnapshot{period.Year, period.Month, before}, incomeSnapshot{period.Year, period.Month, amount})
+	return err
+}
@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+
+	"spending-tracker/db"
+	"spending-tracker/models"
+)
+
+// LoanInput holds the editable fields of a loan
+type LoanInput struct {
+	Name string `json:"name"`
+	// Principal is what was owed at the start of the start period
+	Principal      float64 `json:"principal"`
+	APR            float64 `json:"apr"`
+	MinimumPayment float64 `json:"minimum_payment"`
+	// StartYear and StartMonth default to the current period when omitted
+	StartYear          int    `json:"start_year"`
+	StartMonth         int    `json:"start_month"`
+	RecurringExpenseID *int64 `json:"recurring_expense_id"`
+}
+
+func (s *Service) validateLoan(ctx context.Context, input LoanInput) (models.Loan, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return models.Loan{}, invalid("name is required")
+	}
+	if len(name) > 100 {
+		return models.Loan{}, invalid("name must be at most 100 characters")
+	}
+	if input.Principal < 0 {
+		return models.Loan{}, invalid("principal must not be negative")
+	}
+	if input.APR < 0 || input.APR > 1000 {
+		return models.Loan{}, invalid("APR must be between 0 and 1000")
+	}
+	if input.MinimumPayment < 0 {
+		return models.Loan{}, invalid("minimum payment must not be negative")
+	}
+	start := models.Period{Year: input.StartYear, Month: input.StartMonth}
+	if input.StartYear == 0 && input.StartMonth == 0 {
+		start = models.CurrentPeriod()
+	}
+	if !start.IsValid() {
+		return models.Loan{}, invalid("invalid start period %d-%d", start.Year, start.Month)
+	}
+	if input.RecurringExpenseID != nil {
+		if _, err := s.GetRecurring(ctx, *input.RecurringExpenseID); err != nil {
+			if errors.Is(err, ErrNotFound) {
+				return models.Loan{}, invalid("recurring expense %d not found", *input.RecurringExpenseID)
+			}
+			return models.Loan{}, err
+		}
+	}
+	return models.Loan{
+		Name:               name,
+		Principal:          input.Principal,
+		APR:                input.APR,
+		MinimumPayment:     input.MinimumPayment,
+		StartYear:          start.Year,
+		StartMonth:         start.Month,
+		RecurringExpenseID: input.RecurringExpenseID,
+	}, nil
+}
+
+// ListLoans returns the household's loans with what is owed on each now
+func (s *Service) ListLoans(ctx context.Context) ([]models.Loan, error) {
+	hid := householdID(ctx)
+	loans, err := db.GetLoans(ctx, hid)
+	if err != nil {
+		return nil, err
+	}
+	payments, err := db.GetLoanPayments(ctx, hid)
+	if err != nil {
+		return nil, err
+	}
+	for i := range loans {
+		withBalance(&loans[i], payments[loans[i].ID])
+	}
+	return loans, nil
+}
+
+func (s *Service) GetLoan(ctx context.Context, id int64) (*models.Loan, error) {
+	hid := householdID(ctx)
+	loan, err := db.GetLoan(ctx, hid, id)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	payments, err := db.GetLoanPayments(ctx, hid)
+	if err != nil {
+		return nil, err
+	}
+	withBalance(loan, payments[id])
+	return loan, nil
+}
+
+// withBalance fills in what is owed on a loan as of the current period and
+// its regular repayment
+func withBalance(loan *models.Loan, payments []models.LoanPayment) {
+	loan.Balance = loan.BalanceAt(models.CurrentPeriod(), payments)
+	loan.MonthlyPayment = loan.ScheduledPayment()
+}
+
+func (s *Service) CreateLoan(ctx context.Context, input LoanInput) (*models.Loan, error) {
+	hid, err := editableHousehold(ctx)
+	if err != nil {
+		return nil, err
+	}
+	loan, err := s.validateLoan(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := db.CreateLoan(ctx, hid, loan)
+	if isUniqueViolation(err) {
+		return nil, invalid("a loan called %s already exists", loan.Name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.GetLoan(ctx, id)
+}
+
+func (s *Service) UpdateLoan(ctx context.Context, id int64, input LoanInput) (*models.Loan, error) {
+	hid, err := editableHousehold(ctx)
+	if err != nil {
+		return nil, err
+	}
+	loan, err := s.validateLoan(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	loan.ID = id
+	updated, err := db.UpdateLoan(ctx, hid, loan)
+	if isUniqueViolation(err) {
+		return nil, invalid("a loan called %s already exists", loan.Name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, ErrNotFound
+	}
+	return s.GetLoan(ctx, id)
+}
+
+// DeleteLoan removes a loan; its extra payments are kept as ordinary expenses
+func (s *Service) DeleteLoan(ctx context.Context, id int64) error {
+	hid, err := editableHousehold(ctx)
+	if err != nil {
+		return err
+	}
+	deleted, err := db.DeleteLoan(ctx, hid, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// LoanSchedule returns the month-by-month paying off of a loan by its
+// regular repayment from the next period on, and whether it is ever paid off
+func (s *Service) LoanSchedule(ctx context.Context, id int64) (*models.Loan, []models.AmortizationRow, bool, error) {
+	loan, err := s.GetLoan(ctx, id)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	rows, paidOff := models.Amortize(loan.Balance, loan.APR, loan.MonthlyPayment, models.CurrentPeriod().Next())
+	return loan, rows, paidOff, nil
+}
+
+// PayoffPlans compares paying off every loan by the snowball and avalanche
+// strategies, with an extra amount on top of the regular repayments each
+// month from the next period on
+func (s *Service) PayoffPlans(ctx context.Context, extra float64) ([]models.PayoffPlan, error) {
+	if extra < 0 || math.IsNaN(extra) {
+		return nil, invalid("extra payment must not be negative")
+	}
+	loans, err := s.ListLoans(ctx)
+	if err != nil {
+		return nil, err
+	}
+	from := models.CurrentPeriod().Next()
+	return []models.PayoffPlan{
+		models.SimulatePayoff(loans, models.PayoffSnowball, extra, from),
+		models.SimulatePayoff(loans, models.PayoffAvalanche, extra, from),
+	}, nil
+}
+
+// SetExpenseLoan records an expense as an extra payment toward a loan,
+// reducing what is owed on it; nil makes it an ordinary expense again
+func (s *Service) SetExpenseLoan(ctx context.Context, id int64, loanID *int64) (*models.Expense, error) {
+	hid, err := editableHousehold(ctx)
+	if err != nil {
+		return nil, err
+	}
+	before, err := s.GetExpense(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if loanID != nil {
+		loan, err := s.GetLoan(ctx, *loanID)
+		if errors.Is(err, ErrNotFound) {
+			return nil, invalid("loan %d not found", *loanID)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if before.Amount <= 0 || before.IsRefund() {
+			return nil, invalid("only a payment can reduce a loan")
+		}
+		if loan.RecurringExpenseID != nil && before.RecurringExpenseID != nil &&
+			*loan.RecurringExpenseID == *before.RecurringExpenseID {
+			return nil, invalid("this expense is already a regular repayment of %s", loan.Name)
+		}
+		if (models.Period{Year: before.Year, Month: before.Month}).Index() < loan.StartPeriod().Index() {
+			return nil, invalid("this expense is from before %s was taken on", loan.Name)
+		}
+	}
+
+	updated, err := db.SetExpenseLoan(ctx, hid, id, loanID)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, ErrNotFound
+	}
+
+	expense, err := s.GetExpense(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.record(ctx, models.AuditEntityExpense, id, models.AuditActionUpdate, before, expense); err != nil {
+		return nil, err
+	}
+	return expense, nil
+}
@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"spending-tracker/db"
+	"spending-tracker/models"
+)
+
+// PayeeInput holds the editable fields of a payee
+type PayeeInput struct {
+	Name              string `json:"name"`
+	DefaultCategoryID *int64 `json:"default_category_id"`
+	// Aliases are extra patterns matched against descriptions, such as
+	// "tesco stores" or "tesco express"; the payee's name always matches
+	Aliases []string `json:"aliases"`
+}
+
+func validatePayee(ctx context.Context, input *PayeeInput) error {
+	input.Name = strings.TrimSpace(input.Name)
+	if input.Name == "" {
+		return invalid("name is required")
+	}
+	if len(input.Name) > 100 {
+		return invalid("name must be at most 100 characters")
+	}
+	if models.NormalizeDescription(input.Name) == "" {
+		return invalid("name must contain a letter or digit")
+	}
+	if err := validateCategoryRef(ctx, input.DefaultCategoryID); err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool)
+	aliases := []string{}
+	for _, alias := range input.Aliases {
+		alias = strings.TrimSpace(alias)
+		pattern := models.NormalizeDescription(alias)
+		if pattern == "" || seen[pattern] {
+			continue
+		}
+		if len(alias) > 100 {
+			return invalid("alias %q must be at most 100 characters", alias)
+		}
+		seen[pattern] = true
+		aliases = append(aliases, alias)
+	}
+	input.Aliases = aliases
+	return nil
+}
+
+func (s *Service) ListPayees(ctx context.Context) ([]models.Payee, error) {
+	return db.GetPayees(ctx, householdID(ctx))
+}
+
+func (s *Service) GetPayee(ctx context.Context, id int64) (*models.Payee, error) {
+	payee, err := db.GetPayee(ctx, householdID(ctx), id)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return payee, nil
+}
+
+// PayeeTotals returns what has been spent with each payee, biggest first
+func (s *Service) PayeeTotals(ctx context.Context) ([]models.PayeeTotal, error) {
+	return db.GetPayeeTotals(ctx, householdID(ctx))
+}
+
+// PayeeHistory returns what has been spent with a payee in total and in
+// each period
+func (s *Service) PayeeHistory(ctx context.Context, id int64) (*models.PayeeHistory, error) {
+	payee, err := s.GetPayee(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	periods, err := db.GetPayeePeriodTotals(ctx, householdID(ctx), id)
+	if err != nil {
+		return nil, err
+	}
+
+	history := &models.PayeeHistory{PayeeTotal: models.PayeeTotal{Payee: *payee}, Periods: periods}
+	for _, p := range periods {
+		history.Total += p.Total
+		history.Count += p.Count
+	}
+	return history, nil
+}
+
+// CreatePayee adds a payee and matches existing expenses without one to it
+func (s *Service) CreatePayee(ctx context.Context, input PayeeInput) (*models.Payee, error) {
+	hid, err := editableHousehold(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := validatePayee(ctx, &input); err != nil {
+		return nil, err
+	}
+
+	id, err := db.CreatePayee(ctx, hid, models.Payee{
+		Name:              input.Name,
+		DefaultCategoryID: input.DefaultCategoryID,
+		Aliases:           input.Aliases,
+	})
+	if isUniqueViolation(err) {
+		return nil, invalid("a payee called %s already exists", input.Name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.rematchPayee(ctx, hid, id); err != nil {
+		return nil, err
+	}
+	return s.GetPayee(ctx, id)
+}
+
+// UpdatePayee changes a payee and matches its expenses, and those without a
+// payee, again with its new name and aliases
+func (s *Service) UpdatePayee(ctx context.Context, id int64, input PayeeInput) (*models.Payee, error) {
+	hid, err := editableHousehold(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := validatePayee(ctx, &input); err != nil {
+		return nil, err
+	}
+
+	updated, err := db.UpdatePayee(ctx, hid, models.Payee{
+		ID:                id,
+		Name:              input.Name,
+		DefaultCategoryID: input.DefaultCategoryID,
+		Aliases:           input.Aliases,
+	})
+	if isUniqueViolation(err) {
+		return nil, invalid("a payee called %s already exists", input.Name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, ErrNotFound
+	}
+	if err := s.rematchPayee(ctx, hid, id); err != nil {
+		return nil, err
+	}
+	return s.GetPayee(ctx, id)
+}
+
+// DeletePayee removes a payee; its expenses are kept without one
+func (s *Service) DeletePayee(ctx context.Context, id int64) error {
+	hid, err := editableHousehold(ctx)
+	if err != nil {
+		return err
+	}
+	deleted, err := db.DeletePayee(ctx, hid, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// matchPayee finds the payee an expense description belongs to, if any
+func matchPayee(ctx context.Context, description string) (*models.Payee, error) {
+	payees, err := db.GetPayees(ctx, householdID(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return models.MatchPayee(payees, description), nil
+}
+
+// rematchPayee matches the expenses without a payee, and those already
+// matched to the given payee, against every payee again
+func (s *Service) rematchPayee(ctx context.Context, hid, payeeID int64) error {
+	payees, err := db.GetPayees(ctx, hid)
+	if err != nil {
+		return err
+	}
+	expenses, err := db.GetExpensesToMatch(ctx, hid, payeeID)
+	if err != nil {
+		return err
+	}
+
+	var unmatched []int64
+	matched := make(map[int64][]int64)
+	for _, e := range expenses {
+		payee := models.MatchPayee(payees, e.Description)
+		switch {
+		case payee == nil && e.PayeeID != nil:
+			unmatched = append(unmatched, e.ID)
+		case payee != nil && (e.PayeeID == nil || *e.PayeeID != payee.ID):
+			matched[payee.ID] = append(matched[payee.ID], e.ID)
+		}
+	}
+
+	if len(unmatched) > 0 {
+		if err := db.AssignPayee(ctx, hid, nil, unmatched); err != nil {
+			return err
+		}
+	}
+	for id, expenseIDs := range matched {
+		if err := db.AssignPayee(ctx, hid, &id, expenseIDs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"spending-tracker/db"
+	"spending-tracker/models"
+)
+
+// Projection looks at cash flow over the next ProjectionMonths periods,
+// applying what-if adjustments that are never saved. Income carries forward
+// from the current period unless a later one has its own, and discretionary
+// spending is the average of the last ProjectionHistory periods. With
+// accounts it starts from their balances, which hold the whole household's
+// money, so every expense counts in full; without them it counts only the
+// signed-in person's share of shared expenses, like the summary.
+func (s *Service) Projection(ctx context.Context, adjustments models.ProjectionAdjustments) (*models.Projection, error) {
+	hid := householdID(ctx)
+	current := models.CurrentPeriod()
+	balances, err := db.GetAccountBalances(ctx, hid, current.Year, current.Month)
+	if err != nil {
+		return nil, err
+	}
+	fromAccounts := len(balances) > 0
+	forecast, err := s.forecast(ctx, !fromAccounts)
+	if err != nil {
+		return nil, err
+	}
+	start := current.Next()
+	end := current.AddMonths(models.ProjectionMonths)
+
+	recurring, err := db.GetRecurringExpenses(ctx, hid)
+	if err != nil {
+		return nil, err
+	}
+	active := make(map[int64]bool)
+	var scheduled []models.RecurringExpense
+	for _, r := range recurring {
+		if r.IsActive {
+			active[r.ID] = true
+			scheduled = append(scheduled, r)
+		}
+	}
+	for _, id := range adjustments.Cancel {
+		if !active[id] {
+			return nil, invalid("recurring expense %d is not active", id)
+		}
+	}
+	for i, o := range adjustments.OneOffs {
+		index := o.Period().Index()
+		if !o.Period().IsValid() || index < start.Index() || index > end.Index() {
+			return nil, invalid("one-offs must fall within the next %d months", models.ProjectionMonths)
+		}
+		if o.Amount == 0 {
+			return nil, invalid("one-off amount must not be zero")
+		}
+		adjustments.OneOffs[i].Description = strings.TrimSpace(o.Description)
+	}
+
+	incomes := make([]float64, models.ProjectionMonths)
+	income := forecast.Income
+	for i := range incomes {
+		period := start.AddMonths(i)
+		set, err := db.GetIncomeByPeriod(ctx, hid, period.Year, period.Month)
+		if err != nil {
+			return nil, err
+		}
+		if set > 0 {
+			income = set
+		}
+		incomes[i] = income
+	}
+
+	history, err := db.GetExpensesBetween(ctx, hid, current.AddMonths(-models.ProjectionHistory), current.Prev())
+	if err != nil {
+		return nil, err
+	}
+	person, err := s.currentPerson(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if person != nil && !fromAccounts {
+		history = models.PersonalExpenses(history, person.ID)
+	}
+	// Households newer than the history average over the months they have
+	months := make(map[int]bool)
+	for _, e := range history {
+		months[models.Period{Year: e.Year, Month: e.Month}.Index()] = true
+	}
+	discretionary := models.AverageDiscretionary(history, len(months))
+
+	opening := models.OpeningBalance(*forecast, balances)
+	projection := models.BuildProjection(opening, fromAccounts, start, incomes, scheduled, discretionary, adjustments)
+	return &projection, nil
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"spending-tracker/db"
+	"spending-tracker/models"
+)
+
+// ReconciliationInput is the statement an account is reconciled against
+type ReconciliationInput struct {
+	// StatementDate is the statement's closing date as YYYY-MM-DD
+	StatementDate    string  `json:"statement_date"`
+	StatementBalance float64 `json:"statement_balance"`
+}
+
+// Reconciliation returns an account's reconciliation in progress with the
+// expenses that can be ticked off against it, and its completed history
+func (s *Service) Reconciliation(ctx context.Context, accountID int64) (*models.AccountReconciliation, error) {
+	hid := householdID(ctx)
+	account, err := s.GetAccount(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.AccountReconciliation{Account: *account}
+	result.Open, err = s.openReconciliation(ctx, accountID)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+	if result.Open != nil {
+		period := result.Open.StatementPeriod()
+		if result.Expenses, err = db.GetUnreconciledExpenses(ctx, hid, accountID, period.Year, period.Month); err != nil {
+			return nil, err
+		}
+	}
+	if result.History, err = db.GetCompletedReconciliations(ctx, hid, accountID); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *Service) openReconciliation(ctx context.Context, accountID int64) (*models.Reconciliation, error) {
+	r, err := db.GetOpenReconciliation(ctx, householdID(ctx), accountID)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return r, nil
+}
+
+// StartReconciliation begins reconciling an account against a statement, or
+// corrects the statement of the reconciliation already in progress
+func (s *Service) StartReconciliation(ctx context.Context, accountID int64, input ReconciliationInput) (*models.Reconciliation, error) {
+	hid, err := editableHousehold(ctx)
+	if err != nil {
+		return nil, err
+	}
+	statementDate, err := time.Parse("2006-01-02", input.StatementDate)
+	if err != nil {
+		return nil, invalid("statement date must be a date like 2024-01-31")
+	}
+	if _, err := s.GetAccount(ctx, accountID); err != nil {
+		return nil, err
+	}
+
+	if err := db.SaveReconciliation(ctx, hid, accountID, statementDate, input.StatementBalance); err != nil {
+		return nil, err
+	}
+	return s.openReconciliation(ctx, accountID)
+}
+
+// SetExpenseCleared ticks an expense off against the statement being
+// reconciled, or unticks it, and returns the updated difference
+func (s *Service) SetExpenseCleared(ctx context.Context, accountID, expenseID int64, cleared bool) (*models.Reconciliation, error) {
+	hid, err := editableHousehold(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.reconciliationInProgress(ctx, accountID); err != nil {
+		return nil, err
+	}
+
+	found, err := db.SetExpenseCleared(ctx, hid, accountID, expenseID, cleared)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, invalid("expense %d is not an unreconciled expense on this account", expenseID)
+	}
+	return s.openReconciliation(ctx, accountID)
+}
+
+// CompleteReconciliation finishes reconciling an account once the cleared
+// balance matches the statement, locking the expenses ticked off
+func (s *Service) CompleteReconciliation(ctx context.Context, accountID int64) (*models.Reconciliation, error) {
+	hid, err := editableHousehold(ctx)
+	if err != nil {
+		return nil, err
+	}
+	open, err := s.reconciliationInProgress(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if !open.IsBalanced() {
+		return nil, invalid("the cleared balance is £%.2f but the statement says £%.2f", open.ClearedBalance, open.StatementBalance)
+	}
+
+	period := open.StatementPeriod()
+	if err := db.CompleteReconciliation(ctx, hid, accountID, period.Year, period.Month); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	open.CompletedAt = &now
+	return open, nil
+}
+
+// reconciliationInProgress returns the account's open reconciliation,
+// failing validation when there is none
+func (s *Service) reconciliationInProgress(ctx context.Context, accountID int64) (*models.Reconciliation, error) {
+	if _, err := s.GetAccount(ctx, accountID); err != nil {
+		return nil, err
+	}
+	open, err := s.openReconciliation(ctx, accountID)
+	if errors.Is(err, ErrNotFound) {
+		return nil, invalid("enter a statement to start reconciling this account")
+	}
+	return open, err
+}
+
+// UnlockExpense releases a reconciled expense so it can be edited or
+// deleted. It must be ticked off again in the account's next reconciliation.
+func (s *Service) UnlockExpense(ctx context.Context, id int64) (*models.Expense, error) {
+	hid, err := editableHousehold(ctx)
+	if err != nil {
+		return nil, err
+	}
+	before, err := s.GetExpense(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !before.IsReconciled() {
+		return before, nil
+	}
+
+	if _, err := db.UnlockExpense(ctx, hid, id); err != nil {
+		return nil, err
+	}
+
+	expense, err := s.GetExpense(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.record(ctx, models.AuditEntityExpense, id, models.AuditActionUpdate, before, expense); err != nil {
+		return nil, err
+	}
+	return expense, nil
+}
+
+// checkUnlocked rejects changes to an expense reconciled against a statement
+func checkUnlocked(expense models.Expense) error {
+	if expense.IsReconciled() {
+		return invalid("this expense has been reconciled against a statement; unlock it to change it")
+	}
+	return nil
+}
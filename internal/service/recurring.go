@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"spending-tracker/db"
+	"spending-tracker/models"
+)
+
+// RecurringInput holds the editable fields of a recurring template
+type RecurringInput struct {
+	Description string  `json:"description"`
+	Amount      float64 `json:"amount"`
+	CategoryID  *int64  `json:"category_id"`
+	// IsActive defaults to true when omitted
+	IsActive *bool `json:"is_active"`
+}
+
+func (s *Service) validateRecurring(ctx context.Context, input *RecurringInput) error {
+	input.Description = strings.TrimSpace(input.Description)
+	if input.Description == "" {
+		return invalid("description is required")
+	}
+	if input.Amount < 0 {
+		return invalid("amount must not be negative")
+	}
+	if input.CategoryID != nil {
+		if _, err := db.GetCategoryByID(ctx, householdID(ctx), *input.CategoryID); err != nil {
+			if errors.Is(notFound(err), ErrNotFound) {
+				return invalid("category %d not found", *input.CategoryID)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Service) ListRecurring(ctx context.Context) ([]models.RecurringExpense, error) {
+	return db.GetRecurringExpenses(ctx, householdID(ctx))
+}
+
+func (s *Service) GetRecurring(ctx context.Context, id int64) (*models.RecurringExpense, error) {
+	r, err := db.GetRecurringExpenseByID(ctx, householdID(ctx), id)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return r, nil
+}
+
+// CreateRecurring adds a template that is copied into periods initialized from now on
+func (s *Service) CreateRecurring(ctx context.Context, input RecurringInput) (*models.RecurringExpense, error) {
+	hid, err := editableHousehold(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validateRecurring(ctx, &input); err != nil {
+		return nil, err
+	}
+
+	id, err := db.CreateRecurringExpense(ctx, hid, input.Description, input.Amount, input.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+	if input.IsActive != nil && !*input.IsActive {
+		if err := db.DeleteRecurringExpense(ctx, hid, id); err != nil {
+			return nil, err
+		}
+	}
+
+	created, err := s.GetRecurring(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.record(ctx, models.AuditEntityRecurring, id, models.AuditActionCreate, nil, created); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+func (s *Service) UpdateRecurring(ctx context.Context, id int64, input RecurringInput) (*models.RecurringExpense, error) {
+	hid, err := editableHousehold(ctx)
+	if err != nil {
+		return nil, err
+	}
+	before, err := s.GetRecurring(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validateRecurring(ctx, &input); err != nil {
+		return nil, err
+	}
+
+	isActive := input.IsActive == nil || *input.IsActive
+	if err := db.UpdateRecurringExpense(ctx, hid, id, input.Description, input.Amount, input.CategoryID, isActive); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.GetRecurring(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.record(ctx, models.AuditEntityRecurring, id, models.AuditActionUpdate, before, updated); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// DeactivateRecurring stops a template being copied into new periods,
+// leaving expenses already created from it untouched. It returns the audit
+// entry that can undo it.
+func (s *Service) DeactivateRecurring(ctx context.Context, id int64) (*models.AuditEntry, error) {
+	hid, err := editableHousehold(ctx)
+	if err != nil {
+		return nil, err
+	}
+	before, err := s.GetRecurring(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.DeleteRecurringExpense(ctx, hid, id); err != nil {
+		return nil, err
+	}
+	return s.record(ctx, models.AuditEntityRecurring, id, models.AuditActionDelete, before, nil)
+}
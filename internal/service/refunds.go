@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"spending-tracker/db"
+	"spending-tracker/models"
+)
+
+// RefundInput records money coming back for an expense
+type RefundInput struct {
+	// Amount is the money returned, as a positive amount
+	Amount      float64 `json:"amount"`
+	Description string  `json:"description"`
+	// CategoryID is the category the refund reduces; nil uses the refunded
+	// expense's category
+	CategoryID *int64 `json:"category_id"`
+}
+
+func validateReimbursableFrom(from string, amount float64) error {
+	if from == "" {
+		return nil
+	}
+	if len(from) > 255 {
+		return invalid("who reimburses the expense must be at most 255 characters")
+	}
+	if toCents(amount) <= 0 {
+		return invalid("only expenses with a positive amount can be reimbursable")
+	}
+	return nil
+}
+
+// checkRefundLimit rejects refunds adding up to more than the refunded
+// expense. replacing is the amount of an existing refund being edited.
+func checkRefundLimit(original models.Expense, amount, replacing float64) error {
+	if toCents(amount) <= 0 {
+		return invalid("a refund must be a positive amount")
+	}
+	left := toCents(original.Amount) - toCents(original.Refunded) + toCents(replacing)
+	if toCents(amount) > left {
+		return invalid("only £%.2f of %q is left to refund", float64(left)/100, original.Description)
+	}
+	return nil
+}
+
+// checkRefundedAmount keeps an edited expense consistent with its refunds:
+// a refund stays negative and within what is left of the refunded expense,
+// and a refunded expense cannot drop below what has already come back
+func (s *Service) checkRefundedAmount(ctx context.Context, before models.Expense, input ExpenseInput) error {
+	if before.IsRefund() {
+		if input.Type != models.ExpenseTypeOneTime {
+			return invalid("a refund cannot be recurring")
+		}
+		if input.ReimbursableFrom != nil && *input.ReimbursableFrom != "" {
+			return invalid("a refund cannot be reimbursable")
+		}
+		original, err := s.GetExpense(ctx, *before.RefundOf)
+		if err != nil {
+			return err
+		}
+		return checkRefundLimit(*original, -input.Amount, -before.Amount)
+	}
+
+	if before.IsReimbursable() && input.ReimbursableFrom == nil && toCents(input.Amount) <= 0 {
+		return invalid("a reimbursable expense's amount must be positive")
+	}
+	if toCents(input.Amount) < toCents(before.Refunded) {
+		return invalid("£%.2f of this expense has already been refunded", before.Refunded)
+	}
+	return nil
+}
+
+// CreateRefund records money coming back for an expense as a negative
+// expense in the period it arrived, reducing that period's category total.
+// The money goes back into the account the expense was paid from.
+// Reimbursements of reimbursable expenses are recorded the same way.
+func (s *Service) CreateRefund(ctx context.Context, expenseID int64, period models.Period, input RefundInput) (*models.Expense, error) {
+	hid, err := editableHousehold(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !period.IsValid() {
+		return nil, invalid("invalid period %d-%d", period.Year, period.Month)
+	}
+	original, err := s.GetExpense(ctx, expenseID)
+	if err != nil {
+		return nil, err
+	}
+	if !original.CanBeRefunded() {
+		return nil, invalid("only expenses with a positive amount can be refunded")
+	}
+	if err := checkRefundLimit(*original, input.Amount, 0); err != nil {
+		return nil, err
+	}
+
+	input.Description = strings.TrimSpace(input.Description)
+	if input.Description == "" {
+		input.Description = refundDescription(*original)
+	}
+	if input.CategoryID == nil {
+		input.CategoryID = original.RefundCategoryID()
+	}
+	if err := validateCategoryRef(ctx, input.CategoryID); err != nil {
+		return nil, err
+	}
+
+	created, err := db.CreateExpense(ctx, hid, models.Expense{
+		Description: input.Description,
+		Amount:      -input.Amount,
+		CategoryID:  input.CategoryID,
+		AccountID:   original.AccountID,
+		PayeeID:     original.PayeeID,
+		Type:        models.ExpenseTypeOneTime,
+		Year:        period.Year,
+		Month:       period.Month,
+		RefundOf:    &original.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	refund, err := s.GetExpense(ctx, created.ID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.record(ctx, models.AuditEntityExpense, refund.ID, models.AuditActionCreate, nil, refund); err != nil {
+		return nil, err
+	}
+	return refund, nil
+}
+
+func refundDescription(original models.Expense) string {
+	if original.IsReimbursable() {
+		return fmt.Sprintf("Reimbursement from %s: %s", original.ReimbursableFrom, original.Description)
+	}
+	return "Refund: " + original.Description
+}
+
+// SetReimbursable records who owes an expense back; empty marks it not reimbursable
+func (s *Service) SetReimbursable(ctx context.Context, id int64, from string) (*models.Expense, error) {
+	hid, err := editableHousehold(ctx)
+	if err != nil {
+		return nil, err
+	}
+	before, err := s.GetExpense(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	from = strings.TrimSpace(from)
+	if from != "" && before.IsRefund() {
+		return nil, invalid("a refund cannot be reimbursable")
+	}
+	if err := validateReimbursableFrom(from, before.Amount); err != nil {
+		return nil, err
+	}
+
+	updated, err := db.SetExpenseReimbursable(ctx, hid, id, from)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, ErrNotFound
+	}
+
+	expense, err := s.GetExpense(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.record(ctx, models.AuditEntityExpense, id, models.AuditActionUpdate, before, expense); err != nil {
+		return nil, err
+	}
+	return expense, nil
+}
+
+// PendingReimbursements returns the money still owed back, grouped by who owes it
+func (s *Service) PendingReimbursements(ctx context.Context) ([]models.ReimbursementGroup, error) {
+	expenses, err := db.GetPendingReimbursements(ctx, householdID(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return models.GroupReimbursements(expenses), nil
+}
@@ -0,0 +1,16 @@
+package service
+
+import (
+	"context"
+
+	"spending-tracker/db"
+	"spending-tracker/models"
+)
+
+// SearchExpenses finds expenses across every period, one page at a time
+func (s *Service) SearchExpenses(ctx context.Context, search models.ExpenseSearch) (models.SearchResults, error) {
+	if search.Page < 1 {
+		search.Page = 1
+	}
+	return db.SearchExpenses(ctx, householdID(ctx), search)
+}
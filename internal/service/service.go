@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"spending-tracker/db"
+	"spending-tracker/internal/analytics"
+	"spending-tracker/internal/auth"
+	"spending-tracker/internal/events"
+	"spending-tracker/models"
+)
+
+// ErrNotFound is returned when the requested record does not exist
+var ErrNotFound = errors.New("not found")
+
+// ValidationError reports input that was rejected before reaching the database
+type ValidationError struct {
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+func invalid(format string, args ...any) error {
+	return &ValidationError{Message: fmt.Sprintf(format, args...)}
+}
+
+// ConflictError reports an update based on a version of the expense that
+// has since been changed by someone else
+type ConflictError struct {
+	Current *models.Expense
+}
+
+func (e *ConflictError) Error() string {
+	return "this expense was changed by someone else"
+}
+
+// notFound maps a missing row to ErrNotFound, passing other errors through
+func notFound(err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// Service holds the operations shared by the HTML and JSON API handlers
+type Service struct {
+	events *events.Hub
+}
+
+// New creates a new Service instance that announces changes on hub
+func New(hub *events.Hub) *Service {
+	return &Service{events: hub}
+}
+
+// LoadPeriod loads the complete application state for a given period and filter,
+// copying recurring templates into the period on first access
+func (s *Service) LoadPeriod(ctx context.Context, period models.Period, filter models.ExpenseFilter) (models.AppState, error) {
+	if !period.IsValid() {
+		return models.AppState{}, invalid("invalid period %d-%d", period.Year, period.Month)
+	}
+
+	membership := auth.MembershipFromContext(ctx)
+	if membership == nil {
+		return models.AppState{}, ErrForbidden
+	}
+	hid := membership.Household.ID
+
+	if err := db.InitializeMonth(ctx, hid, period.Year, period.Month); err != nil {
+		return models.AppState{}, err
+	}
+
+	income, err := db.GetIncomeByPeriod(ctx, hid, period.Year, period.Month)
+	if err != nil {
+		return models.AppState{}, err
+	}
+
+	var expenses []models.Expense
+	tag, isTagFilter := filter.Tag()
+	switch {
+	case isTagFilter:
+		expenses, err = db.GetExpensesByPeriodAndTag(ctx, hid, period.Year, period.Month, tag)
+	case filter == models.FilterRecurring:
+		expenses, err = db.GetExpensesByPeriodAndType(ctx, hid, period.Year, period.Month, models.ExpenseTypeRecurring)
+	case filter == models.FilterOneTime:
+		expenses, err = db.GetExpensesByPeriodAndType(ctx, hid, period.Year, period.Month, models.ExpenseTypeOneTime)
+	default:
+		expenses, err = db.GetExpensesByPeriod(ctx, hid, period.Year, period.Month)
+	}
+	if err != nil {
+		return models.AppState{}, err
+	}
+
+	allExpenses, err := db.GetExpensesByPeriod(ctx, hid, period.Year, period.Month)
+	if err != nil {
+		return models.AppState{}, err
+	}
+
+	anomalies, err := detectAnomalies(ctx, hid, period, allExpenses)
+	if err != nil {
+		return models.AppState{}, err
+	}
+	analytics.Annotate(expenses, anomalies)
+
+	categories, err := db.GetAllCategories(ctx, hid)
+	if err != nil {
+		return models.AppState{}, err
+	}
+
+	tags, err := db.GetAllTags(ctx, hid)
+	if err != nil {
+		return models.AppState{}, err
+	}
+
+	households, err := s.ListHouseholds(ctx)
+	if err != nil {
+		return models.AppState{}, err
+	}
+
+	person, err := s.currentPerson(ctx)
+	if err != nil {
+		return models.AppState{}, err
+	}
+
+	accounts, err := db.GetAccounts(ctx, hid)
+	if err != nil {
+		return models.AppState{}, err
+	}
+
+	incomeAccountID, err := db.GetIncomeAccountID(ctx, hid, period.Year, period.Month)
+	if err != nil {
+		return models.AppState{}, err
+	}
+
+	savedToGoals, err := db.GetContributionTotal(ctx, hid, period.Year, period.Month)
+	if err != nil {
+		return models.AppState{}, err
+	}
+
+	// People sharing expenses see their own share rather than the gross amount
+	counted := allExpenses
+	if person != nil {
+		counted = models.PersonalExpenses(allExpenses, person.ID)
+	}
+	summary := models.CalculateSummary(income, savedToGoals, counted, categories, period.DaysInMonth())
+
+	return models.AppState{
+		Household:       *membership,
+		Households:      households,
+		Period:          period,
+		Income:          income,
+		Expenses:        expenses,
+		Categories:      categories,
+		Tags:            tags,
+		Summary:         summary,
+		Filter:          filter,
+		Person:          person,
+		Accounts:        accounts,
+		IncomeAccountID: incomeAccountID,
+	}, nil
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"spending-tracker/db"
+	"spending-tracker/internal/auth"
+	"spending-tracker/internal/events"
+	"spending-tracker/models"
+)
+
+var (
+	connectOnce sync.Once
+	connectErr  error
+)
+
+// newTestService connects to the database named by TEST_DATABASE_URL,
+// skipping the test without one, and returns a service acting as the owner
+// of a new, empty household
+func newTestService(t *testing.T) (*Service, context.Context) {
+	t.Helper()
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	ctx := context.Background()
+	connectOnce.Do(func() {
+		os.Setenv("DATABASE_URL", url)
+		if connectErr = db.Connect(ctx); connectErr == nil {
+			connectErr = db.RunMigrations(ctx)
+		}
+	})
+	if connectErr != nil {
+		t.Fatalf("connecting to the test database: %v", connectErr)
+	}
+
+	name := fmt.Sprintf("%s-%d", t.Name(), time.Now().UnixNano())
+	user, err := db.CreateUser(ctx, name, "x", false)
+	if err != nil {
+		t.Fatalf("creating user: %v", err)
+	}
+	household, err := db.CreateHousehold(ctx, name, user.ID)
+	if err != nil {
+		t.Fatalf("creating household: %v", err)
+	}
+
+	ctx = auth.WithUser(ctx, user)
+	ctx = auth.WithMembership(ctx, &models.Membership{Household: *household, Role: models.RoleOwner})
+	return New(events.NewHub()), ctx
+}
@@ -0,0 +1,267 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+
+	"spending-tracker/db"
+	"spending-tracker/internal/auth"
+	"spending-tracker/models"
+)
+
+// SharingInput describes who paid an expense and how it is shared.
+// An empty Method stops sharing the expense.
+type SharingInput struct {
+	PaidBy       *int64             `json:"paid_by"`
+	Method       models.ShareMethod `json:"method"`
+	Participants []ShareInput       `json:"participants"`
+}
+
+// ShareInput is one participant. Value is their percentage or exact amount,
+// and is ignored when sharing equally.
+type ShareInput struct {
+	PersonID int64   `json:"person_id"`
+	Value    float64 `json:"value"`
+}
+
+// SettlementInput records a payment from one person to another
+type SettlementInput struct {
+	FromPersonID int64   `json:"from_person_id"`
+	ToPersonID   int64   `json:"to_person_id"`
+	Amount       float64 `json:"amount"`
+	Note         string  `json:"note"`
+}
+
+func (s *Service) ListPeople(ctx context.Context) ([]models.Person, error) {
+	return db.GetPeople(ctx, householdID(ctx))
+}
+
+func (s *Service) getPerson(ctx context.Context, id int64) (*models.Person, error) {
+	person, err := db.GetPerson(ctx, householdID(ctx), id)
+	if errors.Is(notFound(err), ErrNotFound) {
+		return nil, invalid("person %d not found", id)
+	}
+	return person, err
+}
+
+// currentPerson returns the person linked to the signed-in user, or nil
+func (s *Service) currentPerson(ctx context.Context) (*models.Person, error) {
+	user := auth.UserFromContext(ctx)
+	if user == nil {
+		return nil, nil
+	}
+	person, err := db.GetPersonForUser(ctx, householdID(ctx), user.ID)
+	if errors.Is(notFound(err), ErrNotFound) {
+		return nil, nil
+	}
+	return person, err
+}
+
+// CreatePerson adds someone to share expenses with, optionally linked to a
+// household member so their own summary counts only their share
+func (s *Service) CreatePerson(ctx context.Context, name string, userID *int64) (*models.Person, error) {
+	hid, err := editableHousehold(ctx)
+	if err != nil {
+		return nil, err
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, invalid("name is required")
+	}
+	if len(name) > 100 {
+		return nil, invalid("name must be at most 100 characters")
+	}
+
+	if userID != nil {
+		members, err := s.HouseholdMembers(ctx)
+		if err != nil {
+			return nil, err
+		}
+		isMember := false
+		for _, m := range members {
+			isMember = isMember || m.UserID == *userID
+		}
+		if !isMember {
+			return nil, invalid("user %d is not a member of this household", *userID)
+		}
+	}
+
+	person, err := db.CreatePerson(ctx, hid, name, userID)
+	if isUniqueViolation(err) {
+		return nil, invalid("%s already exists, or that member is already linked to someone", name)
+	}
+	return person, err
+}
+
+// DeletePerson removes someone who is not named on any shared expense or settlement
+func (s *Service) DeletePerson(ctx context.Context, id int64) error {
+	hid, err := editableHousehold(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err := s.getPerson(ctx, id); err != nil {
+		return err
+	}
+
+	err = db.DeletePerson(ctx, hid, id)
+	if isForeignKeyViolation(err) {
+		return invalid("this person still has shared expenses or settlements")
+	}
+	return err
+}
+
+// validateSharing checks the input against an expense's amount and returns
+// the shares it produces
+func (s *Service) validateSharing(ctx context.Context, amount float64, input SharingInput) ([]models.ExpenseShare, error) {
+	if !input.Method.IsValid() {
+		return nil, invalid("unknown share method %q", input.Method)
+	}
+	if toCents(amount) <= 0 {
+		return nil, invalid("only expenses with a positive amount can be shared")
+	}
+	if input.PaidBy == nil {
+		return nil, invalid("choose who paid")
+	}
+	if _, err := s.getPerson(ctx, *input.PaidBy); err != nil {
+		return nil, err
+	}
+	if len(input.Participants) == 0 {
+		return nil, invalid("choose who shares the expense")
+	}
+
+	shares := make([]models.ExpenseShare, 0, len(input.Participants))
+	seen := make(map[int64]bool)
+	var total float64
+	for _, p := range input.Participants {
+		if seen[p.PersonID] {
+			return nil, invalid("each person can only share an expense once")
+		}
+		seen[p.PersonID] = true
+		person, err := s.getPerson(ctx, p.PersonID)
+		if err != nil {
+			return nil, err
+		}
+		if input.Method != models.ShareEqual && p.Value < 0 {
+			return nil, invalid("%s's share must not be negative", person.Name)
+		}
+		total += p.Value
+		shares = append(shares, models.ExpenseShare{PersonID: person.ID, PersonName: person.Name, Value: p.Value})
+	}
+
+	switch input.Method {
+	case models.SharePercent:
+		if math.Abs(total-100) > 0.01 {
+			return nil, invalid("percentages add up to %.2f%%, not 100%%", total)
+		}
+	case models.ShareExact:
+		if toCents(total) != toCents(amount) {
+			return nil, invalid("shares add up to £%.2f but the expense is £%.2f", total, amount)
+		}
+	}
+	return models.ComputeShares(amount, input.Method, shares), nil
+}
+
+// SetExpenseSharing records who paid an expense and how it is shared
+func (s *Service) SetExpenseSharing(ctx context.Context, id int64, input SharingInput) (*models.Expense, error) {
+	hid, err := editableHousehold(ctx)
+	if err != nil {
+		return nil, err
+	}
+	before, err := s.GetExpense(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Method == "" {
+		err = db.SetExpenseSharing(ctx, hid, id, nil, "", nil)
+	} else {
+		var shares []models.ExpenseShare
+		shares, err = s.validateSharing(ctx, before.Amount, input)
+		if err != nil {
+			return nil, err
+		}
+		err = db.SetExpenseSharing(ctx, hid, id, input.PaidBy, input.Method, shares)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	expense, err := s.GetExpense(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.record(ctx, models.AuditEntityExpense, id, models.AuditActionUpdate, before, expense); err != nil {
+		return nil, err
+	}
+	return expense, nil
+}
+
+// reshare recomputes a shared expense's shares after its amount changed.
+// Exact amounts cannot be rescaled, so those must be re-entered instead.
+func reshare(ctx context.Context, hid int64, before models.Expense, amount float64) error {
+	if !before.IsShared() || toCents(amount) == toCents(before.Amount) {
+		return nil
+	}
+	shares := models.ComputeShares(amount, before.ShareMethod, before.Shares)
+	return db.SetExpenseSharing(ctx, hid, before.ID, before.PaidBy, before.ShareMethod, shares)
+}
+
+// Balances returns where each person stands and the payments that would settle up
+func (s *Service) Balances(ctx context.Context) ([]models.Balance, []models.Debt, error) {
+	balances, err := db.GetBalances(ctx, householdID(ctx))
+	if err != nil {
+		return nil, nil, err
+	}
+	return balances, models.SettleUp(balances), nil
+}
+
+func (s *Service) ListSettlements(ctx context.Context) ([]models.Settlement, error) {
+	return db.GetSettlements(ctx, householdID(ctx))
+}
+
+// RecordSettlement records a payment between two people, reducing what one owes the other
+func (s *Service) RecordSettlement(ctx context.Context, input SettlementInput) (*models.Settlement, error) {
+	hid, err := editableHousehold(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if input.FromPersonID == input.ToPersonID {
+		return nil, invalid("a settlement must be between two different people")
+	}
+	if toCents(input.Amount) <= 0 {
+		return nil, invalid("amount must be positive")
+	}
+	input.Note = strings.TrimSpace(input.Note)
+	if len(input.Note) > 255 {
+		return nil, invalid("note must be at most 255 characters")
+	}
+	if _, err := s.getPerson(ctx, input.FromPersonID); err != nil {
+		return nil, err
+	}
+	if _, err := s.getPerson(ctx, input.ToPersonID); err != nil {
+		return nil, err
+	}
+
+	id, err := db.CreateSettlement(ctx, hid, input.FromPersonID, input.ToPersonID, input.Amount, input.Note)
+	if err != nil {
+		return nil, err
+	}
+	return db.GetSettlement(ctx, hid, id)
+}
+
+func (s *Service) DeleteSettlement(ctx context.Context, id int64) error {
+	hid, err := editableHousehold(ctx)
+	if err != nil {
+		return err
+	}
+	deleted, err := db.DeleteSettlement(ctx, hid, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrNotFound
+	}
+	return nil
+}
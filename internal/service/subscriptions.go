@@ -0,0 +1,31 @@
+package service
+
+import (
+	"context"
+
+	"spending-tracker/db"
+	"spending-tracker/models"
+)
+
+// Subscriptions reports on the household's recurring templates as of a
+// period: what each costs, when it renews, how its price has changed and
+// whether it still seems to be charged, along with regular charges that
+// have no template yet
+func (s *Service) Subscriptions(ctx context.Context, period models.Period) (*models.SubscriptionReport, error) {
+	if !period.IsValid() {
+		return nil, invalid("invalid period %d-%d", period.Year, period.Month)
+	}
+	hid := householdID(ctx)
+
+	templates, err := db.GetRecurringExpenses(ctx, hid)
+	if err != nil {
+		return nil, err
+	}
+	charges, err := db.GetChargesBetween(ctx, hid, period.AddMonths(1-models.SubscriptionHistory), period)
+	if err != nil {
+		return nil, err
+	}
+
+	report := models.BuildSubscriptions(period, templates, charges)
+	return &report, nil
+}
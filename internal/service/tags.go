@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"spending-tracker/db"
+	"spending-tracker/models"
+)
+
+const tagSuggestionLimit = 8
+
+func (s *Service) ListTags(ctx context.Context) ([]models.Tag, error) {
+	return db.GetAllTags(ctx, householdID(ctx))
+}
+
+// SuggestTags completes the last tag in comma-separated input, returning
+// the full input for each suggestion so it can fill a datalist directly
+func (s *Service) SuggestTags(ctx context.Context, input string) ([]string, error) {
+	// Everything before the last comma is already entered; complete the rest
+	var entered []string
+	prefix := input
+	if i := strings.LastIndex(input, ","); i >= 0 {
+		entered = models.ParseTags(input[:i])
+		prefix = input[i+1:]
+	}
+
+	tags, err := db.SearchTags(ctx, householdID(ctx), models.NormalizeTagName(prefix), tagSuggestionLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	alreadyEntered := make(map[string]bool, len(entered))
+	for _, name := range entered {
+		alreadyEntered[name] = true
+	}
+
+	var suggestions []string
+	for _, t := range tags {
+		if alreadyEntered[t.Name] {
+			continue
+		}
+		suggestions = append(suggestions, strings.Join(append(entered, t.Name), ", "))
+	}
+	return suggestions, nil
+}
+
+// TagTotals sums tagged spending between two periods, inclusive
+func (s *Service) TagTotals(ctx context.Context, from, to models.Period) ([]models.TagTotal, error) {
+	if from.Index() > to.Index() {
+		return nil, invalid("range start %s is after its end %s", from, to)
+	}
+	return db.GetTagTotals(ctx, householdID(ctx), from, to)
+}
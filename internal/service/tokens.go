@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"spending-tracker/db"
+	"spending-tracker/internal/auth"
+	"spending-tracker/models"
+)
+
+// APITokenInput holds the fields used to create an API token
+type APITokenInput struct {
+	Name  string            `json:"name"`
+	Scope models.TokenScope `json:"scope"`
+	// ExpiresInDays of zero creates a token that never expires
+	ExpiresInDays int `json:"expires_in_days"`
+}
+
+func (s *Service) ListAPITokens(ctx context.Context) ([]models.APIToken, error) {
+	user := auth.UserFromContext(ctx)
+	if user == nil {
+		return nil, ErrForbidden
+	}
+	return db.GetAPITokens(ctx, user.ID)
+}
+
+// CreateAPIToken issues a token for the signed-in user. The plaintext token
+// is returned once and only its hash is stored.
+func (s *Service) CreateAPIToken(ctx context.Context, input APITokenInput) (string, *models.APIToken, error) {
+	user := auth.UserFromContext(ctx)
+	if user == nil {
+		return "", nil, ErrForbidden
+	}
+
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return "", nil, invalid("token name is required")
+	}
+	if !input.Scope.IsValid() {
+		return "", nil, invalid("unknown token scope %q", input.Scope)
+	}
+	if input.ExpiresInDays < 0 {
+		return "", nil, invalid("expiry must not be negative")
+	}
+
+	var expiresAt *time.Time
+	if input.ExpiresInDays > 0 {
+		t := time.Now().AddDate(0, 0, input.ExpiresInDays)
+		expiresAt = &t
+	}
+
+	secret, err := auth.NewToken()
+	if err != nil {
+		return "", nil, err
+	}
+	token := auth.APITokenPrefix + secret
+
+	created, err := db.CreateAPIToken(ctx, user.ID, name, auth.HashToken(token), input.Scope, expiresAt)
+	if err != nil {
+		return "", nil, err
+	}
+	return token, created, nil
+}
+
+// RevokeAPIToken deletes one of the signed-in user's tokens
+func (s *Service) RevokeAPIToken(ctx context.Context, id int64) error {
+	user := auth.UserFromContext(ctx)
+	if user == nil {
+		return ErrForbidden
+	}
+	deleted, err := db.DeleteAPIToken(ctx, user.ID, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// TokenUser returns the user and scope for an API token
+func (s *Service) TokenUser(ctx context.Context, token string) (*models.User, models.TokenScope, error) {
+	if !strings.HasPrefix(token, auth.APITokenPrefix) {
+		return nil, "", ErrNotFound
+	}
+	user, scope, err := db.GetAPITokenUser(ctx, auth.HashToken(token))
+	if err != nil {
+		return nil, "", notFound(err)
+	}
+	return user, scope, nil
+}
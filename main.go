@@ -4,7 +4,11 @@ import (
 	"context"
 	"log"
 	"spending-tracker/db"
+	"spending-tracker/internal/api"
+	"spending-tracker/internal/auth"
+	"spending-tracker/internal/events"
 	"spending-tracker/internal/handlers"
+	"spending-tracker/internal/service"
 
 	"github.com/gin-gonic/gin"
 )
@@ -24,35 +28,261 @@ func main() {
 	r := gin.Default()
 	r.Static("/static", "./static")
 
-	// Initialize handler
-	h := handlers.NewHandler()
+	// Initialize handlers; the HTML and JSON handlers share one service layer
+	hub := events.NewHub()
+	svc := service.New(hub)
+	h := handlers.NewHandler(svc, hub)
+	a := api.NewHandler(svc)
 
-	// Page routes
-	r.GET("/", h.Index)
-	r.GET("/period/:year/:month", h.Period)
+	// Public routes
 	r.GET("/health", h.Health)
+	r.GET("/login", h.LoginPage)
+	r.POST("/login", h.Login)
+	r.GET("/setup", h.SetupPage)
+	r.POST("/setup", h.Setup)
+
+	// Everything below requires a signed-in user and acts on their active household
+	app := r.Group("/",
+		auth.RequireUser(svc, h.Unauthorized),
+		auth.VerifyCSRF(h.CSRFRejected),
+		auth.RequireHousehold(svc, h.Forbidden),
+		events.TrackOrigin(),
+	)
+	// Logging out is a mutation like any other and needs the CSRF token
+	app.POST("/logout", h.Logout)
+
+	// Page routes
+	app.GET("/", h.Index)
+	app.GET("/period/:year/:month", h.Period)
+	app.GET("/search", h.SearchPage)
+	app.GET("/search/results", h.SearchResults)
+
+	// Live update routes
+	app.GET("/events", h.Events)
+	app.GET("/summary", h.GetSummary)
+	app.GET("/forecast", h.Forecast)
+	app.GET("/projection", h.ProjectionPage)
+	app.GET("/insights", h.Insights)
 
 	// Income routes
-	r.PUT("/income", h.UpdateIncome)
+	app.GET("/income", h.GetIncome)
+	app.PUT("/income", h.UpdateIncome)
+	app.PUT("/income/account", h.UpdateIncomeAccount)
 
 	// Expense routes
-	r.GET("/expenses", h.GetExpenses)
-	r.POST("/expenses", h.CreateExpense)
-	r.PUT("/expenses/:id", h.UpdateExpense)
-	r.DELETE("/expenses/:id", h.DeleteExpense)
+	app.GET("/expenses", h.GetExpenses)
+	app.POST("/expenses", h.CreateExpense)
+	app.GET("/expenses/splits/line", h.SplitLine)
+	app.GET("/expenses/duplicates", h.CheckDuplicates)
+	app.GET("/expenses/:id", h.GetExpenseRow)
+	app.GET("/expenses/:id/splits", h.SplitEditor)
+	app.GET("/expenses/:id/sharing", h.SharingEditor)
+	app.PUT("/expenses/:id/sharing", h.UpdateExpenseSharing)
+	app.GET("/expenses/:id/refunds", h.RefundEditor)
+	app.POST("/expenses/:id/refunds", h.CreateRefund)
+	app.PUT("/expenses/:id/reimbursable", h.UpdateReimbursable)
+	app.GET("/expenses/:id/loan", h.LoanEditor)
+	app.PUT("/expenses/:id/loan", h.UpdateExpenseLoan)
+	app.GET("/reimbursements", h.PendingReimbursements)
+	app.PUT("/expenses/:id", h.UpdateExpense)
+	app.DELETE("/expenses/:id", h.DeleteExpense)
 
 	// Category routes
-	r.GET("/categories", h.GetCategories)
-	r.GET("/categories/options", h.GetCategoryOptions)
-	r.POST("/categories", h.CreateCategory)
-	r.PUT("/categories/:id", h.UpdateCategory)
-	r.DELETE("/categories/:id", h.DeleteCategory)
-	r.GET("/categories/:id/edit-name", h.EditCategoryName)
-	r.GET("/categories/:id/edit-color", h.EditCategoryColor)
+	app.GET("/categories", h.GetCategories)
+	app.GET("/categories/options", h.GetCategoryOptions)
+	app.POST("/categories", h.CreateCategory)
+	app.PUT("/categories/:id", h.UpdateCategory)
+	app.PUT("/categories/:id/parent", h.UpdateCategoryParent)
+	app.PUT("/categories/:id/budget", h.UpdateCategoryBudget)
+	app.DELETE("/categories/:id", h.DeleteCategory)
+	app.GET("/categories/:id/delete", h.ConfirmDeleteCategory)
+	app.GET("/categories/:id/merge", h.ConfirmMergeCategory)
+	app.POST("/categories/:id/merge", h.MergeCategory)
+	app.PUT("/categories/:id/archive", h.ArchiveCategory)
+	app.PUT("/categories/:id/unarchive", h.UnarchiveCategory)
+	app.GET("/categories/:id/edit-name", h.EditCategoryName)
+	app.GET("/categories/:id/edit-color", h.EditCategoryColor)
+
+	// Tag routes
+	app.GET("/tags/suggestions", h.TagSuggestions)
+	app.GET("/tags/totals", h.TagTotals)
 
 	// Modal routes
-	r.GET("/modals/expense", h.ExpenseModal)
-	r.GET("/modals/category", h.CategoryModal)
+	app.GET("/modals/expense", h.ExpenseModal)
+	app.GET("/modals/category", h.CategoryModal)
+
+	// Household routes
+	app.GET("/households", h.HouseholdsPage)
+	app.POST("/households", h.CreateHousehold)
+	app.POST("/households/switch", h.SwitchHousehold)
+	app.POST("/households/rename", h.RenameHousehold)
+	app.POST("/households/members", h.AddHouseholdMember)
+	app.POST("/households/members/:user_id/role", h.UpdateHouseholdMember)
+	app.POST("/households/members/:user_id/remove", h.RemoveHouseholdMember)
+
+	// Shared expense routes
+	app.GET("/sharing", h.SharingPage)
+	app.POST("/sharing/people", h.CreatePerson)
+	app.POST("/sharing/people/:id/delete", h.DeletePerson)
+	app.POST("/sharing/settlements", h.RecordSettlement)
+	app.POST("/sharing/settlements/:id/delete", h.DeleteSettlement)
+
+	// Account routes
+	app.GET("/accounts", h.AccountsPage)
+	app.POST("/accounts", h.CreateAccount)
+	app.GET("/accounts/options", h.AccountOptions)
+	app.GET("/accounts/balances", h.AccountBalances)
+	app.POST("/accounts/transfers", h.CreateTransfer)
+	app.POST("/accounts/transfers/:id/delete", h.DeleteTransfer)
+	app.POST("/accounts/:id", h.UpdateAccount)
+	app.POST("/accounts/:id/delete", h.DeleteAccount)
+	app.GET("/accounts/:id/reconcile", h.ReconcilePage)
+	app.POST("/accounts/:id/reconcile", h.StartReconciliation)
+	app.PUT("/accounts/:id/reconcile/expenses/:expense_id", h.UpdateExpenseCleared)
+	app.POST("/accounts/:id/reconcile/complete", h.CompleteReconciliation)
+	app.POST("/expenses/:id/unlock", h.UnlockExpense)
+
+	// Savings goal routes
+	app.GET("/goals", h.GoalsPage)
+	app.POST("/goals", h.CreateGoal)
+	app.POST("/goals/contributions", h.CreateContribution)
+	app.POST("/goals/contributions/:id/delete", h.DeleteContribution)
+	app.POST("/goals/:id", h.UpdateGoal)
+	app.POST("/goals/:id/delete", h.DeleteGoal)
+
+	// Loan routes
+	app.GET("/loans", h.LoansPage)
+	app.POST("/loans", h.CreateLoan)
+	app.GET("/loans/:id", h.LoanSchedulePage)
+	app.POST("/loans/:id", h.UpdateLoan)
+	app.POST("/loans/:id/delete", h.DeleteLoan)
+
+	// Payee routes
+	app.GET("/payees", h.PayeesPage)
+	app.POST("/payees", h.CreatePayee)
+	app.GET("/payees/:id", h.PayeeDetail)
+	app.POST("/payees/:id", h.UpdatePayee)
+	app.POST("/payees/:id/delete", h.DeletePayee)
+
+	// Subscription routes
+	app.GET("/subscriptions", h.SubscriptionsPage)
+	app.POST("/subscriptions/track", h.TrackSubscription)
+
+	// Duplicate routes
+	app.GET("/duplicates", h.DuplicatesPage)
+	app.POST("/duplicates/merge", h.MergeDuplicates)
+
+	// Activity routes
+	app.GET("/activity", h.ActivityPage)
+	app.POST("/activity/:id/undo", h.UndoChange)
+
+	// Settings routes
+	app.GET("/settings", h.SettingsPage)
+	app.POST("/settings/tokens", h.CreateAPIToken)
+	app.POST("/settings/tokens/:id/revoke", h.RevokeAPIToken)
+
+	// User management
+	admin := app.Group("/users", auth.RequireAdmin(h.Forbidden))
+	admin.GET("", h.UsersPage)
+	admin.POST("", h.CreateUser)
+
+	// JSON API routes
+	// These take a POST body but only read, so read-scoped tokens may call them
+	readOnlyPosts := []string{
+		"/api/v1/expenses/duplicates",
+		"/api/v1/projection",
+	}
+	v1 := r.Group("/api/v1",
+		auth.BearerToken(svc, api.Unauthorized, api.Forbidden, readOnlyPosts...),
+		auth.RequireUser(svc, api.Unauthorized),
+		auth.VerifyCSRF(api.CSRFRejected),
+		auth.RequireHousehold(svc, api.Forbidden),
+		events.TrackOrigin(),
+	)
+	v1.GET("/openapi.json", a.OpenAPI)
+	v1.GET("/households", a.ListHouseholds)
+	v1.GET("/household", a.GetCurrentHousehold)
+	v1.GET("/periods/:year/:month", a.GetPeriod)
+	v1.GET("/periods/:year/:month/expenses", a.GetPeriodExpenses)
+	v1.GET("/periods/:year/:month/summary", a.GetSummary)
+	v1.GET("/periods/:year/:month/anomalies", a.GetAnomalies)
+	v1.GET("/forecast", a.GetForecast)
+	v1.GET("/projection", a.GetProjection)
+	v1.POST("/projection", a.PostProjection)
+	v1.GET("/periods/:year/:month/income", a.GetIncome)
+	v1.PUT("/periods/:year/:month/income", a.PutIncome)
+	v1.GET("/periods/:year/:month/accounts", a.GetAccountBalances)
+	v1.GET("/periods/:year/:month/duplicates", a.GetDuplicates)
+	v1.GET("/periods/:year/:month/subscriptions", a.GetSubscriptions)
+	v1.GET("/periods/:year/:month/contributions", a.GetContributions)
+	v1.POST("/expenses", a.CreateExpense)
+	v1.GET("/expenses/search", a.SearchExpenses)
+	v1.POST("/expenses/duplicates", a.CheckDuplicates)
+	v1.GET("/expenses/:id", a.GetExpense)
+	v1.PUT("/expenses/:id", a.UpdateExpense)
+	v1.DELETE("/expenses/:id", a.DeleteExpense)
+	v1.PUT("/expenses/:id/sharing", a.PutExpenseSharing)
+	v1.POST("/expenses/:id/refunds", a.CreateRefund)
+	v1.POST("/expenses/:id/unlock", a.UnlockExpense)
+	v1.POST("/expenses/:id/merge", a.MergeDuplicates)
+	v1.GET("/reimbursements", a.ListReimbursements)
+	v1.GET("/categories", a.ListCategories)
+	v1.POST("/categories", a.CreateCategory)
+	v1.GET("/categories/:id", a.GetCategory)
+	v1.PUT("/categories/:id", a.UpdateCategory)
+	v1.DELETE("/categories/:id", a.DeleteCategory)
+	v1.GET("/categories/:id/usage", a.GetCategoryUsage)
+	v1.POST("/categories/:id/merge", a.MergeCategory)
+	v1.POST("/categories/:id/archive", a.ArchiveCategory)
+	v1.POST("/categories/:id/unarchive", a.UnarchiveCategory)
+	v1.GET("/recurring", a.ListRecurring)
+	v1.POST("/recurring", a.CreateRecurring)
+	v1.GET("/recurring/:id", a.GetRecurring)
+	v1.PUT("/recurring/:id", a.UpdateRecurring)
+	v1.DELETE("/recurring/:id", a.DeleteRecurring)
+	v1.GET("/tags", a.ListTags)
+	v1.GET("/tags/totals", a.GetTagTotals)
+	v1.GET("/accounts", a.ListAccounts)
+	v1.POST("/accounts", a.CreateAccount)
+	v1.PUT("/accounts/:id", a.UpdateAccount)
+	v1.DELETE("/accounts/:id", a.DeleteAccount)
+	v1.GET("/accounts/:id/reconciliation", a.GetReconciliation)
+	v1.PUT("/accounts/:id/reconciliation", a.PutReconciliation)
+	v1.PUT("/accounts/:id/reconciliation/expenses/:expense_id", a.PutExpenseCleared)
+	v1.POST("/accounts/:id/reconciliation/complete", a.CompleteReconciliation)
+	v1.GET("/payees", a.ListPayees)
+	v1.POST("/payees", a.CreatePayee)
+	v1.GET("/payees/totals", a.GetPayeeTotals)
+	v1.PUT("/payees/:id", a.UpdatePayee)
+	v1.DELETE("/payees/:id", a.DeletePayee)
+	v1.GET("/payees/:id/history", a.GetPayeeHistory)
+	v1.GET("/goals", a.ListGoals)
+	v1.POST("/goals", a.CreateGoal)
+	v1.GET("/goals/:id", a.GetGoal)
+	v1.PUT("/goals/:id", a.UpdateGoal)
+	v1.DELETE("/goals/:id", a.DeleteGoal)
+	v1.POST("/contributions", a.CreateContribution)
+	v1.DELETE("/contributions/:id", a.DeleteContribution)
+	v1.GET("/loans", a.ListLoans)
+	v1.POST("/loans", a.CreateLoan)
+	v1.GET("/loans/plans", a.GetPayoffPlans)
+	v1.GET("/loans/:id", a.GetLoan)
+	v1.PUT("/loans/:id", a.UpdateLoan)
+	v1.DELETE("/loans/:id", a.DeleteLoan)
+	v1.GET("/loans/:id/schedule", a.GetLoanSchedule)
+	v1.PUT("/expenses/:id/loan", a.PutExpenseLoan)
+	v1.GET("/transfers", a.ListTransfers)
+	v1.POST("/transfers", a.CreateTransfer)
+	v1.DELETE("/transfers/:id", a.DeleteTransfer)
+	v1.GET("/people", a.ListPeople)
+	v1.POST("/people", a.CreatePerson)
+	v1.DELETE("/people/:id", a.DeletePerson)
+	v1.GET("/balances", a.GetBalances)
+	v1.GET("/settlements", a.ListSettlements)
+	v1.POST("/settlements", a.CreateSettlement)
+	v1.DELETE("/settlements/:id", a.DeleteSettlement)
+	v1.GET("/activity", a.ListActivity)
+	v1.POST("/activity/:id/undo", a.UndoChange)
 
 	r.Run(":8080")
 }
@@ -0,0 +1,85 @@
+package models
+
+import "time"
+
+type AccountType string
+
+const (
+	AccountCurrent    AccountType = "current"
+	AccountCreditCard AccountType = "credit_card"
+	AccountCash       AccountType = "cash"
+	AccountSavings    AccountType = "savings"
+)
+
+// AccountTypes lists the account types in the order they are offered
+var AccountTypes = []AccountType{AccountCurrent, AccountCreditCard, AccountCash, AccountSavings}
+
+func (t AccountType) IsValid() bool {
+	switch t {
+	case AccountCurrent, AccountCreditCard, AccountCash, AccountSavings:
+		return true
+	}
+	return false
+}
+
+func (t AccountType) Label() string {
+	switch t {
+	case AccountCurrent:
+		return "Current account"
+	case AccountCreditCard:
+		return "Credit card"
+	case AccountCash:
+		return "Cash"
+	case AccountSavings:
+		return "Savings"
+	}
+	return string(t)
+}
+
+// Account is somewhere money is held. A credit card's balance is negative
+// while money is owed on it.
+type Account struct {
+	ID             int64       `json:"id"`
+	Name           string      `json:"name"`
+	Type           AccountType `json:"account_type"`
+	OpeningBalance float64     `json:"opening_balance"`
+	CreatedAt      time.Time   `json:"created_at"`
+}
+
+// Transfer moves money between two accounts in a period. Transfers are not
+// spending and never count towards the summary.
+type Transfer struct {
+	ID          int64     `json:"id"`
+	FromAccount Account   `json:"from"`
+	ToAccount   Account   `json:"to"`
+	Amount      float64   `json:"amount"`
+	Year        int       `json:"year"`
+	Month       int       `json:"month"`
+	Note        string    `json:"note"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// AccountBalance is an account's running balance over one period: its
+// balance going in, what moved through it, and its balance coming out
+type AccountBalance struct {
+	Account      Account `json:"account"`
+	Opening      float64 `json:"opening"`
+	Income       float64 `json:"income"`
+	Spent        float64 `json:"spent"`
+	TransfersIn  float64 `json:"transfers_in"`
+	TransfersOut float64 `json:"transfers_out"`
+	Closing      float64 `json:"closing"`
+}
+
+// NewAccountBalance works out the closing balance from the period's movements
+func NewAccountBalance(account Account, opening, income, spent, transfersIn, transfersOut float64) AccountBalance {
+	return AccountBalance{
+		Account:      account,
+		Opening:      opening,
+		Income:       income,
+		Spent:        spent,
+		TransfersIn:  transfersIn,
+		TransfersOut: transfersOut,
+		Closing:      opening + income - spent + transfersIn - transfersOut,
+	}
+}
@@ -0,0 +1,64 @@
+package models
+
+import "fmt"
+
+// AnomalyKind names the way spending looks unusual
+type AnomalyKind string
+
+const (
+	// AnomalyCategorySpike is a category's spending in a period far above
+	// its trailing average
+	AnomalyCategorySpike AnomalyKind = "category_spike"
+	// AnomalyLargeExpense is a single expense much larger than usual for its
+	// payee or category
+	AnomalyLargeExpense AnomalyKind = "large_expense"
+	// AnomalyRecurringChange is a recurring charge for a different amount
+	// than the last one
+	AnomalyRecurringChange AnomalyKind = "recurring_change"
+)
+
+func (k AnomalyKind) Label() string {
+	switch k {
+	case AnomalyCategorySpike:
+		return "Category spike"
+	case AnomalyLargeExpense:
+		return "Unusually large"
+	case AnomalyRecurringChange:
+		return "Amount changed"
+	}
+	return string(k)
+}
+
+// Anomaly is something unusual in a period's spending. Amount is what was
+// spent and Usual what would normally be expected: the trailing average for
+// a category, the typical expense for a payee or category, or the previous
+// charge of a recurring expense.
+type Anomaly struct {
+	Kind AnomalyKind `json:"kind"`
+	// ExpenseID is the expense flagged, for all but category spikes
+	ExpenseID  *int64 `json:"expense_id,omitempty"`
+	CategoryID *int64 `json:"category_id,omitempty"`
+	// Subject is the category or expense the anomaly is about
+	Subject string  `json:"subject"`
+	Amount  float64 `json:"amount"`
+	Usual   float64 `json:"usual"`
+	// Basis says what Usual was worked out from
+	Basis string `json:"basis"`
+}
+
+// Message describes the anomaly in a sentence
+func (a Anomaly) Message() string {
+	switch a.Kind {
+	case AnomalyCategorySpike:
+		return fmt.Sprintf("£%.2f spent on %s, against £%.2f a month %s", a.Amount, a.Subject, a.Usual, a.Basis)
+	case AnomalyLargeExpense:
+		return fmt.Sprintf("£%.2f for %s, against a usual £%.2f %s", a.Amount, a.Subject, a.Usual, a.Basis)
+	case AnomalyRecurringChange:
+		direction := "up"
+		if a.Amount < a.Usual {
+			direction = "down"
+		}
+		return fmt.Sprintf("%s charged £%.2f, %s from £%.2f %s", a.Subject, a.Amount, direction, a.Usual, a.Basis)
+	}
+	return a.Subject
+}
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// TokenScope limits what an API token may do
+type TokenScope string
+
+const (
+	// TokenScopeRead allows only GET requests
+	TokenScopeRead TokenScope = "read"
+	// TokenScopeWrite allows every request the user's household role allows
+	TokenScopeWrite TokenScope = "write"
+)
+
+func (s TokenScope) IsValid() bool {
+	return s == TokenScopeRead || s == TokenScopeWrite
+}
+
+// APIToken is a named personal access token for the JSON API
+type APIToken struct {
+	ID         int64      `json:"id"`
+	UserID     int64      `json:"user_id"`
+	Name       string     `json:"name"`
+	Scope      TokenScope `json:"scope"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+func (t APIToken) IsExpired() bool {
+	return t.ExpiresAt != nil && !t.ExpiresAt.After(time.Now())
+}
@@ -1,18 +1,45 @@
 package models
 
+import "strings"
+
 type ExpenseFilter string
 
 const (
 	FilterAll       ExpenseFilter = "all"
 	FilterRecurring ExpenseFilter = "recurring"
 	FilterOneTime   ExpenseFilter = "one_time"
+
+	tagFilterPrefix = "tag:"
 )
 
+// TagFilter returns the filter matching expenses carrying the given tag
+func TagFilter(name string) ExpenseFilter {
+	return ExpenseFilter(tagFilterPrefix + name)
+}
+
+// Tag returns the tag name for a tag filter
+func (f ExpenseFilter) Tag() (string, bool) {
+	if !strings.HasPrefix(string(f), tagFilterPrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(string(f), tagFilterPrefix), true
+}
+
 type AppState struct {
+	Household  Membership
+	Households []Membership
 	Period     Period
 	Income     float64
 	Expenses   []Expense
 	Categories []Category
+	Tags       []Tag
 	Summary    Summary
 	Filter     ExpenseFilter
+	// Person is the signed-in user's person in the household; when set, the
+	// summary counts their share of shared expenses rather than the gross amount
+	Person *Person
+	// Accounts are the household's accounts; IncomeAccountID is the one the
+	// period's income was paid into
+	Accounts        []Account
+	IncomeAccountID *int64
 }
@@ -0,0 +1,103 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// AuditEntity names the kind of record an audit entry describes
+type AuditEntity string
+
+const (
+	AuditEntityExpense   AuditEntity = "expense"
+	AuditEntityIncome    AuditEntity = "income"
+	AuditEntityCategory  AuditEntity = "category"
+	AuditEntityRecurring AuditEntity = "recurring"
+)
+
+// AuditAction is what happened to the record
+type AuditAction string
+
+const (
+	AuditActionCreate  AuditAction = "create"
+	AuditActionUpdate  AuditAction = "update"
+	AuditActionDelete  AuditAction = "delete"
+	AuditActionRestore AuditAction = "restore"
+)
+
+// AuditEntry records one change with snapshots of the record before and after
+type AuditEntry struct {
+	ID         int64           `json:"id"`
+	UserID     *int64          `json:"user_id"`
+	Username   *string         `json:"username"`
+	EntityType AuditEntity     `json:"entity_type"`
+	EntityID   *int64          `json:"entity_id"`
+	Action     AuditAction     `json:"action"`
+	Before     json.RawMessage `json:"before"`
+	After      json.RawMessage `json:"after"`
+	UndoneAt   *time.Time      `json:"undone_at"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// CanUndo reports whether the entry is a delete that can still be reversed.
+// Merges record the target in After and cannot be undone.
+func (e AuditEntry) CanUndo() bool {
+	return e.Action == AuditActionDelete && e.UndoneAt == nil && len(e.After) == 0 && len(e.Before) > 0
+}
+
+// Actor returns who made the change
+func (e AuditEntry) Actor() string {
+	if e.Username == nil {
+		return "Deleted user"
+	}
+	return *e.Username
+}
+
+// Label names the affected record, e.g. an expense description or category name
+func (e AuditEntry) Label() string {
+	snapshot := e.After
+	if e.Action == AuditActionDelete || len(snapshot) == 0 {
+		snapshot = e.Before
+	}
+
+	var fields struct {
+		Description string `json:"description"`
+		Name        string `json:"name"`
+		Year        int    `json:"year"`
+		Month       int    `json:"month"`
+	}
+	json.Unmarshal(snapshot, &fields)
+
+	switch {
+	case fields.Description != "":
+		return fields.Description
+	case fields.Name != "":
+		return fields.Name
+	case e.EntityType == AuditEntityIncome && fields.Year != 0:
+		return Period{Year: fields.Year, Month: fields.Month}.String()
+	}
+	return ""
+}
+
+// Summary describes the change in a sentence for the activity feed
+func (e AuditEntry) Summary() string {
+	verb := map[AuditAction]string{
+		AuditActionCreate:  "Created",
+		AuditActionUpdate:  "Updated",
+		AuditActionDelete:  "Deleted",
+		AuditActionRestore: "Restored",
+	}[e.Action]
+
+	noun := map[AuditEntity]string{
+		AuditEntityExpense:   "expense",
+		AuditEntityIncome:    "income for",
+		AuditEntityCategory:  "category",
+		AuditEntityRecurring: "recurring template",
+	}[e.EntityType]
+
+	if label := e.Label(); label != "" {
+		return fmt.Sprintf("%s %s %q", verb, noun, label)
+	}
+	return fmt.Sprintf("%s %s", verb, noun)
+}
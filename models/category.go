@@ -2,11 +2,28 @@ package models
 
 import "time"
 
+// CategoryColors lists the Tailwind color names a category may use
+var CategoryColors = []string{"blue", "purple", "green", "orange", "pink", "red", "yellow", "gray"}
+
 type Category struct {
-	ID        int64     `json:"id"`
-	Name      string    `json:"name"`
-	Color     string    `json:"color"`
-	CreatedAt time.Time `json:"created_at"`
+	ID         int64      `json:"id"`
+	Name       string     `json:"name"`
+	Color      string     `json:"color"`
+	ParentID   *int64     `json:"parent_id,omitempty"`
+	Budget     *float64   `json:"budget,omitempty"`
+	Children   []Category `json:"children,omitempty"`
+	ArchivedAt *time.Time `json:"archived_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CategoryUsage counts the rows that reference a category
+type CategoryUsage struct {
+	Expenses           int `json:"expenses"`
+	RecurringTemplates int `json:"recurring_templates"`
+}
+
+func (u CategoryUsage) Total() int {
+	return u.Expenses + u.RecurringTemplates
 }
 
 func (c Category) BgClass() string {
@@ -20,3 +37,70 @@ func (c Category) TextClass() string {
 func (c Category) DotClass() string {
 	return c.Color + "-500"
 }
+
+func (c Category) IsTopLevel() bool {
+	return c.ParentID == nil
+}
+
+func (c Category) IsArchived() bool {
+	return c.ArchivedAt != nil
+}
+
+// BuildCategoryTree nests subcategories under their parents, preserving the
+// input order. Children whose parent is missing are promoted to the top level.
+func BuildCategoryTree(categories []Category) []Category {
+	children := make(map[int64][]Category)
+	ids := make(map[int64]bool, len(categories))
+	for _, c := range categories {
+		ids[c.ID] = true
+	}
+	for _, c := range categories {
+		if c.ParentID != nil && ids[*c.ParentID] {
+			children[*c.ParentID] = append(children[*c.ParentID], c)
+		}
+	}
+
+	tree := make([]Category, 0, len(categories))
+	for _, c := range categories {
+		if c.ParentID != nil && ids[*c.ParentID] {
+			continue
+		}
+		c.Children = children[c.ID]
+		tree = append(tree, c)
+	}
+	return tree
+}
+
+// TopLevelCategories returns the categories that can act as a parent
+func TopLevelCategories(categories []Category) []Category {
+	var parents []Category
+	for _, c := range categories {
+		if c.IsTopLevel() {
+			parents = append(parents, c)
+		}
+	}
+	return parents
+}
+
+// ActiveCategories drops archived categories, keeping keepID (if set) so an
+// existing selection still renders
+func ActiveCategories(categories []Category, keepID *int64) []Category {
+	var active []Category
+	for _, c := range categories {
+		if !c.IsArchived() || (keepID != nil && *keepID == c.ID) {
+			active = append(active, c)
+		}
+	}
+	return active
+}
+
+// ArchivedCategories returns only the archived categories
+func ArchivedCategories(categories []Category) []Category {
+	var archived []Category
+	for _, c := range categories {
+		if c.IsArchived() {
+			archived = append(archived, c)
+		}
+	}
+	return archived
+}
@@ -0,0 +1,108 @@
+package models
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// DuplicateSimilarity is how alike, from 0 to 1, the descriptions of two
+// expenses with the same amount must be for one to be a suspected duplicate
+const DuplicateSimilarity = 0.8
+
+// DuplicateWindow is how recently an expense in another period must have been
+// added to be checked against a new one. Expenses in the same period are
+// always checked.
+const DuplicateWindow = 3 * 24 * time.Hour
+
+// DuplicateGroup is an expense and the later expenses that look like
+// duplicates of it
+type DuplicateGroup struct {
+	Original   Expense   `json:"original"`
+	Duplicates []Expense `json:"duplicates"`
+}
+
+// NormalizeDescription reduces a description to lower-case words so that
+// case, punctuation and spacing do not hide a duplicate
+func NormalizeDescription(description string) string {
+	words := strings.FieldsFunc(strings.ToLower(description), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, " ")
+}
+
+// DescriptionSimilarity compares two descriptions after normalizing them,
+// from 0 for nothing in common to 1 for the same
+func DescriptionSimilarity(a, b string) float64 {
+	ra := []rune(NormalizeDescription(a))
+	rb := []rune(NormalizeDescription(b))
+	longest := max(len(ra), len(rb))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(editDistance(ra, rb))/float64(longest)
+}
+
+// editDistance is the Levenshtein distance between two strings
+func editDistance(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// IsDuplicateOf reports whether the expense looks like another entry of the
+// same spending: the same amount with a similar description
+func (e Expense) IsDuplicateOf(other Expense) bool {
+	if e.ID != 0 && e.ID == other.ID {
+		return false
+	}
+	if math.Round(e.Amount*100) != math.Round(other.Amount*100) {
+		return false
+	}
+	return DescriptionSimilarity(e.Description, other.Description) >= DuplicateSimilarity
+}
+
+// FindDuplicates groups expenses that look like duplicates of an earlier one.
+// Each expense appears in at most one group.
+func FindDuplicates(expenses []Expense) []DuplicateGroup {
+	sorted := make([]Expense, len(expenses))
+	copy(sorted, expenses)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].CreatedAt.Before(sorted[j].CreatedAt)
+	})
+
+	grouped := make([]bool, len(sorted))
+	var groups []DuplicateGroup
+	for i, original := range sorted {
+		if grouped[i] {
+			continue
+		}
+		group := DuplicateGroup{Original: original}
+		for j := i + 1; j < len(sorted); j++ {
+			if !grouped[j] && sorted[j].IsDuplicateOf(original) {
+				group.Duplicates = append(group.Duplicates, sorted[j])
+				grouped[j] = true
+			}
+		}
+		if len(group.Duplicates) > 0 {
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
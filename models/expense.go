@@ -1,6 +1,9 @@
 package models
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 type ExpenseType string
 
@@ -10,19 +13,73 @@ const (
 )
 
 type Expense struct {
-	ID                 int64       `json:"id"`
-	Description        string      `json:"description"`
-	Amount             float64     `json:"amount"`
-	CategoryID         *int64      `json:"category_id"`
-	Category           *Category   `json:"category,omitempty"`
-	Type               ExpenseType `json:"expense_type"`
-	Year               int         `json:"year"`
-	Month              int         `json:"month"`
-	RecurringExpenseID *int64      `json:"recurring_expense_id,omitempty"`
-	CreatedAt          time.Time   `json:"created_at"`
-	UpdatedAt          time.Time   `json:"updated_at"`
+	ID                  int64          `json:"id"`
+	Description         string         `json:"description"`
+	Amount              float64        `json:"amount"`
+	CategoryID          *int64         `json:"category_id"`
+	Category            *Category      `json:"category,omitempty"`
+	Type                ExpenseType    `json:"expense_type"`
+	Year                int            `json:"year"`
+	Month               int            `json:"month"`
+	RecurringExpenseID  *int64         `json:"recurring_expense_id,omitempty"`
+	Tags                []Tag          `json:"tags,omitempty"`
+	Splits              []ExpenseSplit `json:"splits,omitempty"`
+	PaidBy              *int64         `json:"paid_by,omitempty"`
+	PaidByName          string         `json:"paid_by_name,omitempty"`
+	ShareMethod         ShareMethod    `json:"share_method,omitempty"`
+	Shares              []ExpenseShare `json:"shares,omitempty"`
+	RefundOf            *int64         `json:"refund_of,omitempty"`
+	RefundOfDescription string         `json:"refund_of_description,omitempty"`
+	ReimbursableFrom    string         `json:"reimbursable_from,omitempty"`
+	Refunded            float64        `json:"refunded"`
+	AccountID           *int64         `json:"account_id"`
+	AccountName         string         `json:"account_name,omitempty"`
+	Cleared             bool           `json:"cleared"`
+	ReconciledAt        *time.Time     `json:"reconciled_at,omitempty"`
+	PayeeID             *int64         `json:"payee_id"`
+	PayeeName           string         `json:"payee_name,omitempty"`
+	LoanID              *int64         `json:"loan_id"`
+	LoanName            string         `json:"loan_name,omitempty"`
+	Version             int            `json:"version"`
+	CreatedAt           time.Time      `json:"created_at"`
+	UpdatedAt           time.Time      `json:"updated_at"`
+	// Anomalies flags what is unusual about the expense, when loaded with
+	// its period
+	Anomalies []Anomaly `json:"anomalies,omitempty"`
+}
+
+// ExpenseSplit is one line of an expense split across categories
+type ExpenseSplit struct {
+	ID         int64     `json:"id"`
+	CategoryID *int64    `json:"category_id"`
+	Category   *Category `json:"category,omitempty"`
+	Amount     float64   `json:"amount"`
+	Note       string    `json:"note"`
+}
+
+// IsSplit reports whether the expense is divided into split lines
+func (e Expense) IsSplit() bool {
+	return len(e.Splits) > 0
+}
+
+// Allocations returns how the expense's amount is attributed to categories:
+// its split lines, or the whole amount to its own category when not split
+func (e Expense) Allocations() []ExpenseSplit {
+	if e.IsSplit() {
+		return e.Splits
+	}
+	return []ExpenseSplit{{CategoryID: e.CategoryID, Category: e.Category, Amount: e.Amount}}
 }
 
 func (e Expense) IsRecurring() bool {
 	return e.Type == ExpenseTypeRecurring
 }
+
+// TagNames returns the expense's tags as comma-separated input
+func (e Expense) TagNames() string {
+	names := make([]string, len(e.Tags))
+	for i, t := range e.Tags {
+		names[i] = t.Name
+	}
+	return strings.Join(names, ", ")
+}
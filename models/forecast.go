@@ -0,0 +1,135 @@
+package models
+
+import "math"
+
+// ForecastYears is how many earlier years of the same month a forecast
+// compares the month-to-date spending against
+const ForecastYears = 3
+
+// ForecastMargin is the least uncertainty either side of the one-time
+// spending still to come, as a fraction of it
+const ForecastMargin = 0.25
+
+// PendingCharge is an active recurring expense not yet charged in a period
+type PendingCharge struct {
+	RecurringExpenseID int64   `json:"recurring_expense_id"`
+	Description        string  `json:"description"`
+	Amount             float64 `json:"amount"`
+}
+
+// PastMonth is what was spent on one-time expenses in the same month of an
+// earlier year
+type PastMonth struct {
+	Year    int     `json:"year"`
+	OneTime float64 `json:"one_time"`
+}
+
+// Forecast projects a period's spending to the end of the month from the
+// spending so far, recurring expenses still to be charged and the same
+// month in earlier years
+type Forecast struct {
+	Period Period `json:"period"`
+	// Day is the day of the month the forecast is made on
+	Day          int     `json:"day"`
+	DaysInMonth  int     `json:"days_in_month"`
+	Income       float64 `json:"income"`
+	SavedToGoals float64 `json:"saved_to_goals"`
+	Spent        float64 `json:"spent"`
+	SpentOneTime float64 `json:"spent_one_time"`
+	// DailyRate is the one-time spending per day so far this month
+	DailyRate float64         `json:"daily_rate"`
+	Pending   []PendingCharge `json:"pending"`
+	// PendingRecurring is the total of the recurring expenses still to come
+	PendingRecurring float64     `json:"pending_recurring"`
+	PastYears        []PastMonth `json:"past_years"`
+	// ProjectedSpending is the month's expected total spending, likely
+	// between LowSpending and HighSpending
+	ProjectedSpending float64 `json:"projected_spending"`
+	LowSpending       float64 `json:"low_spending"`
+	HighSpending      float64 `json:"high_spending"`
+	// ProjectedRemaining is what is expected to be left over at month end,
+	// after savings toward goals, likely between RemainingLow and
+	// RemainingHigh
+	ProjectedRemaining float64 `json:"projected_remaining"`
+	RemainingLow       float64 `json:"remaining_low"`
+	RemainingHigh      float64 `json:"remaining_high"`
+}
+
+// DaysLeft is the number of days after the forecast's day
+func (f Forecast) DaysLeft() int {
+	return f.DaysInMonth - f.Day
+}
+
+// OneTimeTotal sums the one-time expenses, refunds included
+func OneTimeTotal(expenses []Expense) float64 {
+	var total float64
+	for _, e := range expenses {
+		if e.Type == ExpenseTypeOneTime {
+			total += e.Amount
+		}
+	}
+	return total
+}
+
+// BuildForecast projects a period's spending from its expenses so far on
+// the given day of the month. One-time spending is projected at the
+// month-to-date daily rate, blended toward the average of the same month
+// in earlier years the less of the month has passed. The band spans the
+// month-to-date and earlier-year estimates, the latter drawn in toward the
+// projection as the month passes, and is never narrower than
+// ForecastMargin of the one-time spending still to come. Recurring expenses
+// not yet charged are added in full.
+func BuildForecast(period Period, day int, income, savedToGoals float64, expenses []Expense, pending []PendingCharge, past []PastMonth) Forecast {
+	days := period.DaysInMonth()
+	day = max(1, min(day, days))
+	f := Forecast{
+		Period:       period,
+		Day:          day,
+		DaysInMonth:  days,
+		Income:       income,
+		SavedToGoals: savedToGoals,
+		Pending:      pending,
+		PastYears:    past,
+	}
+	for _, e := range expenses {
+		f.Spent += e.Amount
+	}
+	f.SpentOneTime = OneTimeTotal(expenses)
+	for _, p := range pending {
+		f.PendingRecurring += p.Amount
+	}
+
+	f.DailyRate = math.Max(f.SpentOneTime, 0) / float64(day)
+	paced := f.SpentOneTime + f.DailyRate*float64(f.DaysLeft())
+	oneTime, low, high := paced, paced, paced
+	if len(past) > 0 {
+		var sum float64
+		totals := make([]float64, len(past))
+		for i, p := range past {
+			// Earlier months cannot have less to come than is already spent
+			totals[i] = math.Max(p.OneTime, f.SpentOneTime)
+			sum += totals[i]
+		}
+		elapsed := float64(day) / float64(days)
+		oneTime = elapsed*paced + (1-elapsed)*sum/float64(len(past))
+		// Earlier years widen the band less the more of the month is known
+		for _, total := range totals {
+			estimate := oneTime + (1-elapsed)*(total-oneTime)
+			low, high = math.Min(low, estimate), math.Max(high, estimate)
+		}
+	}
+	toCome := oneTime - f.SpentOneTime
+	low = math.Min(low, oneTime-toCome*ForecastMargin)
+	high = math.Max(high, oneTime+toCome*ForecastMargin)
+
+	recurring := f.Spent - f.SpentOneTime + f.PendingRecurring
+	f.ProjectedSpending = roundCents(recurring + oneTime)
+	f.LowSpending = roundCents(recurring + low)
+	f.HighSpending = roundCents(recurring + high)
+
+	available := income - savedToGoals
+	f.ProjectedRemaining = roundCents(available - f.ProjectedSpending)
+	f.RemainingLow = roundCents(available - f.HighSpending)
+	f.RemainingHigh = roundCents(available - f.LowSpending)
+	return f
+}
@@ -0,0 +1,106 @@
+package models
+
+import "testing"
+
+func TestBuildForecast(t *testing.T) {
+	april := Period{Year: 2026, Month: 4}
+	oneTime := func(amount float64) Expense {
+		return Expense{Type: ExpenseTypeOneTime, Amount: amount}
+	}
+
+	tests := []struct {
+		name         string
+		day          int
+		income       float64
+		savedToGoals float64
+		expenses     []Expense
+		pending      []PendingCharge
+		past         []PastMonth
+		wantDay      int
+		wantSpending [3]float64 // projected, low, high
+		wantLeft     [3]float64 // projected, low, high
+	}{
+		{
+			name:   "first day leans on earlier years",
+			day:    1,
+			income: 2000, savedToGoals: 100,
+			expenses:     []Expense{oneTime(30)},
+			past:         []PastMonth{{Year: 2025, OneTime: 600}, {Year: 2024, OneTime: 900}},
+			wantDay:      1,
+			wantSpending: [3]float64{755, 573.75, 936.25},
+			wantLeft:     [3]float64{1145, 963.75, 1326.25},
+		},
+		{
+			name:         "last day is what has been spent",
+			day:          30,
+			income:       1000,
+			expenses:     []Expense{oneTime(500)},
+			past:         []PastMonth{{Year: 2025, OneTime: 800}},
+			wantDay:      30,
+			wantSpending: [3]float64{500, 500, 500},
+			wantLeft:     [3]float64{500, 500, 500},
+		},
+		{
+			name:         "earlier years below the spending so far count as the spending so far",
+			day:          15,
+			income:       1000,
+			expenses:     []Expense{oneTime(400)},
+			past:         []PastMonth{{Year: 2025, OneTime: 100}},
+			wantDay:      15,
+			wantSpending: [3]float64{600, 500, 800},
+			wantLeft:     [3]float64{400, 200, 500},
+		},
+		{
+			name:         "without earlier years the daily rate is kept, with the margin around it",
+			day:          10,
+			income:       1000,
+			expenses:     []Expense{{Type: ExpenseTypeRecurring, Amount: 200}, oneTime(100)},
+			pending:      []PendingCharge{{RecurringExpenseID: 1, Amount: 50}},
+			wantDay:      10,
+			wantSpending: [3]float64{550, 500, 600},
+			wantLeft:     [3]float64{450, 400, 500},
+		},
+		{
+			name:         "refunds so far are kept but add no daily rate",
+			day:          10,
+			income:       1000,
+			expenses:     []Expense{oneTime(-20)},
+			wantDay:      10,
+			wantSpending: [3]float64{-20, -20, -20},
+			wantLeft:     [3]float64{1020, 1020, 1020},
+		},
+		{
+			name:         "day before the month starts is the first",
+			day:          0,
+			income:       100,
+			wantDay:      1,
+			wantSpending: [3]float64{0, 0, 0},
+			wantLeft:     [3]float64{100, 100, 100},
+		},
+		{
+			name:         "day after the month ends is the last",
+			day:          31,
+			income:       100,
+			wantDay:      30,
+			wantSpending: [3]float64{0, 0, 0},
+			wantLeft:     [3]float64{100, 100, 100},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := BuildForecast(april, tt.day, tt.income, tt.savedToGoals, tt.expenses, tt.pending, tt.past)
+			if f.Day != tt.wantDay {
+				t.Errorf("day = %d, want %d", f.Day, tt.wantDay)
+			}
+			spending := [3]float64{f.ProjectedSpending, f.LowSpending, f.HighSpending}
+			if spending != tt.wantSpending {
+				t.Errorf("spending (projected, low, high) = %v, want %v", spending, tt.wantSpending)
+			}
+			left := [3]float64{f.ProjectedRemaining, f.RemainingLow, f.RemainingHigh}
+			if left != tt.wantLeft {
+				t.Errorf("remaining (projected, low, high) = %v, want %v", left, tt.wantLeft)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"math"
+	"time"
+)
+
+// SavingsGoal is something the household is saving toward by a target date
+type SavingsGoal struct {
+	ID           int64     `json:"id"`
+	Name         string    `json:"name"`
+	TargetAmount float64   `json:"target_amount"`
+	TargetDate   time.Time `json:"target_date"`
+	// AccountID is where the goal's money is kept, if anywhere in particular
+	AccountID   *int64 `json:"account_id"`
+	AccountName string `json:"account_name,omitempty"`
+	// Saved is the total of all contributions toward the goal
+	Saved float64 `json:"saved"`
+	// RequiredMonthly is what must be put toward the goal each month, from
+	// the current one, to reach the target by the target date
+	RequiredMonthly float64   `json:"required_monthly"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// TargetPeriod is the month the goal should be reached in
+func (g SavingsGoal) TargetPeriod() Period {
+	return Period{Year: g.TargetDate.Year(), Month: int(g.TargetDate.Month())}
+}
+
+// Remaining is what is still to be saved, never less than zero
+func (g SavingsGoal) Remaining() float64 {
+	return math.Max(g.TargetAmount-g.Saved, 0)
+}
+
+// Progress returns the share of the target saved as a percentage, up to 100
+func (g SavingsGoal) Progress() float64 {
+	if g.TargetAmount <= 0 {
+		return 0
+	}
+	return math.Min(g.Saved/g.TargetAmount*100, 100)
+}
+
+func (g SavingsGoal) IsComplete() bool {
+	return math.Round(g.Saved*100) >= math.Round(g.TargetAmount*100)
+}
+
+// MonthsLeft counts the months from a period up to and including the target
+// period, or zero once the target period has passed
+func (g SavingsGoal) MonthsLeft(from Period) int {
+	return max(g.TargetPeriod().Index()-from.Index()+1, 0)
+}
+
+// RequiredMonthlyFrom is what must be put toward the goal each month from a
+// period to reach the target on time. Once the target period has passed,
+// everything still to be saved is due at once.
+func (g SavingsGoal) RequiredMonthlyFrom(from Period) float64 {
+	months := g.MonthsLeft(from)
+	if months == 0 {
+		return g.Remaining()
+	}
+	return math.Ceil(g.Remaining()/float64(months)*100) / 100
+}
+
+// GoalContribution is money put toward a goal in a period. Contributions are
+// saving rather than spending, so they are kept apart from expenses; a
+// negative one takes money back out of the goal.
+type GoalContribution struct {
+	ID        int64     `json:"id"`
+	GoalID    int64     `json:"goal_id"`
+	GoalName  string    `json:"goal_name"`
+	Amount    float64   `json:"amount"`
+	Year      int       `json:"year"`
+	Month     int       `json:"month"`
+	Note      string    `json:"note"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// Role is a member's level of access to a household
+type Role string
+
+const (
+	RoleOwner  Role = "owner"
+	RoleEditor Role = "editor"
+	RoleViewer Role = "viewer"
+)
+
+// Roles lists every role, most privileged first
+var Roles = []Role{RoleOwner, RoleEditor, RoleViewer}
+
+func (r Role) IsValid() bool {
+	return r == RoleOwner || r == RoleEditor || r == RoleViewer
+}
+
+// CanEdit reports whether the role may change budget data
+func (r Role) CanEdit() bool {
+	return r == RoleOwner || r == RoleEditor
+}
+
+// CanManage reports whether the role may rename the household and manage members
+func (r Role) CanManage() bool {
+	return r == RoleOwner
+}
+
+// Household owns a budget: its categories, income, expenses and templates
+type Household struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Membership is a household as seen by one of its members
+type Membership struct {
+	Household Household `json:"household"`
+	Role      Role      `json:"role"`
+}
+
+// Member is a user belonging to a household
+type Member struct {
+	UserID   int64     `json:"user_id"`
+	Username string    `json:"username"`
+	Role     Role      `json:"role"`
+	JoinedAt time.Time `json:"joined_at"`
+}
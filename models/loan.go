@@ -0,0 +1,265 @@
+package models
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// MaxPayoffMonths is how far ahead schedules and payoff plans are worked
+// out; a loan not paid off by then is treated as never paid off
+const MaxPayoffMonths = 600
+
+// Loan is a loan or credit card being paid off. Interest accrues monthly at
+// APR / 12 on what is owed.
+type Loan struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	// Principal is what was owed at the start of the start period
+	Principal      float64 `json:"principal"`
+	APR            float64 `json:"apr"`
+	MinimumPayment float64 `json:"minimum_payment"`
+	StartYear      int     `json:"start_year"`
+	StartMonth     int     `json:"start_month"`
+	// RecurringExpenseID is the recurring expense the regular repayments
+	// are made by, if any
+	RecurringExpenseID   *int64  `json:"recurring_expense_id"`
+	RecurringDescription string  `json:"recurring_description,omitempty"`
+	RecurringAmount      float64 `json:"-"`
+	// Balance is what is owed now, after the payments made so far
+	Balance float64 `json:"balance"`
+	// MonthlyPayment is the regular repayment: the recurring expense's
+	// amount, but never less than the minimum payment
+	MonthlyPayment float64   `json:"monthly_payment"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+func (l Loan) StartPeriod() Period {
+	return Period{Year: l.StartYear, Month: l.StartMonth}
+}
+
+// MonthlyRate is the interest charged each month as a fraction
+func (l Loan) MonthlyRate() float64 {
+	return l.APR / 100 / 12
+}
+
+// ScheduledPayment is the regular repayment the loan is paid off by
+func (l Loan) ScheduledPayment() float64 {
+	if l.RecurringExpenseID == nil {
+		return l.MinimumPayment
+	}
+	return math.Max(l.RecurringAmount, l.MinimumPayment)
+}
+
+// LoanPayment is what was paid toward a loan in one period: by its
+// recurring expense, and in extra payments on top
+type LoanPayment struct {
+	Year    int     `json:"year"`
+	Month   int     `json:"month"`
+	Regular float64 `json:"regular"`
+	Extra   float64 `json:"extra"`
+}
+
+// BalanceAt works out what is owed at the end of a period from the payments
+// made since the start period. Without a linked recurring expense the
+// minimum payment is taken to have been made each month.
+func (l Loan) BalanceAt(asOf Period, payments []LoanPayment) float64 {
+	paid := make(map[int]LoanPayment)
+	for _, p := range payments {
+		paid[Period{Year: p.Year, Month: p.Month}.Index()] = p
+	}
+
+	balance := l.Principal
+	for index := l.StartPeriod().Index(); index <= asOf.Index() && balance > 0; index++ {
+		balance += roundCents(balance * l.MonthlyRate())
+		payment := paid[index]
+		regular := payment.Regular
+		if l.RecurringExpenseID == nil {
+			regular = l.MinimumPayment
+		}
+		balance = math.Max(roundCents(balance-regular-payment.Extra), 0)
+	}
+	return balance
+}
+
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// AmortizationRow is one month of paying off a loan
+type AmortizationRow struct {
+	Year      int     `json:"year"`
+	Month     int     `json:"month"`
+	Payment   float64 `json:"payment"`
+	Interest  float64 `json:"interest"`
+	Principal float64 `json:"principal"`
+	Balance   float64 `json:"balance"`
+}
+
+func (r AmortizationRow) Period() Period {
+	return Period{Year: r.Year, Month: r.Month}
+}
+
+// Amortize schedules paying off a balance by a fixed monthly payment from a
+// period on. It reports false when the payment never pays the loan off,
+// either because it does not cover the interest or it would take more than
+// MaxPayoffMonths.
+func Amortize(balance, apr, payment float64, from Period) ([]AmortizationRow, bool) {
+	rate := apr / 100 / 12
+	var rows []AmortizationRow
+	for month := 0; balance > 0; month++ {
+		if month == MaxPayoffMonths {
+			return rows, false
+		}
+		interest := roundCents(balance * rate)
+		if payment <= interest {
+			return rows, false
+		}
+		paid := math.Min(payment, roundCents(balance+interest))
+		balance = roundCents(balance + interest - paid)
+		period := from.AddMonths(month)
+		rows = append(rows, AmortizationRow{
+			Year:      period.Year,
+			Month:     period.Month,
+			Payment:   paid,
+			Interest:  interest,
+			Principal: roundCents(paid - interest),
+			Balance:   balance,
+		})
+	}
+	return rows, true
+}
+
+// PayoffStrategy is the order extra money is put toward loans
+type PayoffStrategy string
+
+const (
+	// PayoffSnowball pays off the smallest balance first
+	PayoffSnowball PayoffStrategy = "snowball"
+	// PayoffAvalanche pays off the highest interest rate first
+	PayoffAvalanche PayoffStrategy = "avalanche"
+)
+
+func (s PayoffStrategy) IsValid() bool {
+	return s == PayoffSnowball || s == PayoffAvalanche
+}
+
+func (s PayoffStrategy) Label() string {
+	switch s {
+	case PayoffSnowball:
+		return "Snowball (smallest balance first)"
+	case PayoffAvalanche:
+		return "Avalanche (highest interest first)"
+	}
+	return string(s)
+}
+
+// LoanPayoff is when a loan is paid off under a plan
+type LoanPayoff struct {
+	LoanID   int64   `json:"loan_id"`
+	Name     string  `json:"name"`
+	Year     int     `json:"year"`
+	Month    int     `json:"month"`
+	Interest float64 `json:"interest"`
+}
+
+func (p LoanPayoff) Period() Period {
+	return Period{Year: p.Year, Month: p.Month}
+}
+
+// PayoffPlan is the outcome of paying off every loan by a strategy
+type PayoffPlan struct {
+	Strategy PayoffStrategy `json:"strategy"`
+	// Extra is what is paid each month on top of the regular repayments
+	Extra         float64 `json:"extra"`
+	Months        int     `json:"months"`
+	TotalInterest float64 `json:"total_interest"`
+	TotalPaid     float64 `json:"total_paid"`
+	// DebtFree is the month the last loan is paid off, nil when the plan
+	// never pays everything off
+	DebtFree *Period      `json:"debt_free"`
+	Payoffs  []LoanPayoff `json:"payoffs"`
+}
+
+// SimulatePayoff pays off loans from their current balances, starting in a
+// period. Each month every loan gets its regular payment, and the extra
+// amount, plus the regular payments of loans already paid off, goes to the
+// loan the strategy puts first.
+func SimulatePayoff(loans []Loan, strategy PayoffStrategy, extra float64, from Period) PayoffPlan {
+	plan := PayoffPlan{Strategy: strategy, Extra: extra}
+
+	type loanState struct {
+		loan     Loan
+		balance  float64
+		interest float64
+	}
+	var owing []*loanState
+	for _, l := range loans {
+		if l.Balance > 0 {
+			owing = append(owing, &loanState{loan: l, balance: l.Balance})
+		}
+	}
+	sort.SliceStable(owing, func(i, j int) bool {
+		a, b := owing[i], owing[j]
+		if strategy == PayoffAvalanche && a.loan.APR != b.loan.APR {
+			return a.loan.APR > b.loan.APR
+		}
+		return a.balance < b.balance
+	})
+
+	// The regular payments of loans paid off keep going to the others
+	budget := extra
+	for _, s := range owing {
+		budget += s.loan.MonthlyPayment
+	}
+
+	for month := 0; len(owing) > 0 && month < MaxPayoffMonths; month++ {
+		period := from.AddMonths(month)
+		available := budget
+		for _, s := range owing {
+			interest := roundCents(s.balance * s.loan.MonthlyRate())
+			s.balance += interest
+			s.interest += interest
+			plan.TotalInterest += interest
+		}
+		// Regular payments first, then whatever is left in strategy order
+		for _, s := range owing {
+			paid := math.Min(s.loan.MonthlyPayment, s.balance)
+			s.balance = roundCents(s.balance - paid)
+			available -= paid
+			plan.TotalPaid += paid
+		}
+		for _, s := range owing {
+			if available <= 0 {
+				break
+			}
+			paid := math.Min(available, s.balance)
+			s.balance = roundCents(s.balance - paid)
+			available -= paid
+			plan.TotalPaid += paid
+		}
+
+		var still []*loanState
+		for _, s := range owing {
+			if s.balance > 0 {
+				still = append(still, s)
+				continue
+			}
+			plan.Payoffs = append(plan.Payoffs, LoanPayoff{
+				LoanID:   s.loan.ID,
+				Name:     s.loan.Name,
+				Year:     period.Year,
+				Month:    period.Month,
+				Interest: roundCents(s.interest),
+			})
+		}
+		owing = still
+		plan.Months = month + 1
+		if len(owing) == 0 {
+			plan.DebtFree = &period
+		}
+	}
+	plan.TotalInterest = roundCents(plan.TotalInterest)
+	plan.TotalPaid = roundCents(plan.TotalPaid)
+	return plan
+}
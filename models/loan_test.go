@@ -0,0 +1,176 @@
+package models
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAmortize(t *testing.T) {
+	tests := []struct {
+		name      string
+		balance   float64
+		apr       float64
+		payment   float64
+		from      Period
+		wantOK    bool
+		wantRows  int
+		wantLast  float64
+		wantEnd   Period
+		wantTotal float64
+	}{
+		{
+			name:    "final payment is rounded down to what is owed",
+			balance: 1000, apr: 12, payment: 100, from: Period{Year: 2026, Month: 1},
+			wantOK: true, wantRows: 11, wantLast: 58.98, wantEnd: Period{Year: 2026, Month: 11}, wantTotal: 1058.98,
+		},
+		{
+			name:    "zero APR pays off the principal alone",
+			balance: 1000, apr: 0, payment: 300, from: Period{Year: 2026, Month: 11},
+			wantOK: true, wantRows: 4, wantLast: 100, wantEnd: Period{Year: 2027, Month: 2}, wantTotal: 1000,
+		},
+		{
+			name:    "payment below the interest never pays off",
+			balance: 10000, apr: 24, payment: 150, from: Period{Year: 2026, Month: 1},
+			wantOK: false,
+		},
+		{
+			name:    "payment equal to the interest never pays off",
+			balance: 10000, apr: 24, payment: 200, from: Period{Year: 2026, Month: 1},
+			wantOK: false,
+		},
+		{
+			name:    "longer than MaxPayoffMonths is never paid off",
+			balance: 1000, apr: 0, payment: 1, from: Period{Year: 2026, Month: 1},
+			wantOK: false, wantRows: MaxPayoffMonths,
+		},
+		{
+			name:    "nothing owed needs no schedule",
+			balance: 0, apr: 10, payment: 100, from: Period{Year: 2026, Month: 1},
+			wantOK: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, ok := Amortize(tt.balance, tt.apr, tt.payment, tt.from)
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if len(rows) != tt.wantRows {
+				t.Fatalf("got %d rows, want %d", len(rows), tt.wantRows)
+			}
+			if !ok || len(rows) == 0 {
+				return
+			}
+
+			last := rows[len(rows)-1]
+			if last.Payment != tt.wantLast {
+				t.Errorf("final payment = %.2f, want %.2f", last.Payment, tt.wantLast)
+			}
+			if last.Balance != 0 {
+				t.Errorf("final balance = %.2f, want 0", last.Balance)
+			}
+			if last.Period() != tt.wantEnd {
+				t.Errorf("paid off in %v, want %v", last.Period(), tt.wantEnd)
+			}
+
+			var total, principal float64
+			for _, r := range rows {
+				total += r.Payment
+				principal += r.Principal
+			}
+			if math.Abs(total-tt.wantTotal) > 0.005 {
+				t.Errorf("total paid = %.2f, want %.2f", total, tt.wantTotal)
+			}
+			if math.Abs(principal-tt.balance) > 0.005 {
+				t.Errorf("principal repaid = %.2f, want %.2f", principal, tt.balance)
+			}
+		})
+	}
+}
+
+func TestSimulatePayoff(t *testing.T) {
+	from := Period{Year: 2026, Month: 1}
+	card := Loan{ID: 1, Name: "Card", APR: 20, Balance: 3000, MonthlyPayment: 100}
+	car := Loan{ID: 2, Name: "Car", APR: 5, Balance: 1000, MonthlyPayment: 50}
+
+	tests := []struct {
+		name         string
+		loans        []Loan
+		strategy     PayoffStrategy
+		extra        float64
+		wantFirst    int64
+		wantMonths   int
+		wantDebtFree *Period
+		wantInterest float64
+	}{
+		{
+			name:  "snowball pays off the smallest balance first",
+			loans: []Loan{card, car}, strategy: PayoffSnowball, extra: 200,
+			wantFirst: car.ID,
+		},
+		{
+			name:  "avalanche pays off the highest rate first",
+			loans: []Loan{car, card}, strategy: PayoffAvalanche, extra: 200,
+			wantFirst: card.ID,
+		},
+		{
+			name:  "zero APR repays exactly the balance",
+			loans: []Loan{{ID: 3, Balance: 1000, MonthlyPayment: 100}}, strategy: PayoffSnowball,
+			wantFirst: 3, wantMonths: 10, wantDebtFree: &Period{Year: 2026, Month: 10}, wantInterest: 0,
+		},
+		{
+			name:  "payments below the interest never pay off",
+			loans: []Loan{{ID: 4, APR: 24, Balance: 10000, MonthlyPayment: 100}}, strategy: PayoffAvalanche,
+			wantMonths: MaxPayoffMonths,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := SimulatePayoff(tt.loans, tt.strategy, tt.extra, from)
+			if tt.wantFirst != 0 {
+				if len(plan.Payoffs) == 0 {
+					t.Fatal("no loan was paid off")
+				}
+				if plan.Payoffs[0].LoanID != tt.wantFirst {
+					t.Errorf("first paid off loan %d, want %d", plan.Payoffs[0].LoanID, tt.wantFirst)
+				}
+			}
+			if tt.wantMonths != 0 && plan.Months != tt.wantMonths {
+				t.Errorf("months = %d, want %d", plan.Months, tt.wantMonths)
+			}
+			if tt.wantFirst == 0 && plan.DebtFree != nil {
+				t.Errorf("debt free in %v, want never", *plan.DebtFree)
+			}
+			if tt.wantDebtFree != nil {
+				if plan.DebtFree == nil || *plan.DebtFree != *tt.wantDebtFree {
+					t.Errorf("debt free = %v, want %v", plan.DebtFree, *tt.wantDebtFree)
+				}
+				if plan.TotalInterest != tt.wantInterest {
+					t.Errorf("total interest = %.2f, want %.2f", plan.TotalInterest, tt.wantInterest)
+				}
+			}
+		})
+	}
+}
+
+func TestSimulatePayoffAvalancheCostsLessInterest(t *testing.T) {
+	loans := []Loan{
+		{ID: 1, APR: 20, Balance: 3000, MonthlyPayment: 100},
+		{ID: 2, APR: 5, Balance: 1000, MonthlyPayment: 50},
+	}
+	from := Period{Year: 2026, Month: 1}
+	snowball := SimulatePayoff(loans, PayoffSnowball, 200, from)
+	avalanche := SimulatePayoff(loans, PayoffAvalanche, 200, from)
+
+	if snowball.DebtFree == nil || avalanche.DebtFree == nil {
+		t.Fatal("both plans should pay everything off")
+	}
+	if avalanche.TotalInterest >= snowball.TotalInterest {
+		t.Errorf("avalanche interest %.2f, want less than snowball's %.2f", avalanche.TotalInterest, snowball.TotalInterest)
+	}
+	if math.Abs(snowball.TotalPaid-snowball.TotalInterest-4000) > 0.005 {
+		t.Errorf("snowball repaid %.2f of principal, want 4000", snowball.TotalPaid-snowball.TotalInterest)
+	}
+}
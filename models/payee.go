@@ -0,0 +1,77 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Payee is who expenses are paid to. An expense's description is matched to
+// a payee by the payee's name or one of its alias patterns.
+type Payee struct {
+	ID                int64     `json:"id"`
+	Name              string    `json:"name"`
+	DefaultCategoryID *int64    `json:"default_category_id"`
+	Aliases           []string  `json:"aliases"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+// PayeeTotal is what has been spent with a payee across all periods
+type PayeeTotal struct {
+	Payee Payee   `json:"payee"`
+	Total float64 `json:"total"`
+	Count int     `json:"count"`
+}
+
+// PayeePeriodTotal is what was spent with a payee in one period
+type PayeePeriodTotal struct {
+	Year  int     `json:"year"`
+	Month int     `json:"month"`
+	Total float64 `json:"total"`
+	Count int     `json:"count"`
+}
+
+func (t PayeePeriodTotal) Period() Period {
+	return Period{Year: t.Year, Month: t.Month}
+}
+
+// PayeeHistory is a payee's spending across periods, latest first
+type PayeeHistory struct {
+	PayeeTotal
+	Periods []PayeePeriodTotal `json:"periods"`
+}
+
+// Patterns returns the normalized patterns a description is matched
+// against: the payee's name and its aliases
+func (p Payee) Patterns() []string {
+	patterns := []string{NormalizeDescription(p.Name)}
+	for _, alias := range p.Aliases {
+		if pattern := NormalizeDescription(alias); pattern != "" {
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns
+}
+
+// AliasList returns the payee's aliases as comma-separated input
+func (p Payee) AliasList() string {
+	return strings.Join(p.Aliases, ", ")
+}
+
+// MatchPayee finds the payee a description belongs to. A pattern matches
+// when its words appear together in the normalized description, so "tesco"
+// matches "TESCO STORES 2231" but not "Tescoville". The longest matching
+// pattern wins.
+func MatchPayee(payees []Payee, description string) *Payee {
+	normalized := " " + NormalizeDescription(description) + " "
+	var match *Payee
+	longest := 0
+	for i := range payees {
+		for _, pattern := range payees[i].Patterns() {
+			if len(pattern) > longest && strings.Contains(normalized, " "+pattern+" ") {
+				match = &payees[i]
+				longest = len(pattern)
+			}
+		}
+	}
+	return match
+}
@@ -1,10 +1,32 @@
 package models
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 type Period struct {
-	Month int
-	Year  int
+	Month int `json:"month"`
+	Year  int `json:"year"`
+}
+
+// ParsePeriod parses a "YYYY-MM" value, as produced by <input type="month">
+func ParsePeriod(value string) (Period, bool) {
+	t, err := time.Parse("2006-01", value)
+	if err != nil {
+		return Period{}, false
+	}
+	return Period{Year: t.Year(), Month: int(t.Month())}, true
+}
+
+// String formats the period as "YYYY-MM"
+func (p Period) String() string {
+	return fmt.Sprintf("%04d-%02d", p.Year, p.Month)
+}
+
+// IsValid reports whether the period names a real month
+func (p Period) IsValid() bool {
+	return p.Month >= 1 && p.Month <= 12 && p.Year > 0
 }
 
 func (p Period) MonthName() string {
@@ -25,6 +47,17 @@ func (p Period) Next() Period {
 	return Period{Month: p.Month + 1, Year: p.Year}
 }
 
+// AddMonths returns the period n months later (or earlier when n is negative)
+func (p Period) AddMonths(n int) Period {
+	index := p.Year*12 + (p.Month - 1) + n
+	return Period{Year: index / 12, Month: index%12 + 1}
+}
+
+// Index returns a month count that orders periods chronologically
+func (p Period) Index() int {
+	return p.Year*12 + (p.Month - 1)
+}
+
 func (p Period) DaysInMonth() int {
 	return time.Date(p.Year, time.Month(p.Month)+1, 0, 0, 0, 0, 0, time.UTC).Day()
 }
@@ -0,0 +1,214 @@
+package models
+
+import "sort"
+
+// ProjectionMonths is how many periods a cash-flow projection looks ahead
+const ProjectionMonths = 12
+
+// ProjectionHistory is how many complete periods discretionary spending is
+// averaged over
+const ProjectionHistory = 6
+
+// CategoryAverage is the average one-time spending a month in a category
+type CategoryAverage struct {
+	CategoryID *int64  `json:"category_id"`
+	Name       string  `json:"name"`
+	Color      string  `json:"color,omitempty"`
+	Average    float64 `json:"average"`
+}
+
+// OneOff is a single purchase, or with a negative amount a windfall, added
+// to a projection
+type OneOff struct {
+	Year        int     `json:"year"`
+	Month       int     `json:"month"`
+	Description string  `json:"description"`
+	Amount      float64 `json:"amount"`
+}
+
+func (o OneOff) Period() Period {
+	return Period{Year: o.Year, Month: o.Month}
+}
+
+// ProjectionAdjustments are what-if changes to a projection. They are never
+// saved.
+type ProjectionAdjustments struct {
+	// Cancel lists recurring expenses to stop from the first projected month
+	Cancel  []int64  `json:"cancel"`
+	OneOffs []OneOff `json:"one_offs"`
+	// IncomeChange is added to every projected month's income
+	IncomeChange float64 `json:"income_change"`
+}
+
+// IsCancelled reports whether a recurring expense is cancelled
+func (a ProjectionAdjustments) IsCancelled(id int64) bool {
+	for _, c := range a.Cancel {
+		if c == id {
+			return true
+		}
+	}
+	return false
+}
+
+// IsZero reports whether there are no adjustments
+func (a ProjectionAdjustments) IsZero() bool {
+	return len(a.Cancel) == 0 && len(a.OneOffs) == 0 && a.IncomeChange == 0
+}
+
+// ProjectedMonth is one period of a cash-flow projection
+type ProjectedMonth struct {
+	Year          int     `json:"year"`
+	Month         int     `json:"month"`
+	Income        float64 `json:"income"`
+	Recurring     float64 `json:"recurring"`
+	Discretionary float64 `json:"discretionary"`
+	OneOffs       float64 `json:"one_offs"`
+	// Net is the month's income less its spending
+	Net float64 `json:"net"`
+	// Balance is what is expected to be in hand at the end of the month
+	Balance float64 `json:"balance"`
+}
+
+func (m ProjectedMonth) Period() Period {
+	return Period{Year: m.Year, Month: m.Month}
+}
+
+// IsNegative reports whether the month is expected to end below zero
+func (m ProjectedMonth) IsNegative() bool {
+	return m.Balance < 0
+}
+
+// Projection looks ahead at the household's cash flow month by month
+type Projection struct {
+	// Opening is what is expected to be in hand at the end of the current
+	// period, where the projection starts from; see OpeningBalance
+	Opening             float64               `json:"opening"`
+	OpeningFromAccounts bool                  `json:"opening_from_accounts"`
+	Recurring           []RecurringExpense    `json:"recurring"`
+	Discretionary       []CategoryAverage     `json:"discretionary"`
+	Adjustments         ProjectionAdjustments `json:"adjustments"`
+	Months              []ProjectedMonth      `json:"months"`
+	// FirstNegative is the first month expected to end below zero, if any
+	FirstNegative *Period `json:"first_negative"`
+}
+
+// AverageDiscretionary averages one-time spending a month by category over
+// a number of periods, largest first
+func AverageDiscretionary(expenses []Expense, months int) []CategoryAverage {
+	if months <= 0 {
+		return nil
+	}
+	byCategory := make(map[int64]*CategoryAverage)
+	var uncategorised *CategoryAverage
+	for _, e := range expenses {
+		if e.Type != ExpenseTypeOneTime {
+			continue
+		}
+		for _, a := range e.Allocations() {
+			if a.CategoryID == nil {
+				if uncategorised == nil {
+					uncategorised = &CategoryAverage{Name: "Uncategorised"}
+				}
+				uncategorised.Average += a.Amount
+				continue
+			}
+			avg, ok := byCategory[*a.CategoryID]
+			if !ok {
+				avg = &CategoryAverage{CategoryID: a.CategoryID}
+				if a.Category != nil {
+					avg.Name, avg.Color = a.Category.Name, a.Category.Color
+				}
+				byCategory[*a.CategoryID] = avg
+			}
+			avg.Average += a.Amount
+		}
+	}
+
+	var averages []CategoryAverage
+	for _, avg := range byCategory {
+		averages = append(averages, *avg)
+	}
+	if uncategorised != nil {
+		averages = append(averages, *uncategorised)
+	}
+	var kept []CategoryAverage
+	for _, avg := range averages {
+		avg.Average = roundCents(avg.Average / float64(months))
+		if avg.Average > 0 {
+			kept = append(kept, avg)
+		}
+	}
+	sort.Slice(kept, func(i, j int) bool {
+		if kept[i].Average != kept[j].Average {
+			return kept[i].Average > kept[j].Average
+		}
+		return kept[i].Name < kept[j].Name
+	})
+	return kept
+}
+
+// OpeningBalance is what is expected to be in hand when a forecast's period
+// ends, where a projection starts from. With accounts it is their closing
+// balances less the spending still to come, otherwise the forecast's
+// remaining income. Either way this period's savings toward goals are set
+// aside. The forecast should count every expense in full when there are
+// accounts, as their balances do.
+func OpeningBalance(f Forecast, accounts []AccountBalance) float64 {
+	if len(accounts) == 0 {
+		return f.ProjectedRemaining
+	}
+	opening := f.Spent - f.ProjectedSpending - f.SavedToGoals
+	for _, a := range accounts {
+		opening += a.Closing
+	}
+	return roundCents(opening)
+}
+
+// BuildProjection projects cash flow over the periods from start, one for
+// each income given. Each month the active recurring expenses not cancelled
+// and the average discretionary spending are taken from its income, along
+// with any one-offs falling in it.
+func BuildProjection(opening float64, fromAccounts bool, start Period, incomes []float64, recurring []RecurringExpense, discretionary []CategoryAverage, adjustments ProjectionAdjustments) Projection {
+	p := Projection{
+		Opening:             opening,
+		OpeningFromAccounts: fromAccounts,
+		Recurring:           recurring,
+		Discretionary:       discretionary,
+		Adjustments:         adjustments,
+	}
+
+	var scheduled, spending float64
+	for _, r := range recurring {
+		if r.IsActive && !adjustments.IsCancelled(r.ID) {
+			scheduled += r.Amount
+		}
+	}
+	for _, d := range discretionary {
+		spending += d.Average
+	}
+
+	balance := opening
+	for i, income := range incomes {
+		period := start.AddMonths(i)
+		m := ProjectedMonth{
+			Year:          period.Year,
+			Month:         period.Month,
+			Income:        income + adjustments.IncomeChange,
+			Recurring:     scheduled,
+			Discretionary: spending,
+		}
+		for _, o := range adjustments.OneOffs {
+			if o.Period() == period {
+				m.OneOffs += o.Amount
+			}
+		}
+		m.Net = roundCents(m.Income - m.Recurring - m.Discretionary - m.OneOffs)
+		balance = roundCents(balance + m.Net)
+		m.Balance = balance
+		if m.IsNegative() && p.FirstNegative == nil {
+			p.FirstNegative = &period
+		}
+		p.Months = append(p.Months, m)
+	}
+	return p
+}
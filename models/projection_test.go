@@ -0,0 +1,40 @@
+package models
+
+import "testing"
+
+func TestOpeningBalance(t *testing.T) {
+	// On the last day of April with £300 spent, £50 of recurring charges to
+	// come and £100 put toward goals
+	forecast := BuildForecast(Period{Year: 2026, Month: 4}, 30, 2000, 100,
+		[]Expense{{Type: ExpenseTypeOneTime, Amount: 300}},
+		[]PendingCharge{{RecurringExpenseID: 1, Amount: 50}}, nil)
+
+	tests := []struct {
+		name     string
+		accounts []AccountBalance
+		want     float64
+	}{
+		{
+			name: "without accounts, income less spending and goal savings",
+			want: 1550,
+		},
+		{
+			name:     "with accounts, balances less spending to come and goal savings",
+			accounts: []AccountBalance{{Closing: 1000}, {Closing: 250}},
+			want:     1100,
+		},
+		{
+			name:     "overdrawn accounts can open below zero",
+			accounts: []AccountBalance{{Closing: -200}, {Closing: 100}},
+			want:     -250,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := OpeningBalance(forecast, tt.accounts); got != tt.want {
+				t.Errorf("opening = %.2f, want %.2f", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"math"
+	"time"
+)
+
+// Reconciliation checks an account against a bank statement. Expenses are
+// ticked off as cleared until the cleared balance matches the statement.
+type Reconciliation struct {
+	ID               int64      `json:"id"`
+	AccountID        int64      `json:"account_id"`
+	StatementDate    time.Time  `json:"statement_date"`
+	StatementBalance float64    `json:"statement_balance"`
+	ClearedBalance   float64    `json:"cleared_balance"`
+	Difference       float64    `json:"difference"`
+	CompletedAt      *time.Time `json:"completed_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+// StatementPeriod is the period the statement closes in. Income and
+// transfers up to the end of it count as cleared.
+func (r Reconciliation) StatementPeriod() Period {
+	return Period{Year: r.StatementDate.Year(), Month: int(r.StatementDate.Month())}
+}
+
+// IsBalanced reports whether the cleared balance matches the statement to the penny
+func (r Reconciliation) IsBalanced() bool {
+	return math.Round(r.Difference*100) == 0
+}
+
+func (r Reconciliation) IsCompleted() bool {
+	return r.CompletedAt != nil
+}
+
+// AccountReconciliation is an account's reconciliation in progress, if any,
+// the expenses still to be reconciled and the statements already completed
+type AccountReconciliation struct {
+	Account  Account          `json:"account"`
+	Open     *Reconciliation  `json:"open"`
+	Expenses []Expense        `json:"expenses"`
+	History  []Reconciliation `json:"history"`
+}
+
+// IsReconciled reports whether the expense has been reconciled against a
+// statement. Reconciled expenses cannot be changed until unlocked.
+func (e Expense) IsReconciled() bool {
+	return e.ReconciledAt != nil
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// RecurringExpense is a template copied into each new period by InitializeMonth
+type RecurringExpense struct {
+	ID          int64     `json:"id"`
+	Description string    `json:"description"`
+	Amount      float64   `json:"amount"`
+	CategoryID  *int64    `json:"category_id"`
+	Category    *Category `json:"category,omitempty"`
+	IsActive    bool      `json:"is_active"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"math"
+	"sort"
+)
+
+// IsRefund reports whether the expense refunds another expense. Refunds have
+// a negative amount and count in the period the money came back.
+func (e Expense) IsRefund() bool {
+	return e.RefundOf != nil
+}
+
+// CanBeRefunded reports whether refunds can be recorded against the expense
+func (e Expense) CanBeRefunded() bool {
+	return !e.IsRefund() && e.Amount > 0
+}
+
+// IsReimbursable reports whether someone owes the money for the expense back
+func (e Expense) IsReimbursable() bool {
+	return e.ReimbursableFrom != ""
+}
+
+// Refundable returns how much of the expense has not been refunded yet
+func (e Expense) Refundable() float64 {
+	return math.Max(math.Round((e.Amount-e.Refunded)*100)/100, 0)
+}
+
+// IsReimbursed reports whether a reimbursable expense has been paid back in full
+func (e Expense) IsReimbursed() bool {
+	return e.IsReimbursable() && e.Refundable() == 0
+}
+
+// RefundCategoryID returns the category a refund of the expense is counted
+// against by default: its own, or for a split expense its largest line's
+func (e Expense) RefundCategoryID() *int64 {
+	var largest ExpenseSplit
+	for _, a := range e.Allocations() {
+		if a.Amount > largest.Amount {
+			largest = a
+		}
+	}
+	return largest.CategoryID
+}
+
+// ReimbursementGroup is the money one payer still owes back
+type ReimbursementGroup struct {
+	From     string    `json:"from"`
+	Total    float64   `json:"total"`
+	Expenses []Expense `json:"expenses"`
+}
+
+// GroupReimbursements groups reimbursable expenses that have not been paid
+// back in full by who owes them, largest amount owed first
+func GroupReimbursements(expenses []Expense) []ReimbursementGroup {
+	index := make(map[string]int)
+	var groups []ReimbursementGroup
+	for _, e := range expenses {
+		if !e.IsReimbursable() || e.IsReimbursed() {
+			continue
+		}
+		i, ok := index[e.ReimbursableFrom]
+		if !ok {
+			i = len(groups)
+			index[e.ReimbursableFrom] = i
+			groups = append(groups, ReimbursementGroup{From: e.ReimbursableFrom})
+		}
+		groups[i].Total += e.Refundable()
+		groups[i].Expenses = append(groups[i].Expenses, e)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		if groups[i].Total != groups[j].Total {
+			return groups[i].Total > groups[j].Total
+		}
+		return groups[i].From < groups[j].From
+	})
+	return groups
+}
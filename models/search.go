@@ -0,0 +1,93 @@
+package models
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const SearchPageSize = 25
+
+// ExpenseSearch holds the criteria for searching expenses across all periods.
+// Nil or zero fields are not filtered on.
+type ExpenseSearch struct {
+	Query      string
+	MinAmount  *float64
+	MaxAmount  *float64
+	CategoryID *int64
+	Type       ExpenseType
+	Tag        string
+	From       *Period
+	To         *Period
+	Page       int
+}
+
+type SearchResults struct {
+	Search   ExpenseSearch `json:"-"`
+	Expenses []Expense     `json:"expenses"`
+	Total    int           `json:"total"`
+	Sum      float64       `json:"sum"`
+}
+
+func (r SearchResults) TotalPages() int {
+	return (r.Total + SearchPageSize - 1) / SearchPageSize
+}
+
+func (r SearchResults) HasPrev() bool {
+	return r.Search.Page > 1
+}
+
+func (r SearchResults) HasNext() bool {
+	return r.Search.Page < r.TotalPages()
+}
+
+// FirstResult returns the 1-based position of the first result on the page
+func (r SearchResults) FirstResult() int {
+	if r.Total == 0 {
+		return 0
+	}
+	return (r.Search.Page-1)*SearchPageSize + 1
+}
+
+// LastResult returns the 1-based position of the last result on the page
+func (r SearchResults) LastResult() int {
+	return r.FirstResult() + len(r.Expenses) - 1
+}
+
+// ParseExpenseSearch reads search criteria from query parameters:
+// q, min, max, category_id, type, tag, from, to (YYYY-MM) and page
+func ParseExpenseSearch(values url.Values) ExpenseSearch {
+	search := ExpenseSearch{
+		Query: strings.TrimSpace(values.Get("q")),
+		Tag:   NormalizeTagName(values.Get("tag")),
+	}
+
+	if id, err := strconv.ParseInt(values.Get("category_id"), 10, 64); err == nil && id > 0 {
+		search.CategoryID = &id
+	}
+	if amount, err := strconv.ParseFloat(values.Get("min"), 64); err == nil {
+		search.MinAmount = &amount
+	}
+	if amount, err := strconv.ParseFloat(values.Get("max"), 64); err == nil {
+		search.MaxAmount = &amount
+	}
+
+	switch t := ExpenseType(values.Get("type")); t {
+	case ExpenseTypeRecurring, ExpenseTypeOneTime:
+		search.Type = t
+	}
+
+	if from, ok := ParsePeriod(values.Get("from")); ok {
+		search.From = &from
+	}
+	if to, ok := ParsePeriod(values.Get("to")); ok {
+		search.To = &to
+	}
+
+	search.Page, _ = strconv.Atoi(values.Get("page"))
+	if search.Page < 1 {
+		search.Page = 1
+	}
+
+	return search
+}
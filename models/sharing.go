@@ -0,0 +1,205 @@
+package models
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Person is someone who shares expenses within a household, optionally
+// linked to the user account they sign in with
+type Person struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	UserID    *int64    `json:"user_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ShareMethod is how a shared expense is divided between participants
+type ShareMethod string
+
+const (
+	ShareEqual   ShareMethod = "equal"
+	SharePercent ShareMethod = "percent"
+	ShareExact   ShareMethod = "exact"
+)
+
+func (m ShareMethod) IsValid() bool {
+	switch m {
+	case ShareEqual, SharePercent, ShareExact:
+		return true
+	}
+	return false
+}
+
+func (m ShareMethod) Label() string {
+	switch m {
+	case ShareEqual:
+		return "Equally"
+	case SharePercent:
+		return "By percentage"
+	case ShareExact:
+		return "By exact amounts"
+	}
+	return "Not shared"
+}
+
+// ExpenseShare is one participant's part of a shared expense. Value is the
+// percentage or exact amount entered; Amount is the resulting share.
+type ExpenseShare struct {
+	PersonID   int64   `json:"person_id"`
+	PersonName string  `json:"person_name"`
+	Value      float64 `json:"value"`
+	Amount     float64 `json:"amount"`
+}
+
+// IsShared reports whether the expense is divided between people
+func (e Expense) IsShared() bool {
+	return e.ShareMethod != "" && len(e.Shares) > 0
+}
+
+// ShareOf returns how much of the expense falls to a person: their share
+// when it is shared, or the whole amount when it is not
+func (e Expense) ShareOf(personID int64) float64 {
+	if !e.IsShared() {
+		return e.Amount
+	}
+	for _, s := range e.Shares {
+		if s.PersonID == personID {
+			return s.Amount
+		}
+	}
+	return 0
+}
+
+// PersonalExpenses scales each expense, and its split lines, down to the
+// person's share so a summary counts what they actually bear
+func PersonalExpenses(expenses []Expense, personID int64) []Expense {
+	personal := make([]Expense, 0, len(expenses))
+	for _, e := range expenses {
+		share := e.ShareOf(personID)
+		if share != e.Amount && e.IsSplit() && e.Amount != 0 {
+			ratio := share / e.Amount
+			splits := make([]ExpenseSplit, len(e.Splits))
+			for i, s := range e.Splits {
+				s.Amount *= ratio
+				splits[i] = s
+			}
+			e.Splits = splits
+		}
+		e.Amount = share
+		personal = append(personal, e)
+	}
+	return personal
+}
+
+// ComputeShares fills in each share's Amount from its Value so the shares
+// sum exactly to total. Rounding pennies go to the first participants, and
+// percentages a little over 100 are trimmed from the last.
+func ComputeShares(total float64, method ShareMethod, shares []ExpenseShare) []ExpenseShare {
+	if len(shares) == 0 {
+		return shares
+	}
+	totalCents := int64(math.Round(total * 100))
+	cents := make([]int64, len(shares))
+
+	switch method {
+	case ShareExact:
+		for i, s := range shares {
+			cents[i] = int64(math.Round(s.Value * 100))
+		}
+		return withCents(shares, cents)
+	case SharePercent:
+		for i, s := range shares {
+			cents[i] = int64(math.Floor(float64(totalCents) * s.Value / 100))
+		}
+	default:
+		for i := range shares {
+			cents[i] = totalCents / int64(len(shares))
+		}
+	}
+
+	var allocated int64
+	for _, c := range cents {
+		allocated += c
+	}
+	for i := 0; allocated < totalCents; i = (i + 1) % len(cents) {
+		cents[i]++
+		allocated++
+	}
+	for i := len(cents) - 1; allocated > totalCents && i >= 0; i-- {
+		excess := min(allocated-totalCents, cents[i])
+		cents[i] -= excess
+		allocated -= excess
+	}
+	return withCents(shares, cents)
+}
+
+func withCents(shares []ExpenseShare, cents []int64) []ExpenseShare {
+	out := make([]ExpenseShare, len(shares))
+	for i, s := range shares {
+		s.Amount = float64(cents[i]) / 100
+		out[i] = s
+	}
+	return out
+}
+
+// Settlement is a payment from one person to another that settles a balance
+type Settlement struct {
+	ID         int64     `json:"id"`
+	FromPerson Person    `json:"from"`
+	ToPerson   Person    `json:"to"`
+	Amount     float64   `json:"amount"`
+	Note       string    `json:"note"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Balance is where a person stands across every shared expense and
+// settlement: positive when they are owed money, negative when they owe it
+type Balance struct {
+	Person Person  `json:"person"`
+	Net    float64 `json:"net"`
+}
+
+// Debt is a payment that would settle part of the household's balances
+type Debt struct {
+	From   Person  `json:"from"`
+	To     Person  `json:"to"`
+	Amount float64 `json:"amount"`
+}
+
+// SettleUp suggests the payments that zero every balance, pairing the
+// largest debtor with the largest creditor until all are settled
+func SettleUp(balances []Balance) []Debt {
+	type position struct {
+		person Person
+		cents  int64
+	}
+	var owed, owing []position
+	for _, b := range balances {
+		cents := int64(math.Round(b.Net * 100))
+		switch {
+		case cents > 0:
+			owed = append(owed, position{b.Person, cents})
+		case cents < 0:
+			owing = append(owing, position{b.Person, -cents})
+		}
+	}
+	sort.SliceStable(owed, func(i, j int) bool { return owed[i].cents > owed[j].cents })
+	sort.SliceStable(owing, func(i, j int) bool { return owing[i].cents > owing[j].cents })
+
+	var debts []Debt
+	for i, j := 0, 0; i < len(owing) && j < len(owed); {
+		amount := min(owing[i].cents, owed[j].cents)
+		debts = append(debts, Debt{From: owing[i].person, To: owed[j].person, Amount: float64(amount) / 100})
+		owing[i].cents -= amount
+		owed[j].cents -= amount
+		if owing[i].cents == 0 {
+			i++
+		}
+		if owed[j].cents == 0 {
+			j++
+		}
+	}
+	return debts
+}
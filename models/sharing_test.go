@@ -0,0 +1,58 @@
+package models
+
+import "testing"
+
+func TestComputeShares(t *testing.T) {
+	people := func(values ...float64) []ExpenseShare {
+		shares := make([]ExpenseShare, len(values))
+		for i, v := range values {
+			shares[i] = ExpenseShare{PersonID: int64(i + 1), Value: v}
+		}
+		return shares
+	}
+
+	tests := []struct {
+		name   string
+		total  float64
+		method ShareMethod
+		shares []ExpenseShare
+		want   []float64
+	}{
+		{
+			name:  "equal shares give leftover pennies to the first",
+			total: 10, method: ShareEqual, shares: people(0, 0, 0),
+			want: []float64{3.34, 3.33, 3.33},
+		},
+		{
+			name:  "percentages just under 100 hand out the missing penny",
+			total: 100, method: SharePercent, shares: people(33.33, 33.33, 33.33),
+			want: []float64{33.34, 33.33, 33.33},
+		},
+		{
+			name:  "percentages over 100 are trimmed from the last share",
+			total: 100, method: SharePercent, shares: people(50, 50.01),
+			want: []float64{50, 50},
+		},
+		{
+			name:  "exact amounts are kept",
+			total: 12.5, method: ShareExact, shares: people(10, 2.5),
+			want: []float64{10, 2.5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ComputeShares(tt.total, tt.method, tt.shares)
+			var sum float64
+			for i, s := range got {
+				if s.Amount != tt.want[i] {
+					t.Errorf("share %d = %.2f, want %.2f", i, s.Amount, tt.want[i])
+				}
+				sum += s.Amount
+			}
+			if roundCents(sum) != tt.total {
+				t.Errorf("shares sum to %.2f, want %.2f", sum, tt.total)
+			}
+		})
+	}
+}
@@ -0,0 +1,275 @@
+package models
+
+import (
+	"math"
+	"sort"
+	"strconv"
+)
+
+// SubscriptionHistory is how many periods, up to the one reported on, are
+// compared to find price changes and untracked subscriptions
+const SubscriptionHistory = 12
+
+// SubscriptionLapse is how many periods, counting the one reported on, a
+// subscription can go without a charge before it is flagged as possibly
+// cancelled
+const SubscriptionLapse = 2
+
+// SubscriptionMinCharges is how many months in a row an expense must be
+// charged, once a month at about the same amount, to look like a
+// subscription that has no recurring template yet
+const SubscriptionMinCharges = 3
+
+// SubscriptionTolerance is how far, as a fraction of the latest charge, the
+// other monthly charges of an untracked subscription may be
+const SubscriptionTolerance = 0.1
+
+// PriceChange is a subscription's monthly charge changing between periods
+type PriceChange struct {
+	Year  int     `json:"year"`
+	Month int     `json:"month"`
+	From  float64 `json:"from"`
+	To    float64 `json:"to"`
+}
+
+func (c PriceChange) Period() Period {
+	return Period{Year: c.Year, Month: c.Month}
+}
+
+func (c PriceChange) IsIncrease() bool {
+	return c.To > c.From
+}
+
+// Subscription is a regular monthly charge, either a recurring template with
+// the expenses charged for it or a run of charges with no template yet
+type Subscription struct {
+	// RecurringExpenseID is nil for charges that look like a subscription
+	// but have no recurring template
+	RecurringExpenseID *int64    `json:"recurring_expense_id"`
+	Description        string    `json:"description"`
+	CategoryID         *int64    `json:"category_id"`
+	Category           *Category `json:"category,omitempty"`
+	MonthlyCost        float64   `json:"monthly_cost"`
+	AnnualCost         float64   `json:"annual_cost"`
+	// LastCharged is the latest period with a charge, nil when there is none
+	LastCharged *Period `json:"last_charged"`
+	// NextRenewal is the period after the last charge, nil when possibly cancelled
+	NextRenewal       *Period       `json:"next_renewal"`
+	PriceChanges      []PriceChange `json:"price_changes"`
+	PossiblyCancelled bool          `json:"possibly_cancelled"`
+}
+
+// LatestIncrease returns the most recent price change if it put the price up
+func (s Subscription) LatestIncrease() *PriceChange {
+	if len(s.PriceChanges) == 0 {
+		return nil
+	}
+	latest := s.PriceChanges[len(s.PriceChanges)-1]
+	if !latest.IsIncrease() {
+		return nil
+	}
+	return &latest
+}
+
+// SubscriptionReport is the household's subscriptions as of a period
+type SubscriptionReport struct {
+	Period        Period         `json:"period"`
+	Subscriptions []Subscription `json:"subscriptions"`
+	// Untracked are charges that look like subscriptions but have no
+	// recurring template
+	Untracked   []Subscription `json:"untracked"`
+	MonthlyCost float64        `json:"monthly_cost"`
+	AnnualCost  float64        `json:"annual_cost"`
+}
+
+// subscriptionCharges is what was charged for a subscription in each period
+type subscriptionCharges struct {
+	totals map[int]float64
+	counts map[int]int
+}
+
+func (c *subscriptionCharges) add(e Expense) {
+	if c.totals == nil {
+		c.totals = make(map[int]float64)
+		c.counts = make(map[int]int)
+	}
+	index := Period{Year: e.Year, Month: e.Month}.Index()
+	c.totals[index] += e.Amount
+	c.counts[index]++
+}
+
+// periods returns the indexes of the periods charged, oldest first
+func (c subscriptionCharges) periods() []int {
+	var indexes []int
+	for index := range c.totals {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+	return indexes
+}
+
+// periodAt is the period with the given Index
+func periodAt(index int) Period {
+	return Period{Year: index / 12, Month: index%12 + 1}
+}
+
+// fill sets a subscription's cost, charge history and renewal from its charges
+func (c subscriptionCharges) fill(s *Subscription, asOf Period) {
+	indexes := c.periods()
+	for i := 1; i < len(indexes); i++ {
+		from, to := c.totals[indexes[i-1]], c.totals[indexes[i]]
+		if math.Round(from*100) != math.Round(to*100) {
+			period := periodAt(indexes[i])
+			s.PriceChanges = append(s.PriceChanges, PriceChange{Year: period.Year, Month: period.Month, From: from, To: to})
+		}
+	}
+
+	if len(indexes) > 0 {
+		latest := indexes[len(indexes)-1]
+		last := periodAt(latest)
+		s.LastCharged = &last
+		s.MonthlyCost = c.totals[latest]
+	}
+	s.PossiblyCancelled = s.LastCharged == nil || asOf.Index()-s.LastCharged.Index() >= SubscriptionLapse
+	if !s.PossiblyCancelled {
+		next := s.LastCharged.Next()
+		s.NextRenewal = &next
+	}
+	s.AnnualCost = s.MonthlyCost * 12
+}
+
+// looksRegular reports whether the charges are once a month, at about the
+// same amount, for at least SubscriptionMinCharges months in a row up to
+// within SubscriptionLapse of asOf
+func (c subscriptionCharges) looksRegular(asOf Period) bool {
+	indexes := c.periods()
+	if len(indexes) < SubscriptionMinCharges {
+		return false
+	}
+	latest := indexes[len(indexes)-1]
+	if asOf.Index()-latest >= SubscriptionLapse {
+		return false
+	}
+	for i := 0; i < SubscriptionMinCharges; i++ {
+		index := latest - i
+		if c.counts[index] != 1 {
+			return false
+		}
+		if math.Abs(c.totals[index]-c.totals[latest]) > c.totals[latest]*SubscriptionTolerance {
+			return false
+		}
+	}
+	return true
+}
+
+// chargeKeys returns the keys an expense is matched to a subscription by:
+// its payee, if it has one, and its normalized description
+func chargeKeys(e Expense) []string {
+	keys := []string{"d:" + NormalizeDescription(e.Description)}
+	if e.PayeeID != nil {
+		keys = append(keys, "p:"+strconv.FormatInt(*e.PayeeID, 10))
+	}
+	return keys
+}
+
+// BuildSubscriptions reports on the active recurring templates as of a
+// period from the expenses charged up to it. Expenses created from a
+// template are its charges, as are other expenses with the same description
+// or payee as them, such as ones entered by hand. Expenses matching no
+// template that are charged like a subscription are reported as untracked.
+func BuildSubscriptions(asOf Period, templates []RecurringExpense, expenses []Expense) SubscriptionReport {
+	report := SubscriptionReport{Period: asOf}
+
+	charges := make(map[int64]*subscriptionCharges)
+	owners := make(map[string]int64)
+	for _, t := range templates {
+		if !t.IsActive {
+			continue
+		}
+		charges[t.ID] = &subscriptionCharges{}
+		owners["d:"+NormalizeDescription(t.Description)] = t.ID
+	}
+	for _, e := range expenses {
+		if e.RecurringExpenseID == nil || charges[*e.RecurringExpenseID] == nil {
+			continue
+		}
+		for _, key := range chargeKeys(e) {
+			if _, taken := owners[key]; !taken {
+				owners[key] = *e.RecurringExpenseID
+			}
+		}
+	}
+
+	untracked := make(map[string]*subscriptionCharges)
+	var untrackedOrder []string
+	examples := make(map[string]Expense)
+	for _, e := range expenses {
+		if e.RecurringExpenseID != nil {
+			if c := charges[*e.RecurringExpenseID]; c != nil {
+				c.add(e)
+			}
+			continue
+		}
+		keys := chargeKeys(e)
+		owned := false
+		for _, key := range keys {
+			if id, ok := owners[key]; ok {
+				charges[id].add(e)
+				owned = true
+				break
+			}
+		}
+		if owned {
+			continue
+		}
+		key := keys[len(keys)-1]
+		if untracked[key] == nil {
+			untracked[key] = &subscriptionCharges{}
+			untrackedOrder = append(untrackedOrder, key)
+		}
+		untracked[key].add(e)
+		examples[key] = e
+	}
+
+	for _, t := range templates {
+		if !t.IsActive {
+			continue
+		}
+		id := t.ID
+		s := Subscription{
+			RecurringExpenseID: &id,
+			Description:        t.Description,
+			CategoryID:         t.CategoryID,
+			Category:           t.Category,
+			MonthlyCost:        t.Amount,
+		}
+		charges[t.ID].fill(&s, asOf)
+		report.Subscriptions = append(report.Subscriptions, s)
+		if !s.PossiblyCancelled {
+			report.MonthlyCost += s.MonthlyCost
+		}
+	}
+	report.AnnualCost = report.MonthlyCost * 12
+
+	for _, key := range untrackedOrder {
+		if !untracked[key].looksRegular(asOf) {
+			continue
+		}
+		e := examples[key]
+		s := Subscription{Description: e.Description, CategoryID: e.CategoryID, Category: e.Category}
+		if e.PayeeName != "" {
+			s.Description = e.PayeeName
+		}
+		untracked[key].fill(&s, asOf)
+		report.Untracked = append(report.Untracked, s)
+	}
+
+	sort.SliceStable(report.Subscriptions, func(i, j int) bool {
+		a, b := report.Subscriptions[i], report.Subscriptions[j]
+		if a.PossiblyCancelled != b.PossiblyCancelled {
+			return !a.PossiblyCancelled
+		}
+		return a.MonthlyCost > b.MonthlyCost
+	})
+	return report
+}
@@ -3,64 +3,137 @@ package models
 import "sort"
 
 type Summary struct {
-	Income            float64
-	TotalExpenses     float64
-	Remaining         float64
-	SavingsRate       float64
-	DailyAllowance    float64
-	CategoryBreakdown []CategoryTotal
+	Income        float64 `json:"income"`
+	TotalExpenses float64 `json:"total_expenses"`
+	// Remaining is the income not spent; SavedToGoals is the part of it put
+	// toward savings goals and LeftOver the rest
+	Remaining         float64         `json:"remaining"`
+	SavedToGoals      float64         `json:"saved_to_goals"`
+	LeftOver          float64         `json:"left_over"`
+	SavingsRate       float64         `json:"savings_rate"`
+	DailyAllowance    float64         `json:"daily_allowance"`
+	CategoryBreakdown []CategoryTotal `json:"category_breakdown"`
 }
 
 type CategoryTotal struct {
-	Category Category
-	Total    float64
+	Category Category        `json:"category"`
+	Total    float64         `json:"total"`
+	Children []CategoryTotal `json:"children,omitempty"`
 }
 
-func CalculateSummary(income float64, expenses []Expense, daysInMonth int) Summary {
+// HasBudget reports whether a budget is set for the category
+func (ct CategoryTotal) HasBudget() bool {
+	return ct.Category.Budget != nil && *ct.Category.Budget > 0
+}
+
+// BudgetUsed returns the share of the budget spent as a percentage
+func (ct CategoryTotal) BudgetUsed() float64 {
+	if !ct.HasBudget() {
+		return 0
+	}
+	return (ct.Total / *ct.Category.Budget) * 100
+}
+
+// OverBudget reports whether spending has exceeded the budget
+func (ct CategoryTotal) OverBudget() bool {
+	return ct.HasBudget() && ct.Total > *ct.Category.Budget
+}
+
+// CalculateSummary totals a period's spending against its income. Money
+// saved toward goals is not spending, but is not available to spend either,
+// so the daily allowance comes from what is left over after it.
+func CalculateSummary(income, savedToGoals float64, expenses []Expense, categories []Category, daysInMonth int) Summary {
 	var totalExpenses float64
 	categoryTotals := make(map[int64]float64)
 	categoryMap := make(map[int64]Category)
 
+	for _, c := range categories {
+		categoryMap[c.ID] = c
+	}
+
 	for _, e := range expenses {
 		totalExpenses += e.Amount
-		if e.CategoryID != nil {
-			categoryTotals[*e.CategoryID] += e.Amount
-			if e.Category != nil {
-				categoryMap[*e.CategoryID] = *e.Category
+		for _, a := range e.Allocations() {
+			if a.CategoryID == nil {
+				continue
+			}
+			categoryTotals[*a.CategoryID] += a.Amount
+			if _, ok := categoryMap[*a.CategoryID]; !ok && a.Category != nil {
+				categoryMap[*a.CategoryID] = *a.Category
 			}
 		}
 	}
 
 	remaining := income - totalExpenses
+	leftOver := remaining - savedToGoals
 	savingsRate := 0.0
 	if income > 0 {
 		savingsRate = (remaining / income) * 100
 	}
 	dailyAllowance := 0.0
 	if daysInMonth > 0 {
-		dailyAllowance = remaining / float64(daysInMonth)
+		dailyAllowance = leftOver / float64(daysInMonth)
 	}
 	if dailyAllowance < 0 {
 		dailyAllowance = 0
 	}
 
-	breakdown := make([]CategoryTotal, 0, len(categoryTotals))
-	for catID, total := range categoryTotals {
-		breakdown = append(breakdown, CategoryTotal{
-			Category: categoryMap[catID],
-			Total:    total,
-		})
-	}
-	sort.Slice(breakdown, func(i, j int) bool {
-		return breakdown[i].Total > breakdown[j].Total
-	})
-
 	return Summary{
 		Income:            income,
 		TotalExpenses:     totalExpenses,
 		Remaining:         remaining,
+		SavedToGoals:      savedToGoals,
+		LeftOver:          leftOver,
 		SavingsRate:       savingsRate,
 		DailyAllowance:    dailyAllowance,
-		CategoryBreakdown: breakdown,
+		CategoryBreakdown: buildBreakdown(categoryTotals, categoryMap),
 	}
 }
+
+// buildBreakdown rolls subcategory totals up into their parents. Categories
+// with neither spending nor a budget are left out.
+func buildBreakdown(categoryTotals map[int64]float64, categoryMap map[int64]Category) []CategoryTotal {
+	children := make(map[int64][]CategoryTotal)
+	for id, cat := range categoryMap {
+		if cat.ParentID == nil {
+			continue
+		}
+		if _, ok := categoryMap[*cat.ParentID]; !ok {
+			continue
+		}
+		total := categoryTotals[id]
+		if total == 0 && cat.Budget == nil {
+			continue
+		}
+		children[*cat.ParentID] = append(children[*cat.ParentID], CategoryTotal{Category: cat, Total: total})
+	}
+
+	breakdown := make([]CategoryTotal, 0, len(categoryMap))
+	for id, cat := range categoryMap {
+		if cat.ParentID != nil {
+			if _, ok := categoryMap[*cat.ParentID]; ok {
+				continue
+			}
+		}
+		ct := CategoryTotal{Category: cat, Total: categoryTotals[id], Children: children[id]}
+		for _, child := range ct.Children {
+			ct.Total += child.Total
+		}
+		if ct.Total == 0 && cat.Budget == nil && len(ct.Children) == 0 {
+			continue
+		}
+		sortCategoryTotals(ct.Children)
+		breakdown = append(breakdown, ct)
+	}
+	sortCategoryTotals(breakdown)
+	return breakdown
+}
+
+func sortCategoryTotals(totals []CategoryTotal) {
+	sort.Slice(totals, func(i, j int) bool {
+		if totals[i].Total != totals[j].Total {
+			return totals[i].Total > totals[j].Total
+		}
+		return totals[i].Category.Name < totals[j].Category.Name
+	})
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const maxTagLength = 50
+
+type Tag struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type TagTotal struct {
+	Tag   Tag     `json:"tag"`
+	Total float64 `json:"total"`
+	Count int     `json:"count"`
+}
+
+// NormalizeTagName lowercases a tag and joins words with hyphens,
+// so "Holiday 2026" and "holiday-2026" are the same tag
+func NormalizeTagName(name string) string {
+	name = strings.Join(strings.Fields(strings.ToLower(name)), "-")
+	if utf8.RuneCountInString(name) > maxTagLength {
+		name = string([]rune(name)[:maxTagLength])
+	}
+	return name
+}
+
+// ParseTags splits comma-separated input into unique, normalized tag names.
+// The result is never nil, so blank input clears an expense's tags.
+func ParseTags(input string) []string {
+	seen := make(map[string]bool)
+	names := []string{}
+	for _, part := range strings.Split(input, ",") {
+		name := NormalizeTagName(part)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names
+}
@@ -17,3 +17,78 @@ const handleDateSelectChange = () => {
   const expensePeriodElement = window.document.getElementById("expense-period");
   expensePeriodElement.innerHTML = month[0];
 };
+
+// Press "/" anywhere outside a form field to search all expenses
+document.addEventListener("keydown", (event) => {
+  if (event.key !== "/" || event.ctrlKey || event.metaKey || event.altKey) {
+    return;
+  }
+  const target = event.target;
+  if (
+    target.isContentEditable ||
+    ["INPUT", "TEXTAREA", "SELECT"].includes(target.tagName)
+  ) {
+    return;
+  }
+  event.preventDefault();
+  const searchInput = document.getElementById("search-query");
+  if (searchInput) {
+    searchInput.focus();
+    searchInput.select();
+  } else {
+    window.location.href = "/search";
+  }
+});
+
+// Error fragments that retarget themselves (e.g. a rejected CSRF token)
+// are meant to be shown, so swap them in despite the error status
+document.addEventListener("htmx:beforeSwap", (event) => {
+  if (event.detail.isError && event.detail.xhr.getResponseHeader("HX-Retarget")) {
+    event.detail.shouldSwap = true;
+    event.detail.isError = false;
+  }
+});
+
+// Live updates: each [sse-connect] element streams change events from the
+// server, and elements inside it with an "sse:<event>" hx-trigger refresh
+// when that event arrives
+const sseTriggers = (element) =>
+  (element.getAttribute("hx-trigger") || "")
+    .split(",")
+    .map((trigger) => trigger.trim())
+    .filter((trigger) => trigger.startsWith("sse:"))
+    .map((trigger) => trigger.slice("sse:".length));
+
+const listenForSSE = (container) => {
+  const source = container.sseSource;
+  const elements = [container, ...container.querySelectorAll("[hx-trigger]")];
+  for (const name of elements.flatMap(sseTriggers)) {
+    if (source.sseEvents.has(name)) {
+      continue;
+    }
+    source.sseEvents.add(name);
+    source.addEventListener(name, () => {
+      if (!container.isConnected) {
+        source.close();
+        return;
+      }
+      for (const element of container.querySelectorAll("[hx-trigger]")) {
+        if (sseTriggers(element).includes(name)) {
+          htmx.trigger(element, "sse:" + name);
+        }
+      }
+    });
+  }
+};
+
+htmx.onLoad((content) => {
+  const root = content.closest("[sse-connect]");
+  const containers = content.querySelectorAll("[sse-connect]");
+  for (const container of root ? [root, ...containers] : containers) {
+    if (!container.sseSource) {
+      container.sseSource = new EventSource(container.getAttribute("sse-connect"));
+      container.sseSource.sseEvents = new Set();
+    }
+    listenForSSE(container);
+  }
+});